@@ -0,0 +1,278 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/audit"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// Audit event page sizes
+const (
+	defaultAuditEventLimit = 100
+	maxAuditEventLimit     = 1000
+	auditExportBatchSize   = 500
+)
+
+// Audit event export formats
+const (
+	auditFormatJSON   = "json"
+	auditFormatCSV    = "csv"
+	auditFormatNDJSON = "ndjson"
+)
+
+var auditCSVHeader = []string{
+	"id", "time", "correlationId", "clientIp", "userAgent", "userId", "method", "path",
+	"statusCode", "responseTime", "responseSize", "body", "headers", "errors",
+}
+
+// AuditEventsResponse is a page of audit events.
+type AuditEventsResponse struct {
+	Events     []audit.AuditEvent `json:"events"`
+	NextCursor string             `json:"nextCursor,omitempty"`
+}
+
+// AuditAPI implements the audit event query and export API actions.
+type AuditAPI struct {
+	events *audit.Events
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewAuditAPI returns a new AuditAPI instance.
+func NewAuditAPI(events *audit.Events, logger logrus.FieldLogger, errorHandler emperror.Handler) *AuditAPI {
+	return &AuditAPI{
+		events:       events,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// ListEvents returns the audit events of the organization.
+// Events can be filtered by the userId, from, to (RFC3339), method, pathPrefix, statusCode and correlationId query parameters.
+// JSON responses are paginated with the cursor and limit parameters,
+// CSV and NDJSON exports (format query parameter) contain every matching event.
+func (a *AuditAPI) ListEvents(c *gin.Context) {
+	filter, ok := a.parseEventFilter(c)
+	if !ok {
+		return
+	}
+
+	switch format := c.DefaultQuery("format", auditFormatJSON); format {
+	case auditFormatJSON:
+		a.listEvents(c, filter)
+
+	case auditFormatCSV, auditFormatNDJSON:
+		a.exportEvents(c, filter, format)
+
+	default:
+		a.replyBadRequest(c, "invalid format", fmt.Sprintf("unsupported format %q, use json, csv or ndjson", format))
+	}
+}
+
+func (a *AuditAPI) listEvents(c *gin.Context, filter audit.EventFilter) {
+	var cursor uint64
+	if raw := c.Query("cursor"); raw != "" {
+		var err error
+		cursor, err = strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			a.replyBadRequest(c, "invalid cursor", err.Error())
+			return
+		}
+	}
+
+	limit := defaultAuditEventLimit
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxAuditEventLimit {
+			a.replyBadRequest(c, "invalid limit", fmt.Sprintf("limit must be between 1 and %d", maxAuditEventLimit))
+			return
+		}
+	}
+
+	events, err := a.events.Find(filter, uint(cursor), limit)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	response := AuditEventsResponse{
+		Events: events,
+	}
+
+	if len(events) == limit {
+		response.NextCursor = strconv.FormatUint(uint64(events[len(events)-1].ID), 10)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (a *AuditAPI) exportEvents(c *gin.Context, filter audit.EventFilter, format string) {
+	fileName := fmt.Sprintf("audit-%d-%s.%s", filter.OrganizationID, time.Now().UTC().Format("20060102150405"), format)
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+
+	var write func(event audit.AuditEvent) error
+	var flush func() error
+
+	switch format {
+	case auditFormatCSV:
+		c.Header("Content-Type", "text/csv")
+
+		writer := csv.NewWriter(c.Writer)
+		if err := writer.Write(auditCSVHeader); err != nil {
+			a.handleError(c, err)
+			return
+		}
+
+		write = func(event audit.AuditEvent) error {
+			return writer.Write(auditEventCSVRecord(event))
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+
+	case auditFormatNDJSON:
+		c.Header("Content-Type", "application/x-ndjson")
+
+		encoder := json.NewEncoder(c.Writer)
+
+		write = func(event audit.AuditEvent) error {
+			return encoder.Encode(event)
+		}
+		flush = func() error {
+			return nil
+		}
+	}
+
+	c.Status(http.StatusOK)
+
+	err := a.events.Each(filter, auditExportBatchSize, write)
+	if err == nil {
+		err = flush()
+	}
+
+	// The response has already been started, errors can only be logged
+	if err != nil {
+		a.errorHandler.Handle(emperror.Wrap(err, "failed to export audit events"))
+	}
+}
+
+func auditEventCSVRecord(event audit.AuditEvent) []string {
+	stringValue := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+
+		return *s
+	}
+
+	return []string{
+		strconv.FormatUint(uint64(event.ID), 10),
+		event.Time.UTC().Format(time.RFC3339),
+		event.CorrelationID,
+		event.ClientIP,
+		event.UserAgent,
+		strconv.FormatUint(uint64(event.UserID), 10),
+		event.Method,
+		event.Path,
+		strconv.Itoa(event.StatusCode),
+		strconv.Itoa(event.ResponseTime),
+		strconv.Itoa(event.ResponseSize),
+		stringValue(event.Body),
+		event.Headers,
+		stringValue(event.Errors),
+	}
+}
+
+func (a *AuditAPI) parseEventFilter(c *gin.Context) (audit.EventFilter, bool) {
+	filter := audit.EventFilter{
+		OrganizationID: auth.GetCurrentOrganization(c.Request).ID,
+		Method:         c.Query("method"),
+		PathPrefix:     c.Query("pathPrefix"),
+		CorrelationID:  c.Query("correlationId"),
+	}
+
+	if raw := c.Query("userId"); raw != "" {
+		userID, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			a.replyBadRequest(c, "invalid userId", err.Error())
+			return filter, false
+		}
+
+		filter.UserID = uint(userID)
+	}
+
+	if raw := c.Query("statusCode"); raw != "" {
+		statusCode, err := strconv.Atoi(raw)
+		if err != nil {
+			a.replyBadRequest(c, "invalid statusCode", err.Error())
+			return filter, false
+		}
+
+		filter.StatusCode = statusCode
+	}
+
+	for param, value := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			a.replyBadRequest(c, fmt.Sprintf("invalid %s", param), err.Error())
+			return filter, false
+		}
+
+		*value = t
+	}
+
+	return filter, true
+}
+
+func (a *AuditAPI) replyBadRequest(c *gin.Context, message string, err string) {
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: message,
+		Error:   err,
+	})
+}
+
+func (a *AuditAPI) handleError(c *gin.Context, err error) {
+	a.errorHandler.Handle(err)
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error querying audit events",
+		Error:   err.Error(),
+	})
+}
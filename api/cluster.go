@@ -54,6 +54,7 @@ type ClusterAPI struct {
 	clusterManager      *cluster.Manager
 	clusterGetter       common.ClusterGetter
 	clusterGroupManager *intClusterGroup.Manager
+	labelManager        *cluster.LabelManager
 	externalBaseURL     string
 	workflowClient      client.Client
 
@@ -77,6 +78,7 @@ func NewClusterAPI(
 	clusterGetter common.ClusterGetter,
 	workflowClient client.Client,
 	clusterGroupManager *intClusterGroup.Manager,
+	labelManager *cluster.LabelManager,
 	logger logrus.FieldLogger,
 	errorHandler emperror.Handler,
 	externalBaseURL string,
@@ -89,6 +91,7 @@ func NewClusterAPI(
 		clusterGetter:       clusterGetter,
 		workflowClient:      workflowClient,
 		clusterGroupManager: clusterGroupManager,
+		labelManager:        labelManager,
 		externalBaseURL:     externalBaseURL,
 		logger:              logger,
 		errorHandler:        errorHandler,
@@ -192,6 +195,27 @@ func (a *ClusterAPI) GetClusters(c *gin.Context) {
 		return
 	}
 
+	clusters, clusterLabels, err := a.labelManager.SelectClusters(context.Background(), clusters, c.Query("labelSelector"))
+	if isInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid label selector",
+			Error:   err.Error(),
+		})
+
+		return
+	} else if err != nil {
+		a.errorHandler.Handle(err)
+
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error listing clusters",
+			Error:   err.Error(),
+		})
+
+		return
+	}
+
 	response := make([]pkgCluster.GetClusterStatusResponse, 0)
 
 	for _, c := range clusters {
@@ -202,6 +226,7 @@ func (a *ClusterAPI) GetClusters(c *gin.Context) {
 			// TODO we want skip or return error?
 			logger.Errorf("get cluster status failed: %s", err.Error())
 		} else {
+			status.Labels = clusterLabels[c.GetID()]
 			response = append(response, *status)
 		}
 	}
@@ -17,6 +17,7 @@ package pke
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/banzaicloud/bank-vaults/pkg/vault"
 	"github.com/goph/emperror"
@@ -40,31 +41,56 @@ type leaderSecret struct {
 
 // VaultLeaderRepository implements a LeaderRepository over Vault secret store
 type VaultLeaderRepository struct {
+	vault *lazyVaultLogical
+}
+
+// lazyVaultLogical creates the Vault client on first use, so that Vault is only required when PKE clusters are used.
+type lazyVaultLogical struct {
+	once    sync.Once
 	logical *vaultapi.Logical
+	err     error
 }
 
-// NewVaultLeaderRepository returns a new VaultLeaderRepository
-func NewVaultLeaderRepository() (VaultLeaderRepository, error) {
-	role := "pipeline"
-	client, err := vault.NewClient(role)
-	if err != nil {
-		return VaultLeaderRepository{}, emperror.Wrap(err, "failed to create new Vault client")
+func (l *lazyVaultLogical) get() (*vaultapi.Logical, error) {
+	l.once.Do(func() {
+		role := "pipeline"
+		client, err := vault.NewClient(role)
+		if err != nil {
+			l.err = emperror.Wrap(err, "failed to create new Vault client")
+			return
+		}
+
+		l.logical = client.Vault().Logical()
+	})
+
+	return l.logical, l.err
+}
+
+// NewVaultLeaderRepository returns a new VaultLeaderRepository.
+// The Vault client is created when the repository is first used.
+func NewVaultLeaderRepository() VaultLeaderRepository {
+	return VaultLeaderRepository{
+		vault: &lazyVaultLogical{},
 	}
-	return NewVaultLeaderRepositoryFromClient(client), nil
 }
 
 // NewVaultLeaderRepositoryFromClient returns a new VaultLeaderRepository
 func NewVaultLeaderRepositoryFromClient(client *vault.Client) VaultLeaderRepository {
 	return VaultLeaderRepository{
-		logical: client.Vault().Logical(),
+		vault: &lazyVaultLogical{logical: client.Vault().Logical()},
 	}
 }
 
 // GetLeader returns information about the leader of the specified cluster
 func (r VaultLeaderRepository) GetLeader(organizationID, clusterID uint) (leaderInfo LeaderInfo, err error) {
+	logical, err := r.vault.get()
+	if err != nil {
+		return
+	}
+
 	path := getSecretPath(organizationID, clusterID)
 
-	secret, err := r.logical.Read(path)
+	secret, err := logical.Read(path)
 	if err = emperror.Wrap(err, "failed to read secret"); err != nil {
 		return
 	}
@@ -91,6 +117,11 @@ func (r VaultLeaderRepository) GetLeader(organizationID, clusterID uint) (leader
 
 // SetLeader writes the given leader info for the specified cluster to the repository if it's not set yet
 func (r VaultLeaderRepository) SetLeader(organizationID, clusterID uint, leaderInfo LeaderInfo) error {
+	logical, err := r.vault.get()
+	if err != nil {
+		return err
+	}
+
 	path := getSecretPath(organizationID, clusterID)
 	ls := leaderSecret{
 		Data: leaderSecretData{
@@ -107,7 +138,7 @@ func (r VaultLeaderRepository) SetLeader(organizationID, clusterID uint, leaderI
 		return emperror.Wrap(err, "failed to decode leader secret")
 	}
 
-	_, err := r.logical.Write(path, data)
+	_, err = logical.Write(path, data)
 	if err != nil && strings.Contains(err.Error(), "* check-and-set parameter did not match the current version") {
 		return emperror.Wrap(leaderSetError{}, "failed to write leader secret")
 	}
@@ -115,8 +146,13 @@ func (r VaultLeaderRepository) SetLeader(organizationID, clusterID uint, leaderI
 }
 
 func (r VaultLeaderRepository) DeleteLeader(organizationID, clusterID uint) error {
+	logical, err := r.vault.get()
+	if err != nil {
+		return err
+	}
+
 	path := getMetadataPath(organizationID, clusterID)
-	secret, err := r.logical.Delete(path)
+	secret, err := logical.Delete(path)
 
 	if secret == nil && err == nil {
 		return leaderNotFound{
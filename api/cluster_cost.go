@@ -0,0 +1,208 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/api/common"
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// defaultCostPeriod is the length of the cost query time range if its start is not specified.
+const defaultCostPeriod = 7 * 24 * time.Hour
+
+// ClusterCostAPI implements the cluster cost estimation and accounting API actions.
+type ClusterCostAPI struct {
+	clusterGetter common.ClusterGetter
+	costManager   *cluster.CostManager
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewClusterCostAPI returns a new ClusterCostAPI instance.
+func NewClusterCostAPI(
+	clusterGetter common.ClusterGetter,
+	costManager *cluster.CostManager,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *ClusterCostAPI {
+	return &ClusterCostAPI{
+		clusterGetter: clusterGetter,
+		costManager:   costManager,
+		logger:        logger,
+		errorHandler:  errorHandler,
+	}
+}
+
+// EstimateCreateCost returns the estimated hourly and monthly cost of a cluster create request.
+func (a *ClusterCostAPI) EstimateCreateCost(c *gin.Context) {
+	var request pkgCluster.CreateClusterRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	estimate, err := a.costManager.EstimateCreate(ctx, &request)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, estimate)
+}
+
+// EstimateUpdateCost returns the estimated hourly and monthly cost of a cluster after an update request.
+func (a *ClusterCostAPI) EstimateUpdateCost(c *gin.Context) {
+	var request pkgCluster.UpdateClusterRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	estimate, err := a.costManager.EstimateUpdate(ctx, commonCluster, &request)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, estimate)
+}
+
+// GetClusterCost returns the accumulated running cost of a cluster broken down by node pools.
+func (a *ClusterCostAPI) GetClusterCost(c *gin.Context) {
+	from, to, ok := a.parseCostTimeRange(c)
+	if !ok {
+		return
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	cost, err := a.costManager.GetClusterCost(ctx, commonCluster, from, to)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cost)
+}
+
+// GetOrganizationCost returns the accumulated running cost of the clusters of an organization
+// broken down by clusters or by the values of the cluster label given in the groupByLabel query parameter.
+func (a *ClusterCostAPI) GetOrganizationCost(c *gin.Context) {
+	from, to, ok := a.parseCostTimeRange(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	cost, err := a.costManager.GetOrganizationCost(ctx, auth.GetCurrentOrganization(c.Request).ID, from, to, c.Query("groupByLabel"))
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cost)
+}
+
+// parseCostTimeRange parses the from and to (RFC3339) query parameters.
+// The range defaults to the last week.
+func (a *ClusterCostAPI) parseCostTimeRange(c *gin.Context) (time.Time, time.Time, bool) {
+	var from, to time.Time
+
+	for param, value := range map[string]*time.Time{"from": &from, "to": &to} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Query parameter '%s' must be an RFC3339 timestamp", param),
+				Error:   err.Error(),
+			})
+			return from, to, false
+		}
+
+		*value = t
+	}
+
+	if to.IsZero() {
+		to = time.Now()
+	}
+
+	if from.IsZero() {
+		from = to.Add(-defaultCostPeriod)
+	}
+
+	return from, to, true
+}
+
+func (a *ClusterCostAPI) handleError(c *gin.Context, err error) {
+	if isInvalid(err) {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	a.errorHandler.Handle(err)
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error processing cluster cost",
+		Error:   err.Error(),
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/mitchellh/mapstructure"
 
@@ -93,6 +94,11 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 	orgID := auth.GetCurrentOrganization(c.Request).ID
 	userID := auth.GetCurrentUser(c.Request).ID
 
+	dryRun, ok := dryRunQuery(c)
+	if !ok {
+		return
+	}
+
 	var requestBody map[string]interface{}
 	if err := c.ShouldBindJSON(&requestBody); err != nil {
 		a.errorHandler.Handle(err)
@@ -118,6 +124,17 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 			createClusterRequest.SecretId = secret.GenerateSecretIDFromName(createClusterRequest.SecretName)
 		}
 
+		if dryRun {
+			plan, err := a.planCluster(ctx, &createClusterRequest, orgID, userID, createClusterRequest.PostHooks)
+			if err != nil {
+				c.JSON(err.Code, err)
+				return
+			}
+
+			c.JSON(http.StatusOK, plan)
+			return
+		}
+
 		commonCluster, err := a.createCluster(ctx, &createClusterRequest, orgID, userID, createClusterRequest.PostHooks)
 		if err != nil {
 			c.JSON(err.Code, err)
@@ -146,6 +163,23 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 		return
 	}
 
+	if dryRun {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: fmt.Sprintf("dry run is not supported for cluster type: %s", createClusterRequestBase.Type),
+		})
+		return
+	}
+
+	if err := pkgCluster.ValidateLabels(createClusterRequestBase.Labels); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	var cluster intCluster.Cluster
 
 	switch createClusterRequestBase.Type {
@@ -193,12 +227,41 @@ func (a *ClusterAPI) CreateCluster(c *gin.Context) {
 		return
 	}
 
+	if len(createClusterRequestBase.Labels) > 0 {
+		err := a.labelManager.SetLabels(ctx, cluster.GetID(), createClusterRequestBase.Labels)
+		if err != nil {
+			// the cluster is already being created, labels can be set again later
+			a.errorHandler.Handle(emperror.WrapWith(err, "failed to save cluster labels", "clusterID", cluster.GetID()))
+		}
+	}
+
 	c.JSON(http.StatusAccepted, pkgCluster.CreateClusterResponse{
 		Name:       cluster.GetName(),
 		ResourceID: cluster.GetID(),
 	})
 }
 
+// dryRunQuery parses the optional dryRun query parameter or responds with an error.
+func dryRunQuery(c *gin.Context) (bool, bool) {
+	value := c.Query("dryRun")
+	if value == "" {
+		return false, true
+	}
+
+	dryRun, err := strconv.ParseBool(value)
+	if err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Query parameter 'dryRun' must be a boolean value",
+			Error:   err.Error(),
+		})
+
+		return false, false
+	}
+
+	return dryRun, true
+}
+
 // createCluster creates a K8S cluster in the cloud.
 func (a *ClusterAPI) createCluster(
 	ctx context.Context,
@@ -213,6 +276,119 @@ func (a *ClusterAPI) createCluster(
 		"cluster":      createClusterRequest.Name,
 	})
 
+	createClusterRequest, commonCluster, errResp := a.prepareCommonCluster(createClusterRequest, organizationID, userID, logger)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	creationCtx := a.newCreationContext(createClusterRequest, organizationID, userID, postHooks)
+
+	creator := cluster.NewClusterCreator(createClusterRequest, commonCluster, a.workflowClient)
+
+	commonCluster, err := a.clusterManager.CreateCluster(ctx, creationCtx, creator)
+
+	if err == cluster.ErrAlreadyExists || isInvalid(err) {
+		logger.Debugf("invalid cluster creation: %s", err.Error())
+
+		return nil, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Error:   err.Error(),
+		}
+	} else if err != nil {
+		logger.Errorf("error during cluster creation: %s", err.Error())
+
+		return nil, &pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+			Error:   err.Error(),
+		}
+	}
+
+	if len(createClusterRequest.Labels) > 0 {
+		err := a.labelManager.SetLabels(ctx, commonCluster.GetID(), createClusterRequest.Labels)
+		if err != nil {
+			// the cluster is already being created, labels can be set again later
+			a.errorHandler.Handle(emperror.WrapWith(err, "failed to save cluster labels", "clusterID", commonCluster.GetID()))
+		}
+	}
+
+	return commonCluster, nil
+}
+
+// planCluster returns what creating a K8S cluster would do without creating it.
+func (a *ClusterAPI) planCluster(
+	ctx context.Context,
+	createClusterRequest *pkgCluster.CreateClusterRequest,
+	organizationID uint,
+	userID uint,
+	postHooks pkgCluster.PostHooks,
+) (*pkgCluster.ClusterPlan, *pkgCommon.ErrorResponse) {
+	logger := a.logger.WithFields(logrus.Fields{
+		"organization": organizationID,
+		"user":         userID,
+		"cluster":      createClusterRequest.Name,
+		"dryRun":       true,
+	})
+
+	createClusterRequest, commonCluster, errResp := a.prepareCommonCluster(createClusterRequest, organizationID, userID, logger)
+	if errResp != nil {
+		return nil, errResp
+	}
+
+	creationCtx := a.newCreationContext(createClusterRequest, organizationID, userID, postHooks)
+
+	creator := cluster.NewClusterCreator(createClusterRequest, commonCluster, a.workflowClient)
+
+	plan, err := a.clusterManager.PlanCreateCluster(ctx, creationCtx, creator)
+
+	if err == cluster.ErrAlreadyExists || isInvalid(err) {
+		logger.Debugf("invalid cluster creation: %s", err.Error())
+
+		return nil, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: err.Error(),
+			Error:   err.Error(),
+		}
+	} else if err != nil {
+		logger.Errorf("error during cluster creation plan: %s", err.Error())
+
+		return nil, &pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: err.Error(),
+			Error:   err.Error(),
+		}
+	}
+
+	return plan, nil
+}
+
+func (a *ClusterAPI) newCreationContext(
+	createClusterRequest *pkgCluster.CreateClusterRequest,
+	organizationID uint,
+	userID uint,
+	postHooks pkgCluster.PostHooks,
+) cluster.CreationContext {
+	return cluster.CreationContext{
+		OrganizationID:  organizationID,
+		UserID:          userID,
+		Name:            createClusterRequest.Name,
+		SecretID:        createClusterRequest.SecretId,
+		SecretIDs:       createClusterRequest.SecretIds,
+		Provider:        createClusterRequest.Cloud,
+		PostHooks:       postHooks,
+		ExternalBaseURL: a.externalBaseURL,
+	}
+}
+
+// prepareCommonCluster merges the requested profile into the request and builds the (not yet persisted) cluster.
+func (a *ClusterAPI) prepareCommonCluster(
+	createClusterRequest *pkgCluster.CreateClusterRequest,
+	organizationID uint,
+	userID uint,
+	logger logrus.FieldLogger,
+) (*pkgCluster.CreateClusterRequest, cluster.CommonCluster, *pkgCommon.ErrorResponse) {
+
 	// TODO: refactor profile handling as well?
 	if len(createClusterRequest.ProfileName) != 0 {
 		logger = logger.WithField("profile", createClusterRequest.ProfileName)
@@ -230,7 +406,7 @@ func (a *ClusterAPI) createCluster(
 		case pkgCluster.Oracle:
 			distribution = pkgCluster.OKE
 		default:
-			return nil, &pkgCommon.ErrorResponse{
+			return nil, nil, &pkgCommon.ErrorResponse{
 				Code:    http.StatusBadRequest,
 				Message: "unsupported cloud type",
 				Error:   "unsupported cloud type",
@@ -239,7 +415,7 @@ func (a *ClusterAPI) createCluster(
 
 		profile, err := defaults.GetProfile(distribution, createClusterRequest.ProfileName)
 		if err != nil {
-			return nil, &pkgCommon.ErrorResponse{
+			return nil, nil, &pkgCommon.ErrorResponse{
 				Code:    http.StatusNotFound,
 				Message: "error during getting profile",
 				Error:   err.Error(),
@@ -254,7 +430,7 @@ func (a *ClusterAPI) createCluster(
 		if err != nil {
 			logger.Errorf("error during getting cluster request from profile: %s", err.Error())
 
-			return nil, &pkgCommon.ErrorResponse{
+			return nil, nil, &pkgCommon.ErrorResponse{
 				Code:    http.StatusBadRequest,
 				Message: "Error creating request from profile",
 				Error:   err.Error(),
@@ -278,45 +454,12 @@ func (a *ClusterAPI) createCluster(
 	commonCluster, err := cluster.CreateCommonClusterFromRequest(createClusterRequest, organizationID, userID)
 	if err != nil {
 		log.Errorf("error during create common cluster from request: %s", err.Error())
-		return nil, &pkgCommon.ErrorResponse{
+		return nil, nil, &pkgCommon.ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: err.Error(),
 			Error:   err.Error(),
 		}
 	}
 
-	creationCtx := cluster.CreationContext{
-		OrganizationID:  organizationID,
-		UserID:          userID,
-		Name:            createClusterRequest.Name,
-		SecretID:        createClusterRequest.SecretId,
-		SecretIDs:       createClusterRequest.SecretIds,
-		Provider:        createClusterRequest.Cloud,
-		PostHooks:       postHooks,
-		ExternalBaseURL: a.externalBaseURL,
-	}
-
-	creator := cluster.NewClusterCreator(createClusterRequest, commonCluster, a.workflowClient)
-
-	commonCluster, err = a.clusterManager.CreateCluster(ctx, creationCtx, creator)
-
-	if err == cluster.ErrAlreadyExists || isInvalid(err) {
-		logger.Debugf("invalid cluster creation: %s", err.Error())
-
-		return nil, &pkgCommon.ErrorResponse{
-			Code:    http.StatusBadRequest,
-			Message: err.Error(),
-			Error:   err.Error(),
-		}
-	} else if err != nil {
-		logger.Errorf("error during cluster creation: %s", err.Error())
-
-		return nil, &pkgCommon.ErrorResponse{
-			Code:    http.StatusInternalServerError,
-			Message: err.Error(),
-			Error:   err.Error(),
-		}
-	}
-
-	return commonCluster, nil
+	return createClusterRequest, commonCluster, nil
 }
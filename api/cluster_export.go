@@ -0,0 +1,159 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/banzaicloud/nodepool-labels-operator/pkg/npls"
+	"github.com/ghodss/yaml"
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"github.com/banzaicloud/pipeline/cluster"
+	pipConfig "github.com/banzaicloud/pipeline/config"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+)
+
+// ExportCluster returns the create request of an existing cluster in JSON (default) or YAML format.
+func (a *ClusterAPI) ExportCluster(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "yaml" {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Query parameter 'format' must be either 'json' or 'yaml'",
+			Error:   fmt.Sprintf("invalid format: %s", format),
+		})
+		return
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	errorHandler := emperror.HandlerWith(
+		a.errorHandler,
+		"clusterId", commonCluster.GetID(),
+		"cluster", commonCluster.GetName(),
+	)
+
+	// labels are only available on running clusters, fall back to the stored ones otherwise
+	nodePoolLabels, err := getUserNodePoolLabels(commonCluster)
+	if err != nil {
+		errorHandler.Handle(errors.WithMessage(err, "failed to get node pool labels for cluster export"))
+	}
+
+	request, err := cluster.ExportCreateClusterRequest(commonCluster, nodePoolLabels)
+	if err != nil {
+		if isInvalid(err) {
+			ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: errors.Cause(err).Error(),
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		errorHandler.Handle(err)
+
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error exporting cluster",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	request.Labels, err = a.labelManager.GetLabels(context.Background(), commonCluster.GetID())
+	if err != nil {
+		errorHandler.Handle(err)
+
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error exporting cluster",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, request)
+		return
+	}
+
+	// converted from JSON to keep the field names accepted by the create endpoint
+	body, err := yaml.Marshal(request)
+	if err != nil {
+		errorHandler.Handle(err)
+
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error exporting cluster",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/x-yaml; charset=utf-8", body)
+}
+
+// getUserNodePoolLabels returns the non-reserved labels of the node pools of a running cluster.
+func getUserNodePoolLabels(commonCluster cluster.CommonCluster) (map[string]map[string]string, error) {
+	ready, err := commonCluster.IsReady()
+	if err != nil || !ready {
+		return nil, err
+	}
+
+	k8sConfig, err := commonCluster.GetK8sConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClientConfig, err := k8sclient.NewClientConfig(k8sConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := npls.NewNPLSManager(k8sClientConfig, viper.GetString(pipConfig.PipelineSystemNamespace))
+	if err != nil {
+		return nil, err
+	}
+
+	sets, err := m.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	nodePoolLabels := make(map[string]map[string]string, len(sets))
+	for nodePoolName, labelMap := range sets {
+		labels := make(map[string]string)
+		for key, value := range labelMap {
+			if !cluster.IsReservedDomainKey(key) {
+				labels[key] = value
+			}
+		}
+
+		nodePoolLabels[nodePoolName] = labels
+	}
+
+	return nodePoolLabels, nil
+}
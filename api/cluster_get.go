@@ -15,6 +15,7 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -67,6 +68,18 @@ func (a *ClusterAPI) GetCluster(c *gin.Context) {
 		return
 	}
 
+	clusterLabels, err := a.labelManager.GetLabels(context.Background(), commonCluster.GetID())
+	if err != nil {
+		errorHandler.Handle(err)
+
+		ginutils.ReplyWithErrorResponse(c, &common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error getting cluster labels",
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	response := GetClusterResponse{
 		ID:            clusterStatus.ResourceID,
 		Status:        clusterStatus.Status,
@@ -85,6 +98,7 @@ func (a *ClusterAPI) GetCluster(c *gin.Context) {
 		SecurityScan: clusterStatus.SecurityScan,
 		ScaleOptions: commonCluster.GetScaleOptions(),
 		TtlMinutes:   clusterStatus.TtlMinutes,
+		Labels:       clusterLabels,
 
 		// TODO: keep one of the following?
 		// TODO: is this correct?
@@ -252,6 +266,7 @@ type GetClusterResponse struct {
 	SecurityScan bool                     `json:"securityscan"`
 	ScaleOptions *pkgCluster.ScaleOptions `json:"scaleOptions,omitempty" yaml:"scaleOptions,omitempty"`
 	TtlMinutes   uint                     `json:"ttlMinutes,omitempty" yaml:"ttlMinutes,omitempty"`
+	Labels       map[string]string        `json:"labels,omitempty" yaml:"labels,omitempty"`
 
 	// TODO: keep one of the following?
 	Version       string `json:"version,omitempty"`
@@ -0,0 +1,210 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/api/common"
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// ClusterHibernationAPI implements the cluster hibernation schedule API actions.
+type ClusterHibernationAPI struct {
+	clusterGetter      common.ClusterGetter
+	hibernationManager *cluster.HibernationManager
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewClusterHibernationAPI returns a new ClusterHibernationAPI instance.
+func NewClusterHibernationAPI(
+	clusterGetter common.ClusterGetter,
+	hibernationManager *cluster.HibernationManager,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *ClusterHibernationAPI {
+	return &ClusterHibernationAPI{
+		clusterGetter:      clusterGetter,
+		hibernationManager: hibernationManager,
+		logger:             logger,
+		errorHandler:       errorHandler,
+	}
+}
+
+// ClusterHibernationSchedule describes when the node pools of a cluster are scaled down outside working hours.
+type ClusterHibernationSchedule struct {
+	Enabled    bool     `json:"enabled"`
+	Timezone   string   `json:"timezone" binding:"required"`
+	Weekdays   []string `json:"weekdays,omitempty"`
+	WakeUpTime string   `json:"wakeUpTime" binding:"required"`
+	SleepTime  string   `json:"sleepTime" binding:"required"`
+	NodePools  []string `json:"nodePools,omitempty"`
+	MinCount   int      `json:"minCount"`
+}
+
+// ClusterHibernationResponse describes the hibernation schedule and state of a cluster.
+type ClusterHibernationResponse struct {
+	ClusterHibernationSchedule
+
+	Hibernated     bool                                          `json:"hibernated"`
+	HibernatedAt   *time.Time                                    `json:"hibernatedAt,omitempty"`
+	SavedNodePools map[string]intCluster.HibernationNodePoolSize `json:"savedNodePools,omitempty"`
+}
+
+// GetClusterHibernation returns the hibernation schedule of a cluster.
+func (a *ClusterHibernationAPI) GetClusterHibernation(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	schedule, err := a.hibernationManager.GetSchedule(ctx, commonCluster)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	if schedule == nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "cluster has no hibernation schedule",
+			Error:   "cluster has no hibernation schedule",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newClusterHibernationResponse(schedule))
+}
+
+// SetClusterHibernation creates or replaces the hibernation schedule of a cluster.
+func (a *ClusterHibernationAPI) SetClusterHibernation(c *gin.Context) {
+	var request ClusterHibernationSchedule
+	if err := c.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	schedule := intCluster.HibernationScheduleModel{
+		Enabled:    request.Enabled,
+		Timezone:   request.Timezone,
+		Weekdays:   strings.Join(request.Weekdays, ","),
+		WakeUpTime: request.WakeUpTime,
+		SleepTime:  request.SleepTime,
+		NodePools:  request.NodePools,
+		MinCount:   request.MinCount,
+	}
+
+	saved, err := a.hibernationManager.SetSchedule(ctx, commonCluster, schedule, auth.GetCurrentUser(c.Request).ID)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newClusterHibernationResponse(saved))
+}
+
+// DeleteClusterHibernation removes the hibernation schedule of a cluster and wakes it up if necessary.
+func (a *ClusterHibernationAPI) DeleteClusterHibernation(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	if err := a.hibernationManager.DeleteSchedule(ctx, commonCluster); err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (a *ClusterHibernationAPI) handleError(c *gin.Context, err error) {
+	if isInvalid(err) {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if isPreconditionFailed(err) {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusPreconditionFailed,
+			Message: errors.Cause(err).Error(),
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	a.errorHandler.Handle(err)
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error processing cluster hibernation schedule",
+		Error:   err.Error(),
+	})
+}
+
+func newClusterHibernationResponse(schedule *intCluster.HibernationScheduleModel) ClusterHibernationResponse {
+	response := ClusterHibernationResponse{
+		ClusterHibernationSchedule: ClusterHibernationSchedule{
+			Enabled:    schedule.Enabled,
+			Timezone:   schedule.Timezone,
+			WakeUpTime: schedule.WakeUpTime,
+			SleepTime:  schedule.SleepTime,
+			NodePools:  schedule.NodePools,
+			MinCount:   schedule.MinCount,
+		},
+		Hibernated:     schedule.Hibernated,
+		HibernatedAt:   schedule.HibernatedAt,
+		SavedNodePools: schedule.SavedNodePools,
+	}
+
+	if schedule.Weekdays != "" {
+		response.Weekdays = strings.Split(schedule.Weekdays, ",")
+	}
+
+	return response
+}
@@ -0,0 +1,100 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// ClusterLabels describes the user defined labels of a cluster.
+type ClusterLabels struct {
+	Labels map[string]string `json:"labels"`
+}
+
+// GetClusterLabels returns the user defined labels of a cluster.
+func (a *ClusterAPI) GetClusterLabels(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	labels, err := a.labelManager.GetLabels(ctx, commonCluster.GetID())
+	if err != nil {
+		a.errorHandler.Handle(err)
+
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error getting cluster labels",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ClusterLabels{Labels: labels})
+}
+
+// SetClusterLabels replaces the user defined labels of a cluster.
+func (a *ClusterAPI) SetClusterLabels(c *gin.Context) {
+	var request ClusterLabels
+	if err := c.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.labelManager.SetLabels(ctx, commonCluster.GetID(), request.Labels)
+	if isInvalid(err) {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+			Error:   err.Error(),
+		})
+		return
+	} else if err != nil {
+		a.errorHandler.Handle(err)
+
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error setting cluster labels",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if request.Labels == nil {
+		request.Labels = map[string]string{}
+	}
+
+	c.JSON(http.StatusOK, request)
+}
@@ -0,0 +1,157 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/api/common"
+	"github.com/banzaicloud/pipeline/cluster"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// ClusterPostHookAPI implements the cluster post hook status API actions.
+type ClusterPostHookAPI struct {
+	clusterGetter   common.ClusterGetter
+	postHookManager *cluster.PostHookManager
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewClusterPostHookAPI returns a new ClusterPostHookAPI instance.
+func NewClusterPostHookAPI(
+	clusterGetter common.ClusterGetter,
+	postHookManager *cluster.PostHookManager,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *ClusterPostHookAPI {
+	return &ClusterPostHookAPI{
+		clusterGetter:   clusterGetter,
+		postHookManager: postHookManager,
+		logger:          logger,
+		errorHandler:    errorHandler,
+	}
+}
+
+// ClusterPostHookStatus describes the state of the last execution of a post hook.
+type ClusterPostHookStatus struct {
+	Name       string     `json:"name"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+	Duration   float64    `json:"durationSeconds"`
+}
+
+// ReRunClusterPostHooksRequest describes which post hooks of a cluster should be re-run.
+type ReRunClusterPostHooksRequest struct {
+	// PostHooks is the list of post hook names to re-run (all of them if empty).
+	PostHooks []string `json:"postHooks,omitempty"`
+
+	// FailedOnly limits the re-run to post hooks that failed the last time.
+	FailedOnly bool `json:"failedOnly"`
+}
+
+// GetClusterPostHooks returns the execution state of the post hooks of a cluster.
+func (a *ClusterPostHookAPI) GetClusterPostHooks(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	statuses, err := a.postHookManager.GetStatuses(ctx, commonCluster)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newClusterPostHookStatuses(statuses))
+}
+
+// ReRunClusterPostHooks re-runs a single named, the failed or all post hooks of a cluster.
+func (a *ClusterPostHookAPI) ReRunClusterPostHooks(c *gin.Context) {
+	var request ReRunClusterPostHooksRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	statuses, err := a.postHookManager.ReRunPostHooks(ctx, commonCluster, request.PostHooks, request.FailedOnly)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newClusterPostHookStatuses(statuses))
+}
+
+func (a *ClusterPostHookAPI) handleError(c *gin.Context, err error) {
+	if isInvalid(err) {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	a.errorHandler.Handle(err)
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error processing cluster posthooks",
+		Error:   err.Error(),
+	})
+}
+
+func newClusterPostHookStatuses(statuses []intCluster.PostHookStatusModel) []ClusterPostHookStatus {
+	response := make([]ClusterPostHookStatus, 0, len(statuses))
+
+	for _, status := range statuses {
+		response = append(response, ClusterPostHookStatus{
+			Name:       status.Name,
+			Status:     status.Status,
+			Error:      status.Error,
+			StartedAt:  status.StartedAt,
+			FinishedAt: status.FinishedAt,
+			Duration:   status.Duration().Seconds(),
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,176 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/api/common"
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// ClusterRBACAPI implements the API actions binding organization members and identity provider groups
+// to Kubernetes roles inside clusters.
+type ClusterRBACAPI struct {
+	clusterGetter common.ClusterGetter
+	rbacManager   *cluster.RBACBindingManager
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewClusterRBACAPI returns a new ClusterRBACAPI instance.
+func NewClusterRBACAPI(
+	clusterGetter common.ClusterGetter,
+	rbacManager *cluster.RBACBindingManager,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *ClusterRBACAPI {
+	return &ClusterRBACAPI{
+		clusterGetter: clusterGetter,
+		rbacManager:   rbacManager,
+		logger:        logger,
+		errorHandler:  errorHandler,
+	}
+}
+
+// ListBindings returns the RBAC bindings of a cluster.
+func (a *ClusterRBACAPI) ListBindings(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	bindings, err := a.rbacManager.GetBindings(ctx, commonCluster)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, bindings)
+}
+
+// CreateBinding binds an organization member or an identity provider group to a role in a cluster.
+func (a *ClusterRBACAPI) CreateBinding(c *gin.Context) {
+	var request cluster.RBACBindingRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	binding, err := a.rbacManager.CreateBinding(ctx, commonCluster, request, auth.GetCurrentUser(c.Request).ID)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, binding)
+}
+
+// DeleteBinding removes an RBAC binding from a cluster.
+func (a *ClusterRBACAPI) DeleteBinding(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("bindingId"), 10, 32)
+	if err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid binding ID",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	found, err := a.rbacManager.DeleteBinding(ctx, commonCluster, uint(id))
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	if !found {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "binding not found",
+			Error:   "binding not found",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SyncBindings reapplies the RBAC bindings of a cluster, removing bindings changed or created outside of Pipeline.
+func (a *ClusterRBACAPI) SyncBindings(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	if err := a.rbacManager.Reconcile(ctx, commonCluster); err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (a *ClusterRBACAPI) handleError(c *gin.Context, err error) {
+	if isInvalid(err) {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	a.errorHandler.Handle(err)
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error processing cluster RBAC bindings",
+		Error:   err.Error(),
+	})
+}
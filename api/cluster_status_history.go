@@ -0,0 +1,189 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/api/common"
+	"github.com/banzaicloud/pipeline/auth"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// ClusterStatusHistoryAPI implements the cluster status history API actions.
+type ClusterStatusHistoryAPI struct {
+	clusterGetter common.ClusterGetter
+	statusHistory *intCluster.StatusHistory
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewClusterStatusHistoryAPI returns a new ClusterStatusHistoryAPI instance.
+func NewClusterStatusHistoryAPI(
+	clusterGetter common.ClusterGetter,
+	statusHistory *intCluster.StatusHistory,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *ClusterStatusHistoryAPI {
+	return &ClusterStatusHistoryAPI{
+		clusterGetter: clusterGetter,
+		statusHistory: statusHistory,
+		logger:        logger,
+		errorHandler:  errorHandler,
+	}
+}
+
+// ClusterStatusTransition describes a status transition of a cluster.
+type ClusterStatusTransition struct {
+	ClusterID   uint   `json:"clusterId"`
+	ClusterName string `json:"clusterName"`
+
+	FromStatus        string `json:"fromStatus"`
+	FromStatusMessage string `json:"fromStatusMessage,omitempty"`
+	ToStatus          string `json:"toStatus"`
+	ToStatusMessage   string `json:"toStatusMessage,omitempty"`
+
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+	Duration  string     `json:"duration"`
+	Seconds   int64      `json:"seconds"`
+}
+
+// GetClusterStatusHistory returns the status transitions of a cluster.
+func (a *ClusterStatusHistoryAPI) GetClusterStatusHistory(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	query, ok := a.parseStatusHistoryQuery(c)
+	if !ok {
+		return
+	}
+	query.ClusterID = commonCluster.GetID()
+
+	a.replyWithStatusHistory(c, commonCluster.GetOrganizationId(), query)
+}
+
+// GetOrganizationStatusHistory returns the status transitions of every cluster in an organization.
+// Clusters can be selected using the optional clusterId query parameter (deleted clusters included).
+func (a *ClusterStatusHistoryAPI) GetOrganizationStatusHistory(c *gin.Context) {
+	query, ok := a.parseStatusHistoryQuery(c)
+	if !ok {
+		return
+	}
+
+	if clusterID := c.Query("clusterId"); clusterID != "" {
+		id, err := strconv.ParseUint(clusterID, 10, 0)
+		if err != nil {
+			ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Query parameter 'clusterId' must be a positive, numeric value",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		query.ClusterID = uint(id)
+	}
+
+	a.replyWithStatusHistory(c, auth.GetCurrentOrganization(c.Request).ID, query)
+}
+
+func (a *ClusterStatusHistoryAPI) replyWithStatusHistory(c *gin.Context, organizationID uint, query intCluster.StatusHistoryQuery) {
+	transitions, err := a.statusHistory.FindByOrganization(organizationID, query)
+	if err != nil {
+		a.errorHandler.Handle(err)
+
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error getting cluster status history",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	response := make([]ClusterStatusTransition, 0, len(transitions))
+	for _, transition := range transitions {
+		response = append(response, ClusterStatusTransition{
+			ClusterID:         transition.ClusterID,
+			ClusterName:       transition.ClusterName,
+			FromStatus:        transition.FromStatus,
+			FromStatusMessage: transition.FromStatusMessage,
+			ToStatus:          transition.ToStatus,
+			ToStatusMessage:   transition.ToStatusMessage,
+			StartedAt:         transition.StartedAt,
+			EndedAt:           transition.EndedAt,
+			Duration:          transition.Duration.Round(time.Second).String(),
+			Seconds:           int64(transition.Duration / time.Second),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseStatusHistoryQuery parses the from, to (RFC3339) and status (comma separated) query parameters.
+func (a *ClusterStatusHistoryAPI) parseStatusHistoryQuery(c *gin.Context) (intCluster.StatusHistoryQuery, bool) {
+	var query intCluster.StatusHistoryQuery
+
+	for param, value := range map[string]*time.Time{"from": &query.From, "to": &query.To} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: fmt.Sprintf("Query parameter '%s' must be an RFC3339 timestamp", param),
+				Error:   err.Error(),
+			})
+			return query, false
+		}
+
+		*value = t
+	}
+
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Query parameter 'to' must not be earlier than 'from'",
+			Error:   "invalid time range",
+		})
+		return query, false
+	}
+
+	if statuses := c.Query("status"); statuses != "" {
+		for _, status := range strings.Split(statuses, ",") {
+			if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+				query.Statuses = append(query.Statuses, status)
+			}
+		}
+	}
+
+	return query, true
+}
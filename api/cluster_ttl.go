@@ -0,0 +1,218 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/api/common"
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// ClusterTTLAPI implements the cluster TTL API actions.
+type ClusterTTLAPI struct {
+	clusterGetter common.ClusterGetter
+	ttlManager    *cluster.TTLManager
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewClusterTTLAPI returns a new ClusterTTLAPI instance.
+func NewClusterTTLAPI(
+	clusterGetter common.ClusterGetter,
+	ttlManager *cluster.TTLManager,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *ClusterTTLAPI {
+	return &ClusterTTLAPI{
+		clusterGetter: clusterGetter,
+		ttlManager:    ttlManager,
+		logger:        logger,
+		errorHandler:  errorHandler,
+	}
+}
+
+// SetClusterTTLRequest describes a cluster TTL set request.
+type SetClusterTTLRequest struct {
+	// TtlMinutes is the new TTL of the cluster, zero clears the TTL.
+	TtlMinutes uint `json:"ttlMinutes"`
+}
+
+// ExtendClusterTTLRequest describes a cluster TTL extension (snooze) request.
+type ExtendClusterTTLRequest struct {
+	Minutes uint `json:"minutes" binding:"required,min=1"`
+}
+
+// ClusterTTLResponse describes the TTL of a cluster.
+type ClusterTTLResponse struct {
+	TtlMinutes uint       `json:"ttlMinutes"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+
+	History []ClusterTTLChange `json:"history,omitempty"`
+}
+
+// ClusterTTLChange describes a TTL change or expiry warning of a cluster.
+type ClusterTTLChange struct {
+	Time           time.Time  `json:"time"`
+	UserID         uint       `json:"userId,omitempty"`
+	Action         string     `json:"action"`
+	FromTtlMinutes uint       `json:"fromTtlMinutes"`
+	ToTtlMinutes   uint       `json:"toTtlMinutes"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+}
+
+// GetClusterTTL returns the TTL of a cluster along with the history of its changes.
+func (a *ClusterTTLAPI) GetClusterTTL(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	status, err := a.ttlManager.GetTTL(ctx, commonCluster)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newClusterTTLResponse(status))
+}
+
+// SetClusterTTL sets (or clears) the TTL of a running cluster.
+func (a *ClusterTTLAPI) SetClusterTTL(c *gin.Context) {
+	var request SetClusterTTLRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	status, err := a.ttlManager.SetTTL(ctx, commonCluster, time.Duration(request.TtlMinutes)*time.Minute, auth.GetCurrentUser(c.Request).ID)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newClusterTTLResponse(status))
+}
+
+// DeleteClusterTTL clears the TTL of a running cluster.
+func (a *ClusterTTLAPI) DeleteClusterTTL(c *gin.Context) {
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	status, err := a.ttlManager.SetTTL(ctx, commonCluster, 0, auth.GetCurrentUser(c.Request).ID)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newClusterTTLResponse(status))
+}
+
+// ExtendClusterTTL postpones the deletion of a running cluster.
+func (a *ClusterTTLAPI) ExtendClusterTTL(c *gin.Context) {
+	var request ExtendClusterTTLRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	status, err := a.ttlManager.ExtendTTL(ctx, commonCluster, time.Duration(request.Minutes)*time.Minute, auth.GetCurrentUser(c.Request).ID)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newClusterTTLResponse(status))
+}
+
+func (a *ClusterTTLAPI) handleError(c *gin.Context, err error) {
+	if isInvalid(err) {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	a.errorHandler.Handle(err)
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error changing cluster TTL",
+		Error:   err.Error(),
+	})
+}
+
+func newClusterTTLResponse(status *cluster.TTLStatus) ClusterTTLResponse {
+	response := ClusterTTLResponse{
+		TtlMinutes: uint(status.TTL / time.Minute),
+		StartedAt:  status.StartedAt,
+		ExpiresAt:  status.ExpiresAt,
+	}
+
+	for _, entry := range status.History {
+		response.History = append(response.History, ClusterTTLChange{
+			Time:           entry.CreatedAt,
+			UserID:         entry.UserID,
+			Action:         entry.Action,
+			FromTtlMinutes: entry.FromTTLMinutes,
+			ToTtlMinutes:   entry.ToTTLMinutes,
+			ExpiresAt:      entry.ExpiresAt,
+		})
+	}
+
+	return response
+}
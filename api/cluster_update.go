@@ -34,6 +34,11 @@ type UpdateClusterResponse struct {
 
 // UpdateCluster updates a K8S cluster in the cloud (e.g. autoscale)
 func (a *ClusterAPI) UpdateCluster(c *gin.Context) {
+	dryRun, ok := dryRunQuery(c)
+	if !ok {
+		return
+	}
+
 	// bind request body to UpdateClusterRequest struct
 	var updateRequest *pkgCluster.UpdateClusterRequest
 	if err := c.BindJSON(&updateRequest); err != nil {
@@ -65,7 +70,14 @@ func (a *ClusterAPI) UpdateCluster(c *gin.Context) {
 
 	ctx := ginutils.Context(context.Background(), c)
 
-	err := a.clusterManager.UpdateCluster(ctx, updateCtx, updater)
+	var plan *pkgCluster.ClusterPlan
+	var err error
+
+	if dryRun {
+		plan, err = a.clusterManager.PlanUpdateCluster(ctx, updateCtx, updater)
+	} else {
+		err = a.clusterManager.UpdateCluster(ctx, updateCtx, updater)
+	}
 	if err != nil {
 		if isInvalid(err) {
 			c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
@@ -93,6 +105,11 @@ func (a *ClusterAPI) UpdateCluster(c *gin.Context) {
 		}
 	}
 
+	if dryRun {
+		c.JSON(http.StatusOK, plan)
+		return
+	}
+
 	c.JSON(http.StatusAccepted, UpdateClusterResponse{
 		Status: http.StatusAccepted,
 	})
@@ -0,0 +1,104 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/api/common"
+	"github.com/banzaicloud/pipeline/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// ClusterUpgradeAPI implements the cluster Kubernetes upgrade API actions.
+type ClusterUpgradeAPI struct {
+	clusterGetter  common.ClusterGetter
+	upgradeManager *cluster.KubernetesUpgradeManager
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewClusterUpgradeAPI returns a new ClusterUpgradeAPI instance.
+func NewClusterUpgradeAPI(
+	clusterGetter common.ClusterGetter,
+	upgradeManager *cluster.KubernetesUpgradeManager,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *ClusterUpgradeAPI {
+	return &ClusterUpgradeAPI{
+		clusterGetter:  clusterGetter,
+		upgradeManager: upgradeManager,
+		logger:         logger,
+		errorHandler:   errorHandler,
+	}
+}
+
+// UpgradeClusterRequest describes a Kubernetes version upgrade of a cluster.
+type UpgradeClusterRequest struct {
+	KubernetesVersion string `json:"kubernetesVersion" binding:"required"`
+
+	// RollbackOnFailure restores the previous Kubernetes version for new nodes if the upgrade fails.
+	// Otherwise a failed upgrade is paused and can be resumed by requesting it again.
+	RollbackOnFailure bool `json:"rollbackOnFailure"`
+}
+
+// UpgradeCluster starts upgrading the Kubernetes version of a cluster.
+func (a *ClusterUpgradeAPI) UpgradeCluster(c *gin.Context) {
+	var request UpgradeClusterRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	commonCluster, ok := a.clusterGetter.GetClusterFromRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	err := a.upgradeManager.Upgrade(ctx, commonCluster, request.KubernetesVersion, request.RollbackOnFailure)
+	if isInvalid(err) {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+			Error:   err.Error(),
+		})
+		return
+	} else if err != nil {
+		a.errorHandler.Handle(err)
+
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Error upgrading cluster",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
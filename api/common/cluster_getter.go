@@ -109,5 +109,18 @@ func (g *clusterGetter) GetClusterFromRequest(c *gin.Context) (cluster.CommonClu
 		return nil, false
 	}
 
+	// the token scope is checked against the path, which is not the cluster ID when looking up clusters by name
+	if user := auth.GetCurrentUser(c.Request); user != nil && user.TokenScope != nil && !user.TokenScope.AllowsCluster(cl.GetID()) {
+		logger.Debug("cluster is not allowed by the token scope")
+
+		c.JSON(http.StatusForbidden, pkgCommon.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "cluster is not allowed by the token scope",
+			Error:   "cluster is not allowed by the token scope",
+		})
+
+		return nil, false
+	}
+
 	return cl, true
 }
@@ -15,21 +15,20 @@
 package api
 
 import (
+	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 	"github.com/banzaicloud/pipeline/secret"
 	"github.com/pkg/errors"
 )
 
 // isInvalid checks whether an error is about a resource not being found.
 func isInvalid(err error) bool {
+	if pkgErrors.IsInvalid(err) {
+		return true
+	}
+
 	// Check the root cause error.
 	err = errors.Cause(err)
 
-	if e, ok := err.(interface {
-		IsInvalid() bool
-	}); ok {
-		return e.IsInvalid()
-	}
-
 	switch err {
 	case secret.ErrSecretNotExists:
 		return true
@@ -0,0 +1,266 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// OrganizationPostHookAPI implements the organization post hook API actions.
+type OrganizationPostHookAPI struct {
+	postHookManager *cluster.OrganizationPostHookManager
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewOrganizationPostHookAPI returns a new OrganizationPostHookAPI instance.
+func NewOrganizationPostHookAPI(
+	postHookManager *cluster.OrganizationPostHookManager,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *OrganizationPostHookAPI {
+	return &OrganizationPostHookAPI{
+		postHookManager: postHookManager,
+		logger:          logger,
+		errorHandler:    errorHandler,
+	}
+}
+
+// OrganizationPostHookRequest describes a user defined post hook installing a Helm chart on new clusters.
+type OrganizationPostHookRequest struct {
+	Chart        string `json:"chart" binding:"required"`
+	ChartVersion string `json:"chartVersion,omitempty"`
+	Namespace    string `json:"namespace" binding:"required"`
+
+	// Values is the YAML encoded value overrides of the chart.
+	Values string `json:"values,omitempty"`
+
+	// Priority orders the organization post hooks: the lower the value, the sooner the post hook runs.
+	Priority int `json:"priority"`
+
+	// Enabled defaults to true.
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// CreateOrganizationPostHookRequest describes a new user defined post hook.
+type CreateOrganizationPostHookRequest struct {
+	Name string `json:"name" binding:"required"`
+
+	OrganizationPostHookRequest
+}
+
+// OrganizationPostHookResponse describes a user defined post hook of an organization.
+type OrganizationPostHookResponse struct {
+	Name         string    `json:"name"`
+	Chart        string    `json:"chart"`
+	ChartVersion string    `json:"chartVersion,omitempty"`
+	Namespace    string    `json:"namespace"`
+	Values       string    `json:"values,omitempty"`
+	Priority     int       `json:"priority"`
+	Enabled      bool      `json:"enabled"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	CreatedBy    uint      `json:"createdBy,omitempty"`
+}
+
+// ListOrganizationPostHooks returns the post hooks of an organization in execution order.
+func (a *OrganizationPostHookAPI) ListOrganizationPostHooks(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	postHooks, err := a.postHookManager.ListPostHooks(ctx, auth.GetCurrentOrganization(c.Request).ID)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	response := make([]OrganizationPostHookResponse, 0, len(postHooks))
+	for _, postHook := range postHooks {
+		response = append(response, newOrganizationPostHookResponse(postHook))
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetOrganizationPostHook returns a single post hook of an organization.
+func (a *OrganizationPostHookAPI) GetOrganizationPostHook(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	postHook, err := a.postHookManager.GetPostHook(ctx, auth.GetCurrentOrganization(c.Request).ID, c.Param("name"))
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	if postHook == nil {
+		a.replyNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, newOrganizationPostHookResponse(*postHook))
+}
+
+// CreateOrganizationPostHook registers a new post hook for an organization.
+func (a *OrganizationPostHookAPI) CreateOrganizationPostHook(c *gin.Context) {
+	var request CreateOrganizationPostHookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	postHook := newOrganizationPostHookModel(request.OrganizationPostHookRequest)
+	postHook.OrganizationID = auth.GetCurrentOrganization(c.Request).ID
+	postHook.Name = request.Name
+	postHook.CreatedBy = auth.GetCurrentUser(c.Request).ID
+
+	err := a.postHookManager.CreatePostHook(ctx, &postHook)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, newOrganizationPostHookResponse(postHook))
+}
+
+// UpdateOrganizationPostHook replaces the definition of a post hook of an organization.
+func (a *OrganizationPostHookAPI) UpdateOrganizationPostHook(c *gin.Context) {
+	var request OrganizationPostHookRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	postHook, err := a.postHookManager.UpdatePostHook(
+		ctx,
+		auth.GetCurrentOrganization(c.Request).ID,
+		c.Param("name"),
+		newOrganizationPostHookModel(request),
+	)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	if postHook == nil {
+		a.replyNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, newOrganizationPostHookResponse(*postHook))
+}
+
+// DeleteOrganizationPostHook removes a post hook of an organization.
+func (a *OrganizationPostHookAPI) DeleteOrganizationPostHook(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	deleted, err := a.postHookManager.DeletePostHook(ctx, auth.GetCurrentOrganization(c.Request).ID, c.Param("name"))
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	if !deleted {
+		a.replyNotFound(c)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (a *OrganizationPostHookAPI) replyNotFound(c *gin.Context) {
+	message := fmt.Sprintf("posthook %s not found", c.Param("name"))
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusNotFound,
+		Message: message,
+		Error:   message,
+	})
+}
+
+func (a *OrganizationPostHookAPI) handleError(c *gin.Context, err error) {
+	if isInvalid(err) {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	a.errorHandler.Handle(err)
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error processing organization posthooks",
+		Error:   err.Error(),
+	})
+}
+
+func newOrganizationPostHookModel(request OrganizationPostHookRequest) intCluster.OrganizationPostHookModel {
+	enabled := true
+	if request.Enabled != nil {
+		enabled = *request.Enabled
+	}
+
+	return intCluster.OrganizationPostHookModel{
+		Chart:        request.Chart,
+		ChartVersion: request.ChartVersion,
+		Namespace:    request.Namespace,
+		Values:       request.Values,
+		Priority:     request.Priority,
+		Enabled:      enabled,
+	}
+}
+
+func newOrganizationPostHookResponse(postHook intCluster.OrganizationPostHookModel) OrganizationPostHookResponse {
+	return OrganizationPostHookResponse{
+		Name:         postHook.Name,
+		Chart:        postHook.Chart,
+		ChartVersion: postHook.ChartVersion,
+		Namespace:    postHook.Namespace,
+		Values:       postHook.Values,
+		Priority:     postHook.Priority,
+		Enabled:      postHook.Enabled,
+		CreatedAt:    postHook.CreatedAt,
+		UpdatedAt:    postHook.UpdatedAt,
+		CreatedBy:    postHook.CreatedBy,
+	}
+}
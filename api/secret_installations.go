@@ -0,0 +1,132 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// SecretInstallationAPI implements the API actions keeping the copies of secrets installed into clusters up to date.
+type SecretInstallationAPI struct {
+	syncManager *cluster.SecretSyncManager
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewSecretInstallationAPI returns a new SecretInstallationAPI instance.
+func NewSecretInstallationAPI(
+	syncManager *cluster.SecretSyncManager,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *SecretInstallationAPI {
+	return &SecretInstallationAPI{
+		syncManager:  syncManager,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// SecretInstallation describes a copy of a secret installed into a cluster and the state of its last sync.
+type SecretInstallation struct {
+	ClusterID     uint       `json:"clusterId"`
+	Namespace     string     `json:"namespace"`
+	Name          string     `json:"name"`
+	Status        string     `json:"status"`
+	Error         string     `json:"error,omitempty"`
+	SyncedVersion int        `json:"syncedVersion,omitempty"`
+	SyncedAt      *time.Time `json:"syncedAt,omitempty"`
+}
+
+// ListSecretInstallations returns the clusters a secret is installed into.
+func (a *SecretInstallationAPI) ListSecretInstallations(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	installations, err := a.syncManager.GetInstallations(ctx, auth.GetCurrentOrganization(c.Request).ID, getSecretID(c))
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newSecretInstallations(installations))
+}
+
+// SyncSecretInstallations updates every installed copy of a secret in the background.
+func (a *SecretInstallationAPI) SyncSecretInstallations(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	installations, err := a.syncManager.SyncSecret(ctx, auth.GetCurrentOrganization(c.Request).ID, getSecretID(c))
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, newSecretInstallations(installations))
+}
+
+// SyncSecretInstallationsAfterUpdate is chained after the handlers changing a secret.
+// It starts updating the installed copies of the secret once the change succeeded, without touching the response.
+func (a *SecretInstallationAPI) SyncSecretInstallationsAfterUpdate(c *gin.Context) {
+	if c.IsAborted() || c.Writer.Status() != http.StatusOK {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	_, err := a.syncManager.SyncSecret(ctx, auth.GetCurrentOrganization(c.Request).ID, getSecretID(c))
+	if err != nil {
+		a.errorHandler.Handle(emperror.Wrap(err, "failed to sync secret installations"))
+	}
+}
+
+func (a *SecretInstallationAPI) handleError(c *gin.Context, err error) {
+	a.errorHandler.Handle(err)
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error processing secret installations",
+		Error:   err.Error(),
+	})
+}
+
+func newSecretInstallations(installations []intCluster.SecretInstallationModel) []SecretInstallation {
+	response := make([]SecretInstallation, 0, len(installations))
+
+	for _, installation := range installations {
+		response = append(response, SecretInstallation{
+			ClusterID:     installation.ClusterID,
+			Namespace:     installation.Namespace,
+			Name:          installation.Name,
+			Status:        installation.Status,
+			Error:         installation.Error,
+			SyncedVersion: installation.SyncedVersion,
+			SyncedAt:      installation.SyncedAt,
+		})
+	}
+
+	return response
+}
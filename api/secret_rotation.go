@@ -0,0 +1,98 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// SecretRotationAPI implements the API actions rotating the cloud credentials stored in secrets.
+type SecretRotationAPI struct {
+	rotationManager *cluster.SecretRotationManager
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewSecretRotationAPI returns a new SecretRotationAPI instance.
+func NewSecretRotationAPI(
+	rotationManager *cluster.SecretRotationManager,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *SecretRotationAPI {
+	return &SecretRotationAPI{
+		rotationManager: rotationManager,
+		logger:          logger,
+		errorHandler:    errorHandler,
+	}
+}
+
+// RotateSecret replaces the credential stored in a secret and updates the clusters using it in the background.
+func (a *SecretRotationAPI) RotateSecret(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	rotation, err := a.rotationManager.RotateSecret(
+		ctx,
+		auth.GetCurrentOrganization(c.Request).ID,
+		getSecretID(c),
+		auth.GetCurrentUser(c.Request).Login,
+	)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, rotation)
+}
+
+func (a *SecretRotationAPI) handleError(c *gin.Context, err error) {
+	if errors.Cause(err) == secret.ErrSecretNotExists {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Secret not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if isInvalid(err) {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: errors.Cause(err).Error(),
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	a.errorHandler.Handle(err)
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error rotating secret",
+		Error:   err.Error(),
+	})
+}
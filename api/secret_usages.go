@@ -0,0 +1,114 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/cluster"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// SecretUsageAPI implements the API actions listing the resources referencing secrets.
+type SecretUsageAPI struct {
+	usageManager *cluster.SecretUsageManager
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewSecretUsageAPI returns a new SecretUsageAPI instance.
+func NewSecretUsageAPI(
+	usageManager *cluster.SecretUsageManager,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *SecretUsageAPI {
+	return &SecretUsageAPI{
+		usageManager: usageManager,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// GetSecretUsages returns the resources referencing a secret.
+func (a *SecretUsageAPI) GetSecretUsages(c *gin.Context) {
+	ctx := ginutils.Context(context.Background(), c)
+
+	usages, err := a.usageManager.GetUsages(ctx, auth.GetCurrentOrganization(c.Request).ID, getSecretID(c))
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usages)
+}
+
+// RequireUnusedSecret is chained before the handler deleting a secret.
+// It refuses to delete a secret which is still referenced unless the force query parameter is set.
+func (a *SecretUsageAPI) RequireUnusedSecret(c *gin.Context) {
+	if force, _ := strconv.ParseBool(c.DefaultQuery("force", "false")); force {
+		return
+	}
+
+	ctx := ginutils.Context(context.Background(), c)
+
+	secretID := getSecretID(c)
+
+	usages, err := a.usageManager.GetUsages(ctx, auth.GetCurrentOrganization(c.Request).ID, secretID)
+	if err != nil {
+		a.handleError(c, err)
+		c.Abort()
+		return
+	}
+
+	if usages.InUse() {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: fmt.Sprintf("Secret[%s] is in use, delete it with force=true to ignore its usages", secretID),
+			Error:   fmt.Sprintf("secret is referenced by %s", usages),
+		})
+		c.Abort()
+	}
+}
+
+func (a *SecretUsageAPI) handleError(c *gin.Context, err error) {
+	if errors.Cause(err) == secret.ErrSecretNotExists {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Secret not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	a.errorHandler.Handle(err)
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error getting secret usages",
+		Error:   err.Error(),
+	})
+}
@@ -0,0 +1,163 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/secret"
+	"github.com/gin-gonic/gin"
+)
+
+// ListSecretVersions returns the versions of a secret along with who created them and when
+func ListSecretVersions(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+	secretID := getSecretID(c)
+
+	versions, err := secret.RestrictedStore.ListVersions(organizationID, secretID)
+	if err != nil {
+		replySecretVersionError(c, "Error during listing secret versions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// GetSecretVersion returns a specific version of a secret
+func GetSecretVersion(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+	secretID := getSecretID(c)
+
+	version, ok := getSecretVersion(c, c.Param("version"))
+	if !ok {
+		return
+	}
+
+	item, err := secret.RestrictedStore.GetVersion(organizationID, secretID, version)
+	if err != nil {
+		replySecretVersionError(c, "Error during getting secret version", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// DiffSecretVersions compares a version of a secret with another one (the latest by default)
+func DiffSecretVersions(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+	secretID := getSecretID(c)
+
+	version, ok := getSecretVersion(c, c.Param("version"))
+	if !ok {
+		return
+	}
+
+	from, err := secret.RestrictedStore.GetVersion(organizationID, secretID, version)
+	if err != nil {
+		replySecretVersionError(c, "Error during getting secret version", err)
+		return
+	}
+
+	var to *secret.SecretItemResponse
+	if toParam := c.Query("to"); toParam != "" {
+		toVersion, ok := getSecretVersion(c, toParam)
+		if !ok {
+			return
+		}
+
+		to, err = secret.RestrictedStore.GetVersion(organizationID, secretID, toVersion)
+	} else {
+		to, err = secret.RestrictedStore.Get(organizationID, secretID)
+	}
+	if err != nil {
+		replySecretVersionError(c, "Error during getting secret version", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, secret.DiffSecrets(from, to))
+}
+
+// RollbackSecret restores a previous version of a secret as its latest version
+func RollbackSecret(c *gin.Context) {
+	organizationID := auth.GetCurrentOrganization(c.Request).ID
+	secretID := getSecretID(c)
+
+	version, ok := getSecretVersion(c, c.Param("version"))
+	if !ok {
+		return
+	}
+
+	err := secret.RestrictedStore.Rollback(organizationID, secretID, version, auth.GetCurrentUser(c.Request).Login)
+	if err != nil {
+		replySecretVersionError(c, "Error during secret rollback", err)
+		return
+	}
+
+	log.Infof("Secret %s rolled back to version %d", secretID, version)
+
+	s, err := secret.RestrictedStore.Get(organizationID, secretID)
+	if err != nil {
+		replySecretVersionError(c, "Error during getting secret", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, secret.CreateSecretResponse{
+		Name:      s.Name,
+		Type:      s.Type,
+		ID:        secretID,
+		UpdatedAt: s.UpdatedAt,
+		UpdatedBy: s.UpdatedBy,
+		Version:   s.Version,
+	})
+}
+
+func getSecretVersion(c *gin.Context, param string) (int, bool) {
+	version, err := strconv.Atoi(param)
+	if err != nil || version < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Secret version must be a positive number",
+			Error:   "invalid secret version",
+		})
+		return 0, false
+	}
+
+	return version, true
+}
+
+func replySecretVersionError(c *gin.Context, message string, err error) {
+	log.Errorf("%s: %s", message, err.Error())
+
+	code := http.StatusInternalServerError
+	switch err.(type) {
+	case secret.ForbiddenError, secret.ReadOnlyError:
+		code = http.StatusBadRequest
+	default:
+		if err == secret.ErrSecretNotExists {
+			code = http.StatusNotFound
+		} else if secret.IsCASError(err) {
+			code = http.StatusConflict
+		}
+	}
+
+	c.AbortWithStatusJSON(code, common.ErrorResponse{
+		Code:    code,
+		Message: message,
+		Error:   err.Error(),
+	})
+}
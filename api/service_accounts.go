@@ -0,0 +1,322 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	"github.com/banzaicloud/pipeline/auth"
+	ginutils "github.com/banzaicloud/pipeline/internal/platform/gin/utils"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+)
+
+// maxServiceAccountNameLength is the maximum length of service account names.
+const maxServiceAccountNameLength = 50
+
+// ServiceAccountAPI implements the organization service account API actions.
+type ServiceAccountAPI struct {
+	db *gorm.DB
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// CreateServiceAccountRequest describes a service account create request.
+type CreateServiceAccountRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Role        string `json:"role" binding:"required"`
+	Description string `json:"description,omitempty"`
+}
+
+// UpdateServiceAccountRequest describes a service account update request.
+type UpdateServiceAccountRequest struct {
+	Role        string  `json:"role,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// CreateServiceAccountTokenRequest describes a service account token create request.
+type CreateServiceAccountTokenRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// CreateServiceAccountTokenResponse contains a newly created service account token.
+// The token itself is only returned once.
+type CreateServiceAccountTokenResponse struct {
+	ID    string `json:"id"`
+	Token string `json:"token"`
+}
+
+// NewServiceAccountAPI returns a new ServiceAccountAPI instance.
+func NewServiceAccountAPI(db *gorm.DB, logger logrus.FieldLogger, errorHandler emperror.Handler) *ServiceAccountAPI {
+	return &ServiceAccountAPI{
+		db:           db,
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+// List lists the service accounts of the organization.
+func (a *ServiceAccountAPI) List(c *gin.Context) {
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	serviceAccounts := make([]auth.ServiceAccount, 0)
+
+	err := a.db.Where(auth.ServiceAccount{OrganizationID: organization.ID}).Order("name").Find(&serviceAccounts).Error
+	if err != nil {
+		a.handleError(c, errors.Wrap(err, "failed to list service accounts"))
+		return
+	}
+
+	c.JSON(http.StatusOK, serviceAccounts)
+}
+
+// Create creates a new service account in the organization.
+func (a *ServiceAccountAPI) Create(c *gin.Context) {
+	var request CreateServiceAccountRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		a.replyBadRequest(c, "Error parsing request", err.Error())
+		return
+	}
+
+	if errs := validation.IsDNS1123Label(request.Name); len(errs) > 0 {
+		a.replyBadRequest(c, "invalid service account name", strings.Join(errs, ", "))
+		return
+	}
+
+	if len(request.Name) > maxServiceAccountNameLength {
+		a.replyBadRequest(c, "invalid service account name", "service account name must be no more than 50 characters")
+		return
+	}
+
+	if !auth.IsValidServiceAccountRole(request.Role) {
+		a.replyBadRequest(c, "invalid service account role", "service accounts can be admins, members or viewers")
+		return
+	}
+
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	var count int
+	err := a.db.Model(auth.ServiceAccount{}).
+		Where(auth.ServiceAccount{OrganizationID: organization.ID, Name: request.Name}).
+		Count(&count).Error
+	if err != nil {
+		a.handleError(c, errors.Wrap(err, "failed to check service account name"))
+		return
+	}
+
+	if count > 0 {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: "service account already exists",
+			Error:   "service account already exists",
+		})
+		return
+	}
+
+	serviceAccount := auth.ServiceAccount{
+		OrganizationID: organization.ID,
+		Name:           request.Name,
+		Description:    request.Description,
+		Role:           request.Role,
+		CreatedBy:      auth.GetCurrentUser(c.Request).ID,
+	}
+
+	if err := a.db.Create(&serviceAccount).Error; err != nil {
+		a.handleError(c, errors.Wrap(err, "failed to create service account"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, serviceAccount)
+}
+
+// Get returns a service account of the organization.
+func (a *ServiceAccountAPI) Get(c *gin.Context) {
+	serviceAccount, ok := a.getServiceAccount(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, serviceAccount)
+}
+
+// Update changes the role or the description of a service account.
+func (a *ServiceAccountAPI) Update(c *gin.Context) {
+	var request UpdateServiceAccountRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		a.replyBadRequest(c, "Error parsing request", err.Error())
+		return
+	}
+
+	if request.Role != "" && !auth.IsValidServiceAccountRole(request.Role) {
+		a.replyBadRequest(c, "invalid service account role", "service accounts can be admins, members or viewers")
+		return
+	}
+
+	serviceAccount, ok := a.getServiceAccount(c)
+	if !ok {
+		return
+	}
+
+	if request.Role != "" {
+		serviceAccount.Role = request.Role
+	}
+
+	if request.Description != nil {
+		serviceAccount.Description = *request.Description
+	}
+
+	if err := a.db.Save(serviceAccount).Error; err != nil {
+		a.handleError(c, errors.Wrap(err, "failed to update service account"))
+		return
+	}
+
+	c.JSON(http.StatusOK, serviceAccount)
+}
+
+// Delete revokes every token of a service account and deletes it.
+func (a *ServiceAccountAPI) Delete(c *gin.Context) {
+	serviceAccount, ok := a.getServiceAccount(c)
+	if !ok {
+		return
+	}
+
+	if err := auth.RevokeServiceAccountTokens(a.db, serviceAccount); err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	if err := a.db.Delete(serviceAccount).Error; err != nil {
+		a.handleError(c, errors.Wrap(err, "failed to delete service account"))
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListTokens lists the tokens of a service account.
+func (a *ServiceAccountAPI) ListTokens(c *gin.Context) {
+	serviceAccount, ok := a.getServiceAccount(c)
+	if !ok {
+		return
+	}
+
+	tokens, err := auth.GetServiceAccountTokens(a.db, serviceAccount)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// CreateToken creates a new token for a service account.
+func (a *ServiceAccountAPI) CreateToken(c *gin.Context) {
+	var request CreateServiceAccountTokenRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		a.replyBadRequest(c, "Error parsing request", err.Error())
+		return
+	}
+
+	if request.ExpiresAt != nil && request.ExpiresAt.Before(time.Now()) {
+		a.replyBadRequest(c, "invalid token expiration", "expiresAt must be in the future")
+		return
+	}
+
+	serviceAccount, ok := a.getServiceAccount(c)
+	if !ok {
+		return
+	}
+
+	tokenID, token, err := auth.CreateServiceAccountToken(a.db, serviceAccount, request.Name, request.ExpiresAt)
+	if err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, CreateServiceAccountTokenResponse{
+		ID:    tokenID,
+		Token: token,
+	})
+}
+
+// DeleteToken revokes a token of a service account.
+func (a *ServiceAccountAPI) DeleteToken(c *gin.Context) {
+	serviceAccount, ok := a.getServiceAccount(c)
+	if !ok {
+		return
+	}
+
+	if err := auth.RevokeServiceAccountToken(a.db, serviceAccount, c.Param("tokenId")); err != nil {
+		a.handleError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getServiceAccount returns the service account of the organization identified by the id path parameter.
+func (a *ServiceAccountAPI) getServiceAccount(c *gin.Context) (*auth.ServiceAccount, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		a.replyBadRequest(c, "invalid service account ID", err.Error())
+		return nil, false
+	}
+
+	serviceAccount, err := auth.GetServiceAccount(a.db, uint(id))
+	if err != nil {
+		a.handleError(c, err)
+		return nil, false
+	}
+
+	if serviceAccount == nil || serviceAccount.OrganizationID != auth.GetCurrentOrganization(c.Request).ID {
+		ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "service account not found",
+			Error:   "service account not found",
+		})
+		return nil, false
+	}
+
+	return serviceAccount, true
+}
+
+func (a *ServiceAccountAPI) replyBadRequest(c *gin.Context, message string, err string) {
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusBadRequest,
+		Message: message,
+		Error:   err,
+	})
+}
+
+func (a *ServiceAccountAPI) handleError(c *gin.Context, err error) {
+	a.errorHandler.Handle(err)
+
+	ginutils.ReplyWithErrorResponse(c, &pkgCommon.ErrorResponse{
+		Code:    http.StatusInternalServerError,
+		Message: "Error processing service account",
+		Error:   err.Error(),
+	})
+}
@@ -15,9 +15,11 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/banzaicloud/pipeline/auth"
 	"github.com/banzaicloud/pipeline/pkg/common"
@@ -28,23 +30,29 @@ import (
 )
 
 type userAccessManager interface {
-	GrantOrganizationAccessToUser(userID string, orgID uint)
-	RevokeOrganizationAccessFromUser(userID string, orgID uint)
+	GrantOrganizationAccessToUser(userID string, orgID uint) error
+	RevokeOrganizationAccessFromUser(userID string, orgID uint) error
+}
+
+type userEvents interface {
+	OrganizationMemberRemoved(organizationID uint, userID uint)
 }
 
 // UserAPI implements user functions.
 type UserAPI struct {
 	accessManager userAccessManager
 	db            *gorm.DB
+	events        userEvents
 	log           logrus.FieldLogger
 	errorHandler  emperror.Handler
 }
 
 // NewUserAPI returns a new UserAPI instance.
-func NewUserAPI(accessManager userAccessManager, db *gorm.DB, log logrus.FieldLogger, errorHandler emperror.Handler) *UserAPI {
+func NewUserAPI(accessManager userAccessManager, db *gorm.DB, events userEvents, log logrus.FieldLogger, errorHandler emperror.Handler) *UserAPI {
 	return &UserAPI{
 		accessManager: accessManager,
 		db:            db,
+		events:        events,
 		log:           log,
 		errorHandler:  errorHandler,
 	}
@@ -123,8 +131,12 @@ func (a *UserAPI) GetUsers(c *gin.Context) {
 	}
 
 	var users []auth.User
+	var orgUsers []OrganizationUser
 
 	err = a.db.Model(organization).Where(&auth.User{ID: uint(id)}).Related(&users, "Users").Error
+	if err == nil {
+		orgUsers, err = a.withRoles(organization, users)
+	}
 	if err != nil {
 		message := "failed to fetch users"
 		a.errorHandler.Handle(emperror.Wrap(err, message))
@@ -135,9 +147,9 @@ func (a *UserAPI) GetUsers(c *gin.Context) {
 		})
 		return
 	} else if id == 0 {
-		c.JSON(http.StatusOK, users)
+		c.JSON(http.StatusOK, orgUsers)
 	} else if len(users) == 1 {
-		c.JSON(http.StatusOK, users[0])
+		c.JSON(http.StatusOK, orgUsers[0])
 	} else if len(users) > 1 {
 		message := fmt.Sprintf("multiple users found with id: %d", id)
 		log.Info(message)
@@ -157,7 +169,7 @@ func (a *UserAPI) GetUsers(c *gin.Context) {
 	}
 }
 
-// AddUser adds a user to an organization, role=admin|member has to be in the body, otherwise member is the default role.
+// AddUser adds a user to an organization, role=owner|admin|member|viewer can be in the body, otherwise member is the default role.
 func (a *UserAPI) AddUser(c *gin.Context) {
 
 	log.Info("Adding user to organization")
@@ -175,9 +187,7 @@ func (a *UserAPI) AddUser(c *gin.Context) {
 		return
 	}
 
-	role := struct {
-		Role string `json:"role" binding:"required,eq=member|eq=admin"`
-	}{Role: "member"}
+	role := organizationRole{Role: auth.RoleMember}
 
 	if c.Request.ContentLength != 0 {
 		err = c.ShouldBindJSON(&role)
@@ -196,6 +206,10 @@ func (a *UserAPI) AddUser(c *gin.Context) {
 	organization := auth.GetCurrentOrganization(c.Request)
 	user := &auth.User{ID: uint(id)}
 
+	if !a.checkRoleChange(c, organization, user.ID, role.Role) {
+		return
+	}
+
 	err = a.addUserToOrgInDb(organization, user, role.Role)
 
 	if err != nil {
@@ -210,7 +224,17 @@ func (a *UserAPI) AddUser(c *gin.Context) {
 		return
 	}
 
-	a.accessManager.GrantOrganizationAccessToUser(user.IDString(), organization.ID)
+	err = a.accessManager.GrantOrganizationAccessToUser(user.IDString(), organization.ID)
+	if err != nil {
+		message := "failed to add user"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
 
 	c.Status(http.StatusNoContent)
 }
@@ -256,7 +280,13 @@ func (a *UserAPI) RemoveUser(c *gin.Context) {
 		return
 	}
 
-	err = a.db.Model(organization).Association("Users").Delete(auth.User{ID: uint(id)}).Error
+	if !a.checkRoleChange(c, organization, uint(id), "") {
+		return
+	}
+
+	user := &auth.User{ID: uint(id)}
+
+	err = a.accessManager.RevokeOrganizationAccessFromUser(user.IDString(), organization.ID)
 	if err != nil {
 		message := "failed to delete user"
 		a.errorHandler.Handle(emperror.Wrap(err, message))
@@ -269,13 +299,375 @@ func (a *UserAPI) RemoveUser(c *gin.Context) {
 		return
 	}
 
-	user := auth.GetCurrentUser(c.Request)
+	a.events.OrganizationMemberRemoved(organization.ID, user.ID)
+
+	c.Status(http.StatusNoContent)
+}
+
+// UpdateUserRole changes the role of a user in an organization, role=owner|admin|member|viewer has to be in the body.
+func (a *UserAPI) UpdateUserRole(c *gin.Context) {
+
+	log.Info("Updating user role in organization")
+
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		message := fmt.Sprintf("error parsing user id: %s", err)
+		log.Info(message)
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	var role organizationRole
+	err = c.ShouldBindJSON(&role)
+	if err != nil {
+		message := fmt.Sprintf("error parsing role from request: %s", err)
+		log.Info(message)
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	currentRole, err := auth.GetUserRole(a.db, uint(id), organization.ID)
+	if err != nil {
+		message := "failed to fetch user role"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+		return
+	} else if currentRole == "" {
+		message := fmt.Sprintf("user not found with id: %d", id)
+		log.Info(message)
+		c.AbortWithStatusJSON(http.StatusNotFound, common.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
 
-	a.accessManager.RevokeOrganizationAccessFromUser(user.IDString(), organization.ID)
+	if !a.checkRoleChange(c, organization, uint(id), role.Role) {
+		return
+	}
+
+	userRoleInOrg := auth.UserOrganization{UserID: uint(id), OrganizationID: organization.ID}
+	err = a.db.Model(&auth.UserOrganization{}).Where(userRoleInOrg).Update("role", role.Role).Error
+	if err != nil {
+		message := "failed to update user role"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		statusCode := auth.GormErrorToStatusCode(err)
+		c.AbortWithStatusJSON(statusCode, common.ErrorResponse{
+			Code:    statusCode,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
 
 	c.Status(http.StatusNoContent)
 }
 
+// GetInvitations lists the pending invitations of an organization.
+func (a *UserAPI) GetInvitations(c *gin.Context) {
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	invitations, err := auth.GetPendingInvitations(a.db, organization.ID)
+	if err != nil {
+		message := "failed to fetch invitations"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// InviteUser invites a user to an organization by login name or email address, role=owner|admin|member|viewer can be in the body,
+// otherwise member is the default role. The invitation is accepted when the user logs in the next time.
+func (a *UserAPI) InviteUser(c *gin.Context) {
+
+	log.Info("Inviting user to organization")
+
+	request := inviteUserRequest{Role: auth.RoleMember}
+
+	err := c.ShouldBindJSON(&request)
+	if err == nil && request.Login == "" && request.Email == "" {
+		err = errors.New("login or email is required")
+	}
+	if err != nil {
+		message := fmt.Sprintf("error parsing invitation request: %s", err)
+		log.Info(message)
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	// nobody can hold the role yet, this only checks whether the current user may grant it
+	if !a.checkRoleChange(c, organization, 0, request.Role) {
+		return
+	}
+
+	invitation := auth.OrganizationInvitation{
+		OrganizationID: organization.ID,
+		Login:          request.Login,
+		Email:          auth.NormalizeInvitationEmail(request.Email),
+		Role:           request.Role,
+		InvitedBy:      auth.GetCurrentUser(c.Request).ID,
+		ExpiresAt:      time.Now().Add(auth.InvitationTTL),
+	}
+
+	conflict, err := a.checkInvitation(organization, &invitation)
+	if err != nil {
+		message := "failed to check invitation"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+		return
+	} else if conflict != "" {
+		log.Info(conflict)
+		c.AbortWithStatusJSON(http.StatusConflict, common.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: conflict,
+			Error:   conflict,
+		})
+		return
+	}
+
+	err = a.db.Create(&invitation).Error
+	if err != nil {
+		message := "failed to save invitation"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// checkInvitation returns the reason why the invited user cannot be invited (if any):
+// it is already a member of the organization or already has a pending invitation.
+func (a *UserAPI) checkInvitation(organization *auth.Organization, invitation *auth.OrganizationInvitation) (string, error) {
+	query := a.db.Model(organization)
+	if invitation.Login != "" && invitation.Email != "" {
+		query = query.Where("login = ? OR email = ?", invitation.Login, invitation.Email)
+	} else if invitation.Login != "" {
+		query = query.Where("login = ?", invitation.Login)
+	} else {
+		query = query.Where("email = ?", invitation.Email)
+	}
+
+	var members []auth.User
+	if err := query.Related(&members, "Users").Error; err != nil {
+		return "", err
+	}
+
+	if len(members) > 0 {
+		return fmt.Sprintf("user %s is already a member of the organization", members[0].Login), nil
+	}
+
+	invitations, err := auth.GetPendingInvitations(a.db, organization.ID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, pending := range invitations {
+		if (invitation.Login != "" && pending.Login == invitation.Login) || (invitation.Email != "" && pending.Email == invitation.Email) {
+			return "the user already has a pending invitation", nil
+		}
+	}
+
+	return "", nil
+}
+
+// DeleteInvitation revokes a pending invitation.
+func (a *UserAPI) DeleteInvitation(c *gin.Context) {
+
+	log.Info("Deleting organization invitation")
+
+	idParam := c.Param("id")
+	id, err := strconv.ParseUint(idParam, 10, 32)
+	if err != nil {
+		message := fmt.Sprintf("error parsing invitation id: %s", err)
+		log.Info(message)
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	organization := auth.GetCurrentOrganization(c.Request)
+
+	result := a.db.Delete(auth.OrganizationInvitation{}, "id = ? AND organization_id = ?", id, organization.ID)
+	if err := result.Error; err != nil {
+		message := "failed to delete invitation"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+		return
+	} else if result.RowsAffected == 0 {
+		message := fmt.Sprintf("invitation not found with id: %d", id)
+		log.Info(message)
+		c.AbortWithStatusJSON(http.StatusNotFound, common.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: message,
+			Error:   message,
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+type inviteUserRequest struct {
+	Login string `json:"login,omitempty"`
+	Email string `json:"email,omitempty" binding:"omitempty,email"`
+	Role  string `json:"role" binding:"required,eq=owner|eq=admin|eq=member|eq=viewer"`
+}
+
+// OrganizationUser describes a member of an organization along with its role.
+type OrganizationUser struct {
+	auth.User
+	Role string `json:"role"`
+}
+
+type organizationRole struct {
+	Role string `json:"role" binding:"required,eq=owner|eq=admin|eq=member|eq=viewer"`
+}
+
+// withRoles returns organization users along with their roles.
+func (a *UserAPI) withRoles(organization *auth.Organization, users []auth.User) ([]OrganizationUser, error) {
+	var memberships []auth.UserOrganization
+
+	err := a.db.Where(auth.UserOrganization{OrganizationID: organization.ID}).Find(&memberships).Error
+	if err != nil {
+		return nil, err
+	}
+
+	roles := make(map[uint]string, len(memberships))
+	for _, membership := range memberships {
+		roles[membership.UserID] = membership.Role
+	}
+
+	orgUsers := make([]OrganizationUser, 0, len(users))
+	for _, user := range users {
+		role := roles[user.ID]
+		if role == "" {
+			role = auth.RoleViewer
+		}
+
+		orgUsers = append(orgUsers, OrganizationUser{User: user, Role: role})
+	}
+
+	return orgUsers, nil
+}
+
+// checkRoleChange checks whether the current user may change the role of a user in the organization
+// (an empty role means removing the user) and replies with an error if not.
+// Only owners can grant or revoke the owner role, and the last owner cannot be demoted or removed.
+func (a *UserAPI) checkRoleChange(c *gin.Context, organization *auth.Organization, userID uint, role string) bool {
+	currentRole, err := auth.GetUserRole(a.db, userID, organization.ID)
+	if err != nil {
+		message := "failed to fetch user role"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+		return false
+	}
+
+	if currentRole != auth.RoleOwner && role != auth.RoleOwner {
+		return true
+	}
+
+	requesterRole, err := auth.GetUserRole(a.db, auth.GetCurrentUser(c.Request).ID, organization.ID)
+	if err != nil {
+		message := "failed to fetch user role"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+		return false
+	}
+
+	if requesterRole != auth.RoleOwner {
+		message := "only organization owners can manage owners"
+		log.Info(message)
+		c.AbortWithStatusJSON(http.StatusForbidden, common.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: message,
+			Error:   message,
+		})
+		return false
+	}
+
+	if currentRole != auth.RoleOwner || role == auth.RoleOwner {
+		return true
+	}
+
+	var owners int
+	err = a.db.Model(&auth.UserOrganization{}).Where(auth.UserOrganization{OrganizationID: organization.ID, Role: auth.RoleOwner}).Count(&owners).Error
+	if err != nil {
+		message := "failed to count organization owners"
+		a.errorHandler.Handle(emperror.Wrap(err, message))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: message,
+			Error:   message,
+		})
+		return false
+	}
+
+	if owners <= 1 {
+		message := "the organization must have at least one owner"
+		log.Info(message)
+		c.AbortWithStatusJSON(http.StatusConflict, common.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: message,
+			Error:   message,
+		})
+		return false
+	}
+
+	return true
+}
+
 type updateUserRequest struct {
 	GitHubToken *string `json:"gitHubToken,omitempty"`
 	GitLabToken *string `json:"gitLabToken,omitempty"`
@@ -123,9 +123,13 @@ type CICDClaims struct {
 func claimConverter(claims *bauth.ScopedClaims) interface{} {
 	userID, _ := strconv.ParseUint(claims.Subject, 10, 32)
 	return &User{
-		ID:      uint(userID),
-		Login:   claims.Text, // This is needed for CICD virtual user tokens
-		Virtual: claims.Type == CICDHookTokenType,
+		ID:            uint(userID),
+		Login:         claims.Text, // This is needed for CICD virtual user tokens
+		Virtual:       claims.Type == CICDHookTokenType,
+		TokenType:     claims.Type,
+		TokenScope:    parseTokenScopeClaim(claims.Scope),
+		TokenID:       claims.Id,
+		TokenIssuedAt: time.Unix(claims.IssuedAt, 0),
 	}
 }
 
@@ -138,12 +142,9 @@ func (c cookieExtractor) ExtractToken(r *http.Request) (string, error) {
 }
 
 type accessManager interface {
-	GrantDefaultAccessToUser(userID string)
-	GrantDefaultAccessToVirtualUser(userID string)
-	AddOrganizationPolicies(orgID uint)
-	GrantOrganizationAccessToUser(userID string, orgID uint)
-	RevokeOrganizationAccessFromUser(userID string, orgID uint)
-	RevokeAllAccessFromUser(userID string)
+	GrantOrganizationAccessToUser(userID string, orgID uint) error
+	RevokeOrganizationAccessFromUser(userID string, orgID uint) error
+	RevokeAllAccessFromUser(userID string) error
 }
 
 type redirector struct {
@@ -198,6 +199,7 @@ func Init(db *gorm.DB, accessManager accessManager, orgImporter *OrgImporter) {
 			SigningMethod:  jwt.SigningMethodHS256,
 			SignedString:   signingKeyBase32,
 		},
+		orgImporter: orgImporter,
 	}
 
 	// Initialize Auth with configuration
@@ -219,7 +221,7 @@ func Init(db *gorm.DB, accessManager accessManager, orgImporter *OrgImporter) {
 		DeregisterHandler: NewBanzaiDeregisterHandler(accessManager),
 	})
 
-	dexProvider := dex.New(&dex.Config{
+	dexProvider := newDexProvider(&dex.Config{
 		ClientID:     viper.GetString("auth.clientid"),
 		ClientSecret: viper.GetString("auth.clientsecret"),
 		IssuerURL:    viper.GetString("auth.dexURL"),
@@ -228,7 +230,17 @@ func Init(db *gorm.DB, accessManager accessManager, orgImporter *OrgImporter) {
 
 	InitTokenStore()
 
-	Handler = bauth.JWTAuth(TokenStore, signingKey, claimConverter, cookieExtractor{sessionStorer})
+	jwtHandler := bauth.JWTAuth(TokenStore, signingKey, claimConverter, cookieExtractor{sessionStorer})
+	tokenUsageRecorder := newTokenUsageRecorder(config.DB())
+
+	Handler = func(c *gin.Context) {
+		jwtHandler(c)
+		if c.IsAborted() {
+			return
+		}
+
+		tokenUsageRecorder.Middleware(c)
+	}
 }
 
 func InitTokenStore() {
@@ -246,6 +258,11 @@ func StartTokenStoreGC() {
 			} else {
 				log.Info("TokenStore garbage collected")
 			}
+
+			err = revokeRotatedTokens(config.DB())
+			if err != nil {
+				errorHandler.Handle(err)
+			}
 		}
 	}()
 }
@@ -271,19 +288,14 @@ func Install(engine *gin.Engine, generateTokenHandler gin.HandlerFunc) {
 		authGroup.GET("/tokens", GetTokens)
 		authGroup.GET("/tokens/:id", GetTokens)
 		authGroup.DELETE("/tokens/:id", DeleteToken)
+		authGroup.POST("/tokens/:id/rotate", RotateToken)
 	}
 }
 
-type tokenHandler struct {
-	accessManager accessManager
-}
+type tokenHandler struct{}
 
-func NewTokenHandler(accessManager accessManager) *tokenHandler {
-	handler := &tokenHandler{
-		accessManager: accessManager,
-	}
-
-	return handler
+func NewTokenHandler() *tokenHandler {
+	return &tokenHandler{}
 }
 
 //GenerateToken generates token from context
@@ -324,6 +336,11 @@ func (h *tokenHandler) GenerateToken(c *gin.Context) {
 		Name        string     `json:"name,omitempty"`
 		VirtualUser string     `json:"virtualUser,omitempty"`
 		ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+		Scope       *struct {
+			OrganizationID uint   `json:"organizationId" binding:"required"`
+			ClusterIDs     []uint `json:"clusterIds,omitempty"`
+			Access         string `json:"access,omitempty" binding:"omitempty,eq=read|eq=readwrite"`
+		} `json:"scope,omitempty"`
 	}{Name: "generated"}
 
 	if c.Request.Method == http.MethodPost && c.Request.ContentLength > 0 {
@@ -333,8 +350,64 @@ func (h *tokenHandler) GenerateToken(c *gin.Context) {
 		}
 	}
 
+	if !canManageTokens(c, currentUser) {
+		return
+	}
+
+	var scope *TokenScope
+	if tokenRequest.Scope != nil {
+		if tokenRequest.VirtualUser != "" {
+			c.AbortWithError(http.StatusBadRequest, errors.New("virtual user tokens cannot be scoped"))
+			return
+		}
+
+		scope = &TokenScope{
+			OrganizationID: tokenRequest.Scope.OrganizationID,
+			ClusterIDs:     tokenRequest.Scope.ClusterIDs,
+			ReadOnly:       tokenRequest.Scope.Access == TokenAccessRead,
+		}
+
+		if err := validateTokenScope(Auth.GetDB(c.Request), currentUser, scope); err != nil {
+			c.AbortWithError(http.StatusBadRequest, err)
+			return
+		}
+	}
+
 	isForVirtualUser := tokenRequest.VirtualUser != ""
 
+	if isForVirtualUser && isReservedLogin(tokenRequest.VirtualUser) {
+		c.AbortWithError(http.StatusBadRequest, errors.New("virtual user name is reserved"))
+		return
+	}
+
+	var organizationIDs []uint
+	if scope != nil {
+		organizationIDs = []uint{scope.OrganizationID}
+	} else if isForVirtualUser {
+		organization, ok := getVirtualUserOrganization(c, currentUser, tokenRequest.VirtualUser)
+		if !ok {
+			return
+		}
+
+		organizationIDs = []uint{organization.ID}
+	} else {
+		var err error
+		organizationIDs, err = getUserOrganizationIDs(Auth.GetDB(c.Request), currentUser.ID)
+		if err != nil {
+			errorHandler.Handle(err)
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	// tokens cannot outlive the maximum token age of the organizations they can access
+	expiresAt, err := limitTokenExpiry(Auth.GetDB(c.Request), organizationIDs, tokenRequest.ExpiresAt)
+	if err != nil {
+		errorHandler.Handle(err)
+		c.AbortWithError(http.StatusInternalServerError, err)
+		return
+	}
+
 	userID := currentUser.IDString()
 	userLogin := currentUser.Login
 	tokenType := CICDUserTokenType
@@ -344,7 +417,7 @@ func (h *tokenHandler) GenerateToken(c *gin.Context) {
 		tokenType = CICDHookTokenType
 	}
 
-	tokenID, signedToken, err := createAndStoreAPIToken(userID, userLogin, tokenType, tokenRequest.Name, tokenRequest.ExpiresAt, false)
+	tokenID, signedToken, err := createAndStoreAPIToken(userID, userLogin, tokenType, tokenRequest.Name, expiresAt, scope, false)
 
 	if err != nil {
 		err = c.AbortWithError(http.StatusInternalServerError, fmt.Errorf("%s", err))
@@ -352,28 +425,55 @@ func (h *tokenHandler) GenerateToken(c *gin.Context) {
 		return
 	}
 
-	if isForVirtualUser {
-		orgName := GetOrgNameFromVirtualUser(tokenRequest.VirtualUser)
-		organization := Organization{Name: orgName}
-		err = Auth.GetDB(c.Request).
-			Model(currentUser).
-			Where(&organization).
-			Related(&organization, "Organizations").Error
-		if err != nil {
-			statusCode := http.StatusInternalServerError
-			if gorm.IsRecordNotFoundError(err) {
-				statusCode = http.StatusBadRequest
-			}
-			err = c.AbortWithError(statusCode, err)
-			errorHandler.Handle(errors.Wrap(err, "failed to query organization name for virtual user"))
-			return
+	c.JSON(http.StatusOK, gin.H{"id": tokenID, "token": signedToken})
+}
+
+// getVirtualUserOrganization returns the organization of a virtual user if the user is an admin of it,
+// otherwise it replies with an error.
+func getVirtualUserOrganization(c *gin.Context, user *User, virtualUser string) (*Organization, bool) {
+	db := Auth.GetDB(c.Request)
+
+	organization := Organization{Name: GetOrgNameFromVirtualUser(virtualUser)}
+	err := db.
+		Model(user).
+		Where(&organization).
+		Related(&organization, "Organizations").Error
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if gorm.IsRecordNotFoundError(err) {
+			statusCode = http.StatusBadRequest
 		}
+		err = c.AbortWithError(statusCode, err)
+		errorHandler.Handle(errors.Wrap(err, "failed to query organization name for virtual user"))
+		return nil, false
+	}
 
-		h.accessManager.GrantDefaultAccessToVirtualUser(userID)
-		h.accessManager.GrantOrganizationAccessToUser(userID, organization.ID)
+	role, err := GetUserRole(db, user.ID, organization.ID)
+	if err != nil {
+		err = c.AbortWithError(http.StatusInternalServerError, err)
+		errorHandler.Handle(errors.Wrap(err, "failed to query organization role for virtual user"))
+		return nil, false
 	}
 
-	c.JSON(http.StatusOK, gin.H{"id": tokenID, "token": signedToken})
+	if !RoleIncludes(role, RoleAdmin) {
+		c.AbortWithError(http.StatusForbidden, errors.New("only organization admins can create virtual user tokens"))
+		return nil, false
+	}
+
+	return &organization, true
+}
+
+// reservedLoginPrefixes are the login prefixes of technical users, virtual users cannot use them
+var reservedLoginPrefixes = []string{serviceAccountLoginPrefix, "clusters/"}
+
+func isReservedLogin(login string) bool {
+	for _, prefix := range reservedLoginPrefixes {
+		if strings.HasPrefix(login, prefix) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // getClusterUserID maps cluster to a unique identifier for the cluster's technical user
@@ -391,14 +491,12 @@ func (h *tokenHandler) GenerateClusterToken(orgID uint, clusterID uint) (string,
 			}
 		}
 	}
-	tokenID, signedToken, err := createAndStoreAPIToken(userID, userID, ClusterTokenType, userID, nil, true)
+	tokenID, signedToken, err := createAndStoreAPIToken(userID, userID, ClusterTokenType, userID, nil, nil, true)
 	// TODO: handle access by cluster
-	h.accessManager.GrantDefaultAccessToVirtualUser(userID)
-	h.accessManager.GrantOrganizationAccessToUser(userID, orgID)
 	return tokenID, signedToken, err
 }
 
-func createAPIToken(userID string, userLogin string, tokenType bauth.TokenType, expiresAt *time.Time) (string, string, error) {
+func createAPIToken(userID string, userLogin string, tokenType bauth.TokenType, expiresAt *time.Time, scope *TokenScope) (string, string, error) {
 	tokenID := uuid.Must(uuid.NewV4()).String()
 
 	var expiresAtUnix int64
@@ -416,9 +514,9 @@ func createAPIToken(userID string, userLogin string, tokenType bauth.TokenType,
 			Subject:   userID,
 			Id:        tokenID,
 		},
-		Scope: "api:invoke", // "scope" for Pipeline
-		Type:  tokenType,    // "type" for CICD
-		Text:  userLogin,    // "text" for CICD
+		Scope: tokenScopeClaim(scope), // "scope" for Pipeline
+		Type:  tokenType,              // "type" for CICD
+		Text:  userLogin,              // "text" for CICD
 	}
 
 	jwtToken := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -433,8 +531,8 @@ func createAPIToken(userID string, userLogin string, tokenType bauth.TokenType,
 	return tokenID, signedToken, nil
 }
 
-func createAndStoreAPIToken(userID string, userLogin string, tokenType bauth.TokenType, tokenName string, expiresAt *time.Time, storeSecret bool) (string, string, error) {
-	tokenID, signedToken, err := createAPIToken(userID, userLogin, tokenType, expiresAt)
+func createAndStoreAPIToken(userID string, userLogin string, tokenType bauth.TokenType, tokenName string, expiresAt *time.Time, scope *TokenScope, storeSecret bool) (string, string, error) {
+	tokenID, signedToken, err := createAPIToken(userID, userLogin, tokenType, expiresAt, scope)
 	if err != nil {
 		return "", "", err
 	}
@@ -449,31 +547,43 @@ func createAndStoreAPIToken(userID string, userLogin string, tokenType bauth.Tok
 		return "", "", errors.Wrap(err, "failed to store user token")
 	}
 
+	err = saveTokenMetadata(userID, tokenID, scope)
+	if err != nil {
+		return "", "", err
+	}
+
 	return tokenID, signedToken, nil
 }
 
-// GetTokens returns the calling user's access tokens
+// GetTokens returns the calling user's access tokens along with their scope, usage and rotation details
 func GetTokens(c *gin.Context) {
 	currentUser := GetCurrentUser(c.Request)
 	tokenID := c.Param("id")
 
+	metadata, err := findUserTokenMetadata(Auth.GetDB(c.Request), currentUser.IDString())
+	if err != nil {
+		errorHandler.Handle(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
+	}
+
 	if tokenID == "" {
 		tokens, err := TokenStore.List(currentUser.IDString())
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, err)
 		} else {
+			response := make([]TokenResponse, 0, len(tokens))
 			for _, token := range tokens {
-				token.Value = ""
+				response = append(response, newTokenResponse(token, metadata[token.ID]))
 			}
-			c.JSON(http.StatusOK, tokens)
+			c.JSON(http.StatusOK, response)
 		}
 	} else {
 		token, err := TokenStore.Lookup(currentUser.IDString(), tokenID)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, err)
 		} else if token != nil {
-			token.Value = ""
-			c.JSON(http.StatusOK, token)
+			c.JSON(http.StatusOK, newTokenResponse(token, metadata[token.ID]))
 		} else {
 			c.AbortWithStatusJSON(http.StatusNotFound, pkgCommon.ErrorResponse{
 				Code:    http.StatusNotFound,
@@ -489,10 +599,17 @@ func DeleteToken(c *gin.Context) {
 	currentUser := GetCurrentUser(c.Request)
 	tokenID := c.Param("id")
 
+	if !canManageTokens(c, currentUser) {
+		return
+	}
+
 	if tokenID == "" {
 		c.AbortWithStatusJSON(http.StatusBadRequest, fmt.Errorf("Missing token id"))
 	} else {
 		err := TokenStore.Revoke(currentUser.IDString(), tokenID)
+		if err == nil {
+			err = Auth.GetDB(c.Request).Delete(TokenMetadata{}, "token_id = ?", tokenID).Error
+		}
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, err)
 		} else {
@@ -504,6 +621,8 @@ func DeleteToken(c *gin.Context) {
 //BanzaiSessionStorer stores the banzai session
 type BanzaiSessionStorer struct {
 	auth.SessionStorer
+
+	orgImporter *OrgImporter
 }
 
 //Update updates the BanzaiSessionStorer
@@ -520,10 +639,24 @@ func (sessionStorer *BanzaiSessionStorer) Update(w http.ResponseWriter, req *htt
 		return fmt.Errorf("Can't get current user")
 	}
 
+	// Pending organization invitations are accepted and group memberships are synchronized on login
+	if sessionStorer.orgImporter != nil {
+		if err := sessionStorer.orgImporter.AcceptInvitations(currentUser); err != nil {
+			errorHandler.Handle(errors.WithMessage(err, "failed to accept organization invitations"))
+		}
+
+		if groups, ok := getDexGroupsFromRequest(req); ok && sessionStorer.orgImporter.groupMapper != nil {
+			err := sessionStorer.orgImporter.SyncGroupMemberships(currentUser, groups, getBackendProvider(claims.Provider))
+			if err != nil {
+				errorHandler.Handle(errors.WithMessage(err, "failed to synchronize group memberships"))
+			}
+		}
+	}
+
 	// These tokens are GCd after they expire
 	expiresAt := time.Now().Add(SessionCookieMaxAge * time.Second)
 
-	_, cookieToken, err := createAndStoreAPIToken(claims.UserID, currentUser.Login, CICDUserTokenType, SessionCookieName, &expiresAt, false)
+	_, cookieToken, err := createAndStoreAPIToken(claims.UserID, currentUser.Login, CICDUserTokenType, SessionCookieName, &expiresAt, nil, false)
 	if err != nil {
 		errorHandler.Handle(errors.Wrap(err, "failed to create user session cookie"))
 		return err
@@ -569,14 +702,15 @@ func (h *banzaiDeregisterHandler) handler(context *auth.Context) {
 
 	userAdminOrganizations := []UserOrganization{}
 
-	// Query the organizations where the only admin is the current user.
+	// Query the organizations where the only owner or admin is the current user.
 	sql :=
-		`SELECT * FROM user_organizations WHERE role = ? AND organization_id IN
-		(SELECT DISTINCT organization_id FROM user_organizations WHERE user_id = ? AND role = ?)
+		`SELECT * FROM user_organizations WHERE role IN (?) AND organization_id IN
+		(SELECT DISTINCT organization_id FROM user_organizations WHERE user_id = ? AND role IN (?))
 		GROUP BY user_id, organization_id
 		HAVING COUNT(*) = 1`
 
-	if err := db.Raw(sql, "admin", user.ID, "admin").Scan(&userAdminOrganizations).Error; err != nil {
+	adminRoles := []string{RoleOwner, RoleAdmin}
+	if err := db.Raw(sql, adminRoles, user.ID, adminRoles).Scan(&userAdminOrganizations).Error; err != nil {
 		errorHandler.Handle(errors.Wrap(err, "failed select user only owned organizations"))
 		http.Error(context.Writer, err.Error(), http.StatusInternalServerError)
 		return
@@ -638,7 +772,11 @@ func (h *banzaiDeregisterHandler) handler(context *auth.Context) {
 	}
 
 	// Delete authorization roles for user
-	h.accessManager.RevokeAllAccessFromUser(user.IDString())
+	if err := h.accessManager.RevokeAllAccessFromUser(user.IDString()); err != nil {
+		errorHandler.Handle(err)
+		http.Error(context.Writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
 
 	BanzaiLogoutHandler(context)
 }
@@ -0,0 +1,205 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	oidc "github.com/coreos/go-oidc"
+	"github.com/pkg/errors"
+	"github.com/qor/auth"
+	"github.com/qor/auth/auth_identity"
+	"github.com/qor/auth/claims"
+	"github.com/qor/auth/providers/dex"
+	"github.com/qor/qor/utils"
+	"golang.org/x/oauth2"
+)
+
+// dexGroups holds the identity provider groups of the user logging in through Dex
+const dexGroups utils.ContextKey = "dexGroups"
+
+// dexIDTokenClaims are the claims of the ID tokens issued by Dex
+type dexIDTokenClaims struct {
+	Subject         string            `json:"sub"`
+	Name            string            `json:"name"`
+	Email           string            `json:"email"`
+	Verified        bool              `json:"email_verified"`
+	Groups          []string          `json:"groups"`
+	FederatedClaims map[string]string `json:"federated_claims"`
+}
+
+// newDexProvider returns a Dex login provider.
+// The default authorize handler of the provider keeps the ID token to itself,
+// so this one passes the groups of the user to the session storer as well,
+// in order to synchronize group memberships on every login, not only on sign up.
+// The provider discovers the Dex endpoints, the verifier only needs its signing keys.
+func newDexProvider(config *dex.Config) *dex.DexProvider {
+	keySet := oidc.NewRemoteKeySet(
+		oidc.ClientContext(context.Background(), http.DefaultClient),
+		strings.TrimSuffix(config.IssuerURL, "/")+"/keys",
+	)
+	verifier := oidc.NewVerifier(config.IssuerURL, keySet, &oidc.Config{ClientID: config.ClientID})
+
+	var provider *dex.DexProvider
+
+	config.AuthorizeHandler = func(authCtx *auth.Context) (*claims.Claims, error) {
+		idClaims, err := verifyDexUser(authCtx, provider.OAuthConfig(authCtx), verifier)
+		if err != nil {
+			http.Error(authCtx.Writer, err.Error(), http.StatusBadRequest)
+			return nil, err
+		}
+
+		groups := idClaims.Groups
+		if groups == nil {
+			groups = []string{}
+		}
+		authCtx.Request = authCtx.Request.WithContext(context.WithValue(authCtx.Request.Context(), dexGroups, groups))
+
+		return findOrCreateDexIdentity(authCtx, idClaims)
+	}
+
+	provider = dex.New(config)
+
+	return provider
+}
+
+// verifyDexUser exchanges the authorization code (or refresh token) of a login for a token
+// and returns the claims of its verified ID token.
+func verifyDexUser(authCtx *auth.Context, oauth2Config *oauth2.Config, verifier *oidc.IDTokenVerifier) (*dexIDTokenClaims, error) {
+	req := authCtx.Request
+	ctx := oidc.ClientContext(req.Context(), http.DefaultClient)
+
+	var (
+		token *oauth2.Token
+		err   error
+	)
+
+	switch req.Method {
+	case http.MethodGet:
+		// Authorization redirect callback from OAuth2 auth flow.
+		if errMsg := req.FormValue("error"); errMsg != "" {
+			return nil, errors.New(errMsg + ": " + req.FormValue("error_description"))
+		}
+
+		if err := validateDexState(authCtx, req.FormValue("state")); err != nil {
+			return nil, err
+		}
+
+		token, err = oauth2Config.Exchange(ctx, req.FormValue("code"))
+
+	case http.MethodPost:
+		// Form request from frontend to refresh a token.
+		expired := &oauth2.Token{
+			RefreshToken: req.FormValue("refresh_token"),
+			Expiry:       time.Now().Add(-time.Hour),
+		}
+
+		token, err = oauth2Config.TokenSource(ctx, expired).Token()
+
+	default:
+		return nil, errors.Errorf("method not implemented: %s", req.Method)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get token")
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("no id_token in token response")
+	}
+
+	idToken, err := verifier.Verify(req.Context(), rawIDToken)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify ID token")
+	}
+
+	var idClaims dexIDTokenClaims
+	if err := idToken.Claims(&idClaims); err != nil {
+		return nil, errors.Wrap(err, "failed to parse claims")
+	}
+
+	return &idClaims, nil
+}
+
+func validateDexState(authCtx *auth.Context, state string) error {
+	stateClaims, err := authCtx.Auth.SessionStorer.ValidateClaims(state)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate state claims")
+	}
+
+	if err := stateClaims.Valid(); err != nil {
+		return errors.Wrap(err, "failed to validate state claims")
+	}
+
+	if stateClaims.Subject != "state" {
+		return errors.Errorf("state parameter doesn't match: %s", stateClaims.Subject)
+	}
+
+	return nil
+}
+
+// findOrCreateDexIdentity returns the identity of a Dex user, signing up the user if necessary.
+func findOrCreateDexIdentity(authCtx *auth.Context, idClaims *dexIDTokenClaims) (*claims.Claims, error) {
+	tx := authCtx.Auth.GetDB(authCtx.Request)
+
+	// Check if authInfo exists with the backend connector already, then with Dex
+	candidates := []auth_identity.Basic{
+		{Provider: idClaims.FederatedClaims["connector_id"], UID: idClaims.FederatedClaims["user_id"]},
+		{Provider: "dex:" + idClaims.FederatedClaims["connector_id"], UID: idClaims.Subject},
+	}
+
+	for _, authInfo := range candidates {
+		if !tx.Model(&AuthIdentity{}).Where(authInfo).Scan(&authInfo).RecordNotFound() {
+			return authInfo.ToClaims(), nil
+		}
+	}
+
+	// Create a new account otherwise
+	authInfo := candidates[1]
+	authCtx.Request = authCtx.Request.WithContext(context.WithValue(authCtx.Request.Context(), SignUp, true))
+
+	schema := auth.Schema{
+		Provider: authInfo.Provider,
+		UID:      idClaims.Subject,
+		Name:     idClaims.Name,
+		Email:    idClaims.Email,
+		RawInfo:  *idClaims,
+	}
+
+	_, userID, err := authCtx.Auth.UserStorer.Save(&schema, authCtx)
+	if err != nil {
+		return nil, err
+	}
+	if userID != "" {
+		authInfo.UserID = userID
+	}
+
+	if err := tx.Where(authInfo).FirstOrCreate(&AuthIdentity{}).Error; err != nil {
+		return nil, err
+	}
+
+	return authInfo.ToClaims(), nil
+}
+
+// getDexGroupsFromRequest returns the identity provider groups of the user logging in.
+func getDexGroupsFromRequest(req *http.Request) ([]string, bool) {
+	groups, ok := req.Context().Value(dexGroups).([]string)
+
+	return groups, ok
+}
@@ -0,0 +1,74 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/qor/auth"
+	"github.com/qor/auth/auth_identity"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOrCreateDexIdentity_ExistingIdentity(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.AutoMigrate(&AuthIdentity{}).Error)
+	require.NoError(t, db.Create(&AuthIdentity{Basic: auth_identity.Basic{Provider: "github", UID: "1234", UserID: "1"}}).Error)
+	require.NoError(t, db.Create(&AuthIdentity{Basic: auth_identity.Basic{Provider: "dex:gitlab", UID: "dex-subject", UserID: "2"}}).Error)
+
+	authCtx := &auth.Context{
+		Auth:    &auth.Auth{Config: &auth.Config{DB: db}},
+		Request: httptest.NewRequest(http.MethodGet, "/auth/dex/callback", nil),
+	}
+
+	tests := map[string]struct {
+		idClaims       dexIDTokenClaims
+		expectedUserID string
+	}{
+		"backend connector identity": {
+			idClaims: dexIDTokenClaims{
+				Subject:         "other-subject",
+				FederatedClaims: map[string]string{"connector_id": "github", "user_id": "1234"},
+			},
+			expectedUserID: "1",
+		},
+		"dex identity": {
+			idClaims: dexIDTokenClaims{
+				Subject:         "dex-subject",
+				FederatedClaims: map[string]string{"connector_id": "gitlab", "user_id": "5678"},
+			},
+			expectedUserID: "2",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			claims, err := findOrCreateDexIdentity(authCtx, &test.idClaims)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expectedUserID, claims.UserID)
+		})
+	}
+}
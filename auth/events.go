@@ -17,10 +17,14 @@ package auth
 // OrganizationRegisteredTopic is the name of the topic where organization registration events are published.
 const OrganizationRegisteredTopic = "organization_registered"
 
+// OrganizationMemberRemovedTopic is the name of the topic where events of users leaving organizations are published.
+const OrganizationMemberRemovedTopic = "organization_member_removed"
+
 // authEvents is responsible for dispatching domain events throughout the system.
 // It does not express any infrastructural detail (like pubsub).
 type authEvents interface {
 	OrganizationRegistered(organizationID uint, userID uint)
+	OrganizationMemberRemoved(organizationID uint, userID uint)
 }
 
 type eventBus interface {
@@ -31,6 +35,15 @@ type ebAuthEvents struct {
 	eb eventBus
 }
 
+// NewEvents returns a new auth event dispatcher publishing to an event bus.
+func NewEvents(eb eventBus) ebAuthEvents {
+	return ebAuthEvents{eb: eb}
+}
+
 func (e ebAuthEvents) OrganizationRegistered(organizationID uint, userID uint) {
 	e.eb.Publish(OrganizationRegisteredTopic, organizationID, userID)
 }
+
+func (e ebAuthEvents) OrganizationMemberRemoved(organizationID uint, userID uint) {
+	e.eb.Publish(OrganizationMemberRemovedTopic, organizationID, userID)
+}
@@ -87,10 +87,16 @@ func getGithubOrganizations(token string) ([]organization, error) {
 
 	var orgs []organization
 	for _, membership := range memberships {
+		// GitHub organization owners have the admin role
+		role := RoleMember
+		if membership.GetRole() == "admin" {
+			role = RoleOwner
+		}
+
 		org := organization{
 			name:     membership.GetOrganization().GetLogin(),
 			id:       membership.GetOrganization().GetID(),
-			role:     membership.GetRole(),
+			role:     role,
 			provider: ProviderGithub,
 		}
 
@@ -104,7 +110,7 @@ func getGithubOrganizations(token string) ([]organization, error) {
 
 	userOrg := organization{
 		name:     *user.Login,
-		role:     "admin",
+		role:     RoleOwner,
 		provider: ProviderGithub,
 	}
 
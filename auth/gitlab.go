@@ -113,7 +113,7 @@ func getGitlabOrganizations(token string) ([]organization, error) {
 
 	userOrg := organization{
 		name:     currentUser.Username,
-		role:     "admin",
+		role:     RoleOwner,
 		provider: ProviderGitlab,
 	}
 
@@ -129,9 +129,11 @@ func getGroupAccesLevel(gitlabClient *gitlab.Client, groupID int, userID int) (s
 		return "", emperror.With(err, "userID", userID, "groupID", groupID)
 	}
 	role := map[int]string{
-		30: "DeveloperPermissions",
-		40: "MaintainerPermissions",
-		50: "OwnerPermissions",
+		10: RoleViewer, // Guest
+		20: RoleViewer, // Reporter
+		30: RoleMember, // Developer
+		40: RoleAdmin,  // Maintainer
+		50: RoleOwner,  // Owner
 	}
 
 	return role[int(groupMember.AccessLevel)], nil
@@ -0,0 +1,184 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"regexp"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// GroupMapping maps identity provider groups to an organization role.
+type GroupMapping struct {
+	// Group is a regular expression matched against the whole group name.
+	Group string
+
+	// Organization is the name of the organization,
+	// it may reference submatches of the group pattern (eg. $1 or ${name}).
+	Organization string
+
+	// Role is the role of the group members in the organization.
+	Role string
+}
+
+type groupMappingRule struct {
+	group        *regexp.Regexp
+	organization string
+	role         string
+}
+
+// GroupMapper decides the organization memberships of users based on their identity provider groups.
+type GroupMapper struct {
+	rules []groupMappingRule
+}
+
+// NewGroupMapper returns a new GroupMapper instance.
+func NewGroupMapper(mappings []GroupMapping) (*GroupMapper, error) {
+	rules := make([]groupMappingRule, 0, len(mappings))
+
+	for _, mapping := range mappings {
+		if mapping.Organization == "" {
+			return nil, errors.Errorf("missing organization in group mapping for %q", mapping.Group)
+		}
+
+		if !IsValidRole(mapping.Role) {
+			return nil, errors.Errorf("invalid role in group mapping for %q: %q", mapping.Group, mapping.Role)
+		}
+
+		group, err := regexp.Compile("^(?:" + mapping.Group + ")$")
+		if err != nil {
+			return nil, emperror.WrapWith(err, "invalid group pattern in group mapping", "group", mapping.Group)
+		}
+
+		rules = append(rules, groupMappingRule{
+			group:        group,
+			organization: mapping.Organization,
+			role:         mapping.Role,
+		})
+	}
+
+	return &GroupMapper{rules: rules}, nil
+}
+
+// Map returns the roles of the given groups' members by organization name.
+// When multiple groups grant access to the same organization the highest role wins.
+func (m *GroupMapper) Map(groups []string) map[string]string {
+	roles := make(map[string]string)
+
+	for _, group := range groups {
+		for _, rule := range m.rules {
+			match := rule.group.FindStringSubmatchIndex(group)
+			if match == nil {
+				continue
+			}
+
+			organization := string(rule.group.ExpandString(nil, rule.organization, group, match))
+			if organization == "" {
+				continue
+			}
+
+			if role, ok := roles[organization]; !ok || !RoleIncludes(role, rule.role) {
+				roles[organization] = rule.role
+			}
+		}
+	}
+
+	return roles
+}
+
+// SyncGroupMemberships adds the user to the organizations its groups are mapped to
+// and removes it from the ones it got access to through groups it is no longer a member of.
+// Memberships granted in any other way (eg. through the API or invitations) are left untouched,
+// unless a group mapping takes them over.
+func (i *OrgImporter) SyncGroupMemberships(currentUser *User, groups []string, provider string) error {
+	roles := i.groupMapper.Map(groups)
+
+	var managedMemberships []UserOrganization
+
+	err := i.db.Where("user_id = ? AND group_managed = ?", currentUser.ID, true).Find(&managedMemberships).Error
+	if err != nil {
+		return errors.Wrap(err, "failed to query group managed memberships")
+	}
+
+	mappedOrganizations := make(map[uint]bool, len(roles))
+
+	for name, role := range roles {
+		organization, err := i.syncGroupMembership(currentUser, name, role, provider)
+		if err != nil {
+			return err
+		}
+
+		mappedOrganizations[organization.ID] = true
+	}
+
+	for _, membership := range managedMemberships {
+		if mappedOrganizations[membership.OrganizationID] {
+			continue
+		}
+
+		err := i.accessManager.RevokeOrganizationAccessFromUser(currentUser.IDString(), membership.OrganizationID)
+		if err != nil {
+			return emperror.WrapWith(err, "failed to remove user from organization", "organization", membership.OrganizationID)
+		}
+
+		i.events.OrganizationMemberRemoved(membership.OrganizationID, currentUser.ID)
+	}
+
+	return nil
+}
+
+func (i *OrgImporter) syncGroupMembership(currentUser *User, name string, role string, provider string) (*Organization, error) {
+	organization := Organization{Name: name}
+	created := false
+
+	err := i.db.Where(organization).First(&organization).Error
+	if gorm.IsRecordNotFoundError(err) {
+		organization.Provider = provider
+
+		if err := i.db.Create(&organization).Error; err != nil {
+			return nil, emperror.WrapWith(err, "failed to create organization", "organization", name)
+		}
+
+		created = true
+	} else if err != nil {
+		return nil, emperror.WrapWith(err, "failed to query organization", "organization", name)
+	}
+
+	membership := UserOrganization{UserID: currentUser.ID, OrganizationID: organization.ID}
+
+	err = i.db.Where(membership).First(&UserOrganization{}).Error
+	if gorm.IsRecordNotFoundError(err) {
+		membership.Role = role
+		membership.GroupManaged = true
+
+		err = i.db.Create(&membership).Error
+	} else if err == nil {
+		err = i.db.Model(&UserOrganization{}).Where(membership).Updates(map[string]interface{}{
+			"role":          role,
+			"group_managed": true,
+		}).Error
+	}
+	if err != nil {
+		return nil, emperror.WrapWith(err, "failed to save user role in organization", "organization", name)
+	}
+
+	if created {
+		i.events.OrganizationRegistered(organization.ID, currentUser.ID)
+	}
+
+	return &organization, nil
+}
@@ -0,0 +1,139 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupMapper_Map(t *testing.T) {
+	t.Parallel()
+
+	mapper, err := NewGroupMapper([]GroupMapping{
+		{Group: "pipeline-admins", Organization: "platform", Role: RoleAdmin},
+		{Group: "pipeline-users", Organization: "platform", Role: RoleViewer},
+		{Group: "team-(?P<team>[a-z]+)", Organization: "${team}", Role: RoleMember},
+		{Group: "team-([a-z]+)-leads", Organization: "$1", Role: RoleOwner},
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		groups   []string
+		expected map[string]string
+	}{
+		"no groups":          {nil, map[string]string{}},
+		"unmapped group":     {[]string{"developers"}, map[string]string{}},
+		"static mapping":     {[]string{"pipeline-users"}, map[string]string{"platform": RoleViewer}},
+		"highest role wins":  {[]string{"pipeline-users", "pipeline-admins"}, map[string]string{"platform": RoleAdmin}},
+		"submatch":           {[]string{"team-data"}, map[string]string{"data": RoleMember}},
+		"whole group match":  {[]string{"my-team-data"}, map[string]string{}},
+		"multiple orgs":      {[]string{"team-data", "team-web-leads"}, map[string]string{"data": RoleMember, "web": RoleOwner}},
+		"lower role ignored": {[]string{"team-web-leads", "team-web"}, map[string]string{"web": RoleOwner}},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, mapper.Map(test.groups))
+		})
+	}
+}
+
+func TestNewGroupMapper_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]GroupMapping{
+		"invalid role":         {Group: "admins", Organization: "platform", Role: "superuser"},
+		"missing organization": {Group: "admins", Role: RoleAdmin},
+		"invalid pattern":      {Group: "admins(", Organization: "platform", Role: RoleAdmin},
+	}
+
+	for name, mapping := range tests {
+		name, mapping := name, mapping
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewGroupMapper([]GroupMapping{mapping})
+
+			assert.Error(t, err)
+		})
+	}
+}
+
+type accessManagerStub struct {
+	db      *gorm.DB
+	revoked []uint
+}
+
+func (*accessManagerStub) GrantOrganizationAccessToUser(userID string, orgID uint) error { return nil }
+func (*accessManagerStub) RevokeAllAccessFromUser(userID string) error                   { return nil }
+
+func (m *accessManagerStub) RevokeOrganizationAccessFromUser(userID string, orgID uint) error {
+	m.revoked = append(m.revoked, orgID)
+
+	id, _ := strconv.ParseUint(userID, 10, 32)
+
+	return m.db.Where(UserOrganization{UserID: uint(id), OrganizationID: orgID}).Delete(UserOrganization{}).Error
+}
+
+type eventBusStub struct{}
+
+func (eventBusStub) Publish(topic string, args ...interface{}) {}
+
+func TestOrgImporter_SyncGroupMemberships_RevokesRemovedGroups(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.AutoMigrate(&User{}, &Organization{}, &UserOrganization{}).Error)
+
+	mapper, err := NewGroupMapper([]GroupMapping{
+		{Group: "team-(?P<team>[a-z]+)", Organization: "${team}", Role: RoleMember},
+	})
+	require.NoError(t, err)
+
+	accessManager := &accessManagerStub{db: db}
+	importer := NewOrgImporter(db, accessManager, eventBusStub{}, mapper)
+
+	user := &User{Login: "john"}
+	require.NoError(t, db.Create(user).Error)
+
+	require.NoError(t, importer.SyncGroupMemberships(user, []string{"team-data", "team-web"}, ProviderGithub))
+
+	var memberships []UserOrganization
+	require.NoError(t, db.Where(UserOrganization{UserID: user.ID}).Find(&memberships).Error)
+	assert.Len(t, memberships, 2)
+
+	var data Organization
+	require.NoError(t, db.Where(Organization{Name: "data"}).First(&data).Error)
+
+	require.NoError(t, importer.SyncGroupMemberships(user, []string{"team-web"}, ProviderGithub))
+
+	memberships = nil
+	require.NoError(t, db.Where(UserOrganization{UserID: user.ID}).Find(&memberships).Error)
+	require.Len(t, memberships, 1)
+	assert.NotEqual(t, data.ID, memberships[0].OrganizationID)
+	assert.Equal(t, []uint{data.ID}, accessManager.revoked)
+}
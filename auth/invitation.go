@@ -0,0 +1,117 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"strings"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// InvitationTTL is how long an organization invitation can be accepted.
+const InvitationTTL = 7 * 24 * time.Hour
+
+// OrganizationInvitation invites a user (identified by login name or email address) to an organization.
+// Invitations are accepted when the invited user logs in the next time.
+type OrganizationInvitation struct {
+	ID             uint      `gorm:"primary_key" json:"id"`
+	OrganizationID uint      `gorm:"not null;index" json:"organizationId"`
+	Login          string    `gorm:"index" json:"login,omitempty"`
+	Email          string    `gorm:"index" json:"email,omitempty"`
+	Role           string    `gorm:"not null" json:"role"`
+	InvitedBy      uint      `json:"invitedBy"`
+	CreatedAt      time.Time `json:"createdAt"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+}
+
+// TableName changes the default table name.
+func (OrganizationInvitation) TableName() string {
+	return "organization_invitations"
+}
+
+// NormalizeInvitationEmail returns the email address in the form invitations are stored and matched.
+func NormalizeInvitationEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// GetPendingInvitations returns the invitations of an organization which are not accepted and not expired yet.
+func GetPendingInvitations(db *gorm.DB, orgID uint) ([]OrganizationInvitation, error) {
+	invitations := make([]OrganizationInvitation, 0)
+
+	err := db.Where("organization_id = ? AND expires_at > ?", orgID, time.Now()).Order("created_at").Find(&invitations).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query organization invitations")
+	}
+
+	return invitations, nil
+}
+
+// AcceptInvitations adds the user to the organizations it has been invited to.
+// Accepted and expired invitations of the user are deleted.
+// Invitations never change the role of an existing member.
+func (i *OrgImporter) AcceptInvitations(currentUser *User) error {
+	query := i.db.Where("login = ?", currentUser.Login)
+	if email := NormalizeInvitationEmail(currentUser.Email); email != "" {
+		query = i.db.Where("login = ? OR email = ?", currentUser.Login, email)
+	}
+
+	var invitations []OrganizationInvitation
+	if err := query.Find(&invitations).Error; err != nil {
+		return errors.Wrap(err, "failed to query organization invitations")
+	}
+
+	now := time.Now()
+
+	for _, invitation := range invitations {
+		if invitation.ExpiresAt.After(now) {
+			if err := i.acceptInvitation(currentUser, invitation); err != nil {
+				return err
+			}
+		}
+
+		if err := i.db.Delete(invitation).Error; err != nil {
+			return errors.Wrap(err, "failed to delete organization invitation")
+		}
+	}
+
+	return nil
+}
+
+func (i *OrgImporter) acceptInvitation(currentUser *User, invitation OrganizationInvitation) error {
+	role, err := GetUserRole(i.db, currentUser.ID, invitation.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	// already a member
+	if role != "" {
+		return nil
+	}
+
+	membership := UserOrganization{
+		UserID:         currentUser.ID,
+		OrganizationID: invitation.OrganizationID,
+		Role:           invitation.Role,
+	}
+
+	if err := i.db.Create(&membership).Error; err != nil {
+		return emperror.WrapWith(err, "failed to associate user with organization", "organization", invitation.OrganizationID)
+	}
+
+	return i.accessManager.GrantOrganizationAccessToUser(currentUser.IDString(), invitation.OrganizationID)
+}
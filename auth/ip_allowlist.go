@@ -0,0 +1,232 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	jwtRequest "github.com/dgrijalva/jwt-go/request"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// OrganizationIPAllowlist describes the network ranges an organization accepts API requests from.
+// API tokens and browser sessions are restricted separately, an empty list allows any address.
+type OrganizationIPAllowlist struct {
+	OrganizationID uint `gorm:"primary_key;auto_increment:false"`
+
+	// APITokenRanges and SessionRanges are comma separated CIDR ranges
+	APITokenRanges string `gorm:"type:text"`
+	SessionRanges  string `gorm:"type:text"`
+
+	UpdatedAt time.Time
+}
+
+// TableName changes the default table name.
+func (OrganizationIPAllowlist) TableName() string {
+	return "organization_ip_allowlists"
+}
+
+// Allows checks whether a request from the given address is accepted by the organization.
+func (a OrganizationIPAllowlist) Allows(ip net.IP, session bool) bool {
+	ranges := a.APITokenRanges
+	if session {
+		ranges = a.SessionRanges
+	}
+
+	if ranges == "" {
+		return true
+	}
+
+	if ip == nil {
+		return false
+	}
+
+	networks, err := parseCIDRs(splitRanges(ranges))
+	if err != nil {
+		return false
+	}
+
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetOrganizationIPAllowlist returns the IP allowlist of an organization.
+// Organizations without an allowlist get an unrestricted one.
+func GetOrganizationIPAllowlist(db *gorm.DB, orgID uint) (*OrganizationIPAllowlist, error) {
+	allowlist := OrganizationIPAllowlist{OrganizationID: orgID}
+
+	err := db.Where(OrganizationIPAllowlist{OrganizationID: orgID}).First(&allowlist).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return nil, errors.Wrap(err, "failed to query organization IP allowlist")
+	}
+
+	return &allowlist, nil
+}
+
+// IsSessionRequest checks whether a request is authenticated by a browser session rather than an API token.
+// API tokens sent in the Authorization header (or access_token parameter) take precedence over the session cookie.
+func IsSessionRequest(r *http.Request) bool {
+	if _, err := jwtRequest.OAuth2Extractor.ExtractToken(r); err == nil {
+		return false
+	}
+
+	_, err := r.Cookie(PipelineSessionCookie)
+
+	return err == nil
+}
+
+// IPAllowlistRequest describes the IP allowlist of an organization.
+type IPAllowlistRequest struct {
+	// APITokens and Sessions are lists of CIDR ranges (eg. 10.0.0.0/8), empty means any address
+	APITokens []string `json:"apiTokens"`
+	Sessions  []string `json:"sessions"`
+}
+
+// GetIPAllowlist returns the IP allowlist of the current organization.
+func GetIPAllowlist(c *gin.Context) {
+	organization := GetCurrentOrganization(c.Request)
+
+	allowlist, err := GetOrganizationIPAllowlist(Auth.GetDB(c.Request), organization.ID)
+	if err != nil {
+		errorHandler.Handle(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get IP allowlist",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newIPAllowlistRequest(allowlist))
+}
+
+// SetIPAllowlist sets the IP allowlist of the current organization.
+// The change is rejected if it would lock out the client making the request.
+func SetIPAllowlist(c *gin.Context) {
+	var request IPAllowlistRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	allowlist, err := newOrganizationIPAllowlist(GetCurrentOrganization(c.Request).ID, request)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "IP allowlist entries must be CIDR ranges (eg. 10.0.0.0/8)",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if !allowlist.Allows(net.ParseIP(c.ClientIP()), IsSessionRequest(c.Request)) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "The IP allowlist would block your current address",
+			Error:   "client address is not allowed",
+		})
+		return
+	}
+
+	if err := Auth.GetDB(c.Request).Save(allowlist).Error; err != nil {
+		errorHandler.Handle(errors.Wrap(err, "failed to save organization IP allowlist"))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to save IP allowlist",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, newIPAllowlistRequest(allowlist))
+}
+
+// newOrganizationIPAllowlist validates the requested CIDR ranges and stores them in canonical form.
+func newOrganizationIPAllowlist(orgID uint, request IPAllowlistRequest) (*OrganizationIPAllowlist, error) {
+	apiTokenRanges, err := normalizeCIDRs(request.APITokens)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionRanges, err := normalizeCIDRs(request.Sessions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrganizationIPAllowlist{
+		OrganizationID: orgID,
+		APITokenRanges: apiTokenRanges,
+		SessionRanges:  sessionRanges,
+	}, nil
+}
+
+func newIPAllowlistRequest(allowlist *OrganizationIPAllowlist) IPAllowlistRequest {
+	return IPAllowlistRequest{
+		APITokens: splitRanges(allowlist.APITokenRanges),
+		Sessions:  splitRanges(allowlist.SessionRanges),
+	}
+}
+
+func splitRanges(ranges string) []string {
+	if ranges == "" {
+		return []string{}
+	}
+
+	return strings.Split(ranges, ",")
+}
+
+func parseCIDRs(ranges []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(ranges))
+
+	for _, r := range ranges {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(r))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+func normalizeCIDRs(ranges []string) (string, error) {
+	networks, err := parseCIDRs(ranges)
+	if err != nil {
+		return "", err
+	}
+
+	normalized := make([]string, 0, len(networks))
+	for _, network := range networks {
+		normalized = append(normalized, network.String())
+	}
+
+	return strings.Join(normalized, ","), nil
+}
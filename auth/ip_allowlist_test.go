@@ -0,0 +1,80 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizationIPAllowlist_Allows(t *testing.T) {
+	t.Parallel()
+
+	allowlist, err := newOrganizationIPAllowlist(1, IPAllowlistRequest{
+		APITokens: []string{"10.0.0.0/8", " 192.168.1.10/24"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "10.0.0.0/8,192.168.1.0/24", allowlist.APITokenRanges)
+
+	tests := map[string]struct {
+		ip       string
+		session  bool
+		expected bool
+	}{
+		"token inside range":          {"10.1.2.3", false, true},
+		"token inside second range":   {"192.168.1.200", false, true},
+		"token outside range":         {"172.16.0.1", false, false},
+		"token without address":       {"", false, false},
+		"session without restriction": {"172.16.0.1", true, true},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, allowlist.Allows(net.ParseIP(test.ip), test.session))
+		})
+	}
+}
+
+func TestNewOrganizationIPAllowlist_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := newOrganizationIPAllowlist(1, IPAllowlistRequest{Sessions: []string{"10.0.0.1"}})
+
+	assert.Error(t, err)
+}
+
+func TestIsSessionRequest(t *testing.T) {
+	t.Parallel()
+
+	tokenRequest := httptest.NewRequest(http.MethodGet, "/api/v1/orgs/1", nil)
+	tokenRequest.Header.Set("Authorization", "Bearer token")
+	tokenRequest.AddCookie(&http.Cookie{Name: PipelineSessionCookie, Value: "session"})
+
+	sessionRequest := httptest.NewRequest(http.MethodGet, "/api/v1/orgs/1", nil)
+	sessionRequest.AddCookie(&http.Cookie{Name: PipelineSessionCookie, Value: "session"})
+
+	assert.False(t, IsSessionRequest(tokenRequest))
+	assert.True(t, IsSessionRequest(sessionRequest))
+}
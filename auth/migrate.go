@@ -29,6 +29,11 @@ func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
 		&User{},
 		&UserOrganization{},
 		&Organization{},
+		&TokenMetadata{},
+		&OrganizationTokenPolicy{},
+		&ServiceAccount{},
+		&OrganizationInvitation{},
+		&OrganizationIPAllowlist{},
 	}
 
 	var tableNames string
@@ -0,0 +1,82 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// Organization roles
+const (
+	// RoleOwner can do anything in the organization, including deleting it and managing other owners
+	RoleOwner = "owner"
+	// RoleAdmin can do anything in the organization except deleting it and managing owners
+	RoleAdmin = "admin"
+	// RoleMember can manage the resources of the organization, but cannot delete clusters or manage users
+	RoleMember = "member"
+	// RoleViewer has read-only access to the organization, without access to secrets and cluster credentials
+	RoleViewer = "viewer"
+)
+
+// Roles lists the organization roles from the most to the least privileged one.
+var Roles = []string{RoleOwner, RoleAdmin, RoleMember, RoleViewer}
+
+// IsValidRole checks whether a role is a known organization role.
+func IsValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RoleIncludes checks whether a role has at least the privileges of another role.
+// Unknown roles are treated as the least privileged ones.
+func RoleIncludes(role string, other string) bool {
+	return roleRank(role) >= roleRank(other)
+}
+
+func roleRank(role string) int {
+	for i, r := range Roles {
+		if r == role {
+			return len(Roles) - i
+		}
+	}
+
+	return 0
+}
+
+// GetUserRole returns the role of a user in an organization.
+// If the user is not a member of the organization an empty role is returned.
+// Memberships without a role (eg. imported from an identity provider with an unknown access level) get the viewer role.
+func GetUserRole(db *gorm.DB, userID uint, orgID uint) (string, error) {
+	var membership UserOrganization
+
+	err := db.Where(UserOrganization{UserID: userID, OrganizationID: orgID}).First(&membership).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Wrap(err, "failed to query user's role in organization")
+	}
+
+	if membership.Role == "" {
+		return RoleViewer, nil
+	}
+
+	return membership.Role, nil
+}
@@ -0,0 +1,151 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	bauth "github.com/banzaicloud/bank-vaults/pkg/auth"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// ServiceAccountTokenType is the token type of service accounts
+const ServiceAccountTokenType bauth.TokenType = "serviceaccount"
+
+// serviceAccountLoginPrefix is the prefix of the login (and token subject) of service accounts
+const serviceAccountLoginPrefix = "serviceaccounts/"
+
+// ServiceAccount is a non-human identity owned by an organization.
+// Its tokens do not belong to any user, so they keep working when the user who created them leaves.
+type ServiceAccount struct {
+	ID             uint      `gorm:"primary_key" json:"id"`
+	OrganizationID uint      `gorm:"not null;unique_index:idx_service_accounts_organization_id_name" json:"organizationId"`
+	Name           string    `gorm:"not null;size:50;unique_index:idx_service_accounts_organization_id_name" json:"name"`
+	Description    string    `json:"description,omitempty"`
+	Role           string    `gorm:"not null" json:"role"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+	CreatedBy      uint      `json:"createdBy,omitempty"`
+}
+
+// TableName changes the default table name.
+func (ServiceAccount) TableName() string {
+	return "service_accounts"
+}
+
+// Login returns the login name of the service account used as the subject of its tokens.
+func (sa *ServiceAccount) Login() string {
+	return fmt.Sprintf("%s%d", serviceAccountLoginPrefix, sa.ID)
+}
+
+// ServiceAccountID returns the ID of the service account if the user is authenticated as one.
+// Only service account tokens are accepted, other tokens cannot act on behalf of service accounts.
+func (user *User) ServiceAccountID() (uint, bool) {
+	if user.ID != 0 || user.TokenType != ServiceAccountTokenType || !strings.HasPrefix(user.Login, serviceAccountLoginPrefix) {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(strings.TrimPrefix(user.Login, serviceAccountLoginPrefix), 10, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// IsValidServiceAccountRole checks whether a role can be given to service accounts.
+// Service accounts cannot be owners of organizations.
+func IsValidServiceAccountRole(role string) bool {
+	return IsValidRole(role) && role != RoleOwner
+}
+
+// GetServiceAccount returns a service account by ID.
+// If the service account cannot be found nil is returned.
+func GetServiceAccount(db *gorm.DB, id uint) (*ServiceAccount, error) {
+	var serviceAccount ServiceAccount
+
+	err := db.Where(ServiceAccount{ID: id}).First(&serviceAccount).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to query service account")
+	}
+
+	return &serviceAccount, nil
+}
+
+// CreateServiceAccountToken generates and stores a new API token for a service account.
+// The token cannot outlive the maximum token age of the organization.
+func CreateServiceAccountToken(db *gorm.DB, sa *ServiceAccount, name string, expiresAt *time.Time) (string, string, error) {
+	expiresAt, err := limitTokenExpiry(db, []uint{sa.OrganizationID}, expiresAt)
+	if err != nil {
+		return "", "", err
+	}
+
+	return createAndStoreAPIToken(sa.Login(), sa.Login(), ServiceAccountTokenType, name, expiresAt, nil, false)
+}
+
+// GetServiceAccountTokens returns the tokens of a service account along with their usage details.
+func GetServiceAccountTokens(db *gorm.DB, sa *ServiceAccount) ([]TokenResponse, error) {
+	tokens, err := TokenStore.List(sa.Login())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list service account tokens")
+	}
+
+	metadata, err := findUserTokenMetadata(db, sa.Login())
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]TokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		response = append(response, newTokenResponse(token, metadata[token.ID]))
+	}
+
+	return response, nil
+}
+
+// RevokeServiceAccountToken revokes a token of a service account.
+func RevokeServiceAccountToken(db *gorm.DB, sa *ServiceAccount, tokenID string) error {
+	if err := TokenStore.Revoke(sa.Login(), tokenID); err != nil {
+		return errors.Wrap(err, "failed to revoke service account token")
+	}
+
+	if err := db.Delete(TokenMetadata{}, "token_id = ?", tokenID).Error; err != nil {
+		return errors.Wrap(err, "failed to delete token metadata")
+	}
+
+	return nil
+}
+
+// RevokeServiceAccountTokens revokes every token of a service account.
+func RevokeServiceAccountTokens(db *gorm.DB, sa *ServiceAccount) error {
+	tokens, err := TokenStore.List(sa.Login())
+	if err != nil {
+		return errors.Wrap(err, "failed to list service account tokens")
+	}
+
+	for _, token := range tokens {
+		if err := RevokeServiceAccountToken(db, sa, token.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUser_ServiceAccountID(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		user       User
+		expectedID uint
+		expectedOK bool
+	}{
+		"service account":      {User{Login: "serviceaccounts/12", TokenType: ServiceAccountTokenType}, 12, true},
+		"regular user":         {User{ID: 3, Login: "serviceaccounts/12", TokenType: ServiceAccountTokenType}, 0, false},
+		"virtual user":         {User{Login: "org/pipeline", TokenType: CICDHookTokenType}, 0, false},
+		"virtual user login":   {User{Login: "serviceaccounts/12", TokenType: CICDHookTokenType}, 0, false},
+		"malformed login":      {User{Login: "serviceaccounts/abc", TokenType: ServiceAccountTokenType}, 0, false},
+		"cluster virtual user": {User{Login: "clusters/1/2", TokenType: ClusterTokenType}, 0, false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			id, ok := test.user.ServiceAccountID()
+
+			assert.Equal(t, test.expectedID, id)
+			assert.Equal(t, test.expectedOK, ok)
+		})
+	}
+}
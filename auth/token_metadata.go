@@ -0,0 +1,382 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	bauth "github.com/banzaicloud/bank-vaults/pkg/auth"
+	"github.com/banzaicloud/pipeline/config"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// DefaultTokenRotationGracePeriod is how long a rotated token remains valid by default.
+const DefaultTokenRotationGracePeriod = 24 * time.Hour
+
+// tokenUsageRecordInterval limits how often the last usage of a token is written to the database.
+const tokenUsageRecordInterval = time.Minute
+
+// TokenMetadata stores the details of an API token which are not kept by the token store.
+type TokenMetadata struct {
+	TokenID string `gorm:"primary_key;size:36"`
+	UserID  string `gorm:"not null;index"`
+
+	// Scope is the scope claim of the token
+	Scope string
+
+	LastUsedAt *time.Time
+	LastUsedIP string
+
+	// ReplacedBy is the ID of the token replacing this one after a rotation
+	ReplacedBy string
+	// RevokeAt is the end of the grace period of a rotated token
+	RevokeAt *time.Time
+
+	CreatedAt time.Time
+}
+
+// TableName changes the default table name.
+func (TokenMetadata) TableName() string {
+	return "token_metadata"
+}
+
+// TokenResponse describes an API token along with its scope, usage and rotation details.
+type TokenResponse struct {
+	*bauth.Token
+
+	Scope      *TokenScope `json:"scope,omitempty"`
+	LastUsedAt *time.Time  `json:"lastUsedAt,omitempty"`
+	LastUsedIP string      `json:"lastUsedIp,omitempty"`
+	ReplacedBy string      `json:"replacedBy,omitempty"`
+	RevokeAt   *time.Time  `json:"revokeAt,omitempty"`
+}
+
+func newTokenResponse(token *bauth.Token, metadata *TokenMetadata) TokenResponse {
+	token.Value = ""
+
+	response := TokenResponse{Token: token}
+	if metadata != nil {
+		response.Scope = parseTokenScopeClaim(metadata.Scope)
+		response.LastUsedAt = metadata.LastUsedAt
+		response.LastUsedIP = metadata.LastUsedIP
+		response.ReplacedBy = metadata.ReplacedBy
+		response.RevokeAt = metadata.RevokeAt
+	}
+
+	return response
+}
+
+func findTokenMetadata(db *gorm.DB, tokenID string) (*TokenMetadata, error) {
+	var metadata TokenMetadata
+
+	err := db.Where(TokenMetadata{TokenID: tokenID}).First(&metadata).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "failed to query token metadata")
+	}
+
+	return &metadata, nil
+}
+
+func findUserTokenMetadata(db *gorm.DB, userID string) (map[string]*TokenMetadata, error) {
+	var metadata []*TokenMetadata
+
+	err := db.Where(TokenMetadata{UserID: userID}).Find(&metadata).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query token metadata")
+	}
+
+	tokens := make(map[string]*TokenMetadata, len(metadata))
+	for _, m := range metadata {
+		tokens[m.TokenID] = m
+	}
+
+	return tokens, nil
+}
+
+// tokenUsageRecorder records the last usage of tokens and revokes rotated tokens after their grace period.
+type tokenUsageRecorder struct {
+	db *gorm.DB
+
+	// recorded holds the last time the usage of a token was written to the database
+	recorded   map[string]time.Time
+	recordedMu sync.Mutex
+}
+
+func newTokenUsageRecorder(db *gorm.DB) *tokenUsageRecorder {
+	return &tokenUsageRecorder{
+		db:       db,
+		recorded: make(map[string]time.Time),
+	}
+}
+
+// Middleware returns a Gin middleware to be used after the token authentication.
+func (r *tokenUsageRecorder) Middleware(c *gin.Context) {
+	user := GetCurrentUser(c.Request)
+	if user == nil || user.TokenID == "" {
+		return
+	}
+
+	now := time.Now()
+
+	r.recordedMu.Lock()
+	lastRecorded, ok := r.recorded[user.TokenID]
+	r.recordedMu.Unlock()
+
+	if ok && now.Sub(lastRecorded) < tokenUsageRecordInterval {
+		return
+	}
+
+	metadata, err := findTokenMetadata(r.db, user.TokenID)
+	if err != nil {
+		errorHandler.Handle(err)
+		return
+	}
+
+	if metadata == nil {
+		// tokens created before usage tracking
+		metadata = &TokenMetadata{TokenID: user.TokenID, UserID: user.subject(), Scope: tokenScopeClaim(user.TokenScope)}
+	}
+
+	if metadata.RevokeAt != nil && now.After(*metadata.RevokeAt) {
+		if err := revokeRotatedToken(r.db, metadata); err != nil {
+			errorHandler.Handle(err)
+		}
+
+		c.AbortWithStatusJSON(http.StatusUnauthorized, pkgCommon.ErrorResponse{
+			Code:    http.StatusUnauthorized,
+			Message: "Token has been rotated",
+			Error:   "Token has been rotated",
+		})
+		return
+	}
+
+	metadata.LastUsedAt = &now
+	metadata.LastUsedIP = c.ClientIP()
+
+	if err := r.db.Save(metadata).Error; err != nil {
+		errorHandler.Handle(errors.Wrap(err, "failed to record token usage"))
+		return
+	}
+
+	r.recordedMu.Lock()
+	r.recorded[user.TokenID] = now
+	r.recordedMu.Unlock()
+}
+
+// subject returns the subject of the token the user authenticated with.
+func (user *User) subject() string {
+	if user.ID == 0 {
+		return user.Login
+	}
+
+	return user.IDString()
+}
+
+func revokeRotatedToken(db *gorm.DB, metadata *TokenMetadata) error {
+	if err := TokenStore.Revoke(metadata.UserID, metadata.TokenID); err != nil {
+		return errors.Wrap(err, "failed to revoke rotated token")
+	}
+
+	if err := db.Delete(metadata).Error; err != nil {
+		return errors.Wrap(err, "failed to delete token metadata")
+	}
+
+	return nil
+}
+
+// revokeRotatedTokens revokes the rotated tokens whose grace period is over.
+func revokeRotatedTokens(db *gorm.DB) error {
+	var rotated []*TokenMetadata
+
+	err := db.Where("revoke_at <= ?", time.Now()).Find(&rotated).Error
+	if err != nil {
+		return errors.Wrap(err, "failed to query rotated tokens")
+	}
+
+	for _, metadata := range rotated {
+		if err := revokeRotatedToken(db, metadata); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RotateToken issues a replacement for the calling user's access token specified by token id
+// and revokes the old token after a grace period.
+func RotateToken(c *gin.Context) {
+	currentUser := GetCurrentUser(c.Request)
+	tokenID := c.Param("id")
+
+	if !canManageTokens(c, currentUser) {
+		return
+	}
+
+	request := struct {
+		GracePeriod string `json:"gracePeriod,omitempty"`
+	}{}
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Error parsing request",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	gracePeriod := DefaultTokenRotationGracePeriod
+	if request.GracePeriod != "" {
+		var err error
+		gracePeriod, err = time.ParseDuration(request.GracePeriod)
+		if err != nil || gracePeriod < 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Grace period must be a non-negative duration (eg. 1h)",
+				Error:   "invalid grace period",
+			})
+			return
+		}
+	}
+
+	token, err := TokenStore.Lookup(currentUser.IDString(), tokenID)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
+	} else if token == nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, pkgCommon.ErrorResponse{
+			Code:    http.StatusNotFound,
+			Message: "Token not found",
+			Error:   "Token not found",
+		})
+		return
+	}
+
+	db := Auth.GetDB(c.Request)
+
+	metadata, err := findTokenMetadata(db, tokenID)
+	if err != nil {
+		errorHandler.Handle(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
+	}
+
+	if metadata == nil {
+		metadata = &TokenMetadata{TokenID: tokenID, UserID: currentUser.IDString()}
+	} else if metadata.ReplacedBy != "" {
+		c.AbortWithStatusJSON(http.StatusConflict, pkgCommon.ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: "Token has already been rotated",
+			Error:   "Token has already been rotated",
+		})
+		return
+	}
+
+	// the replacement keeps the lifetime of the original token
+	var expiresAt *time.Time
+	if token.ExpiresAt != nil && token.CreatedAt != nil {
+		t := time.Now().Add(token.ExpiresAt.Sub(*token.CreatedAt))
+		expiresAt = &t
+	}
+
+	newTokenID, signedToken, err := createAndStoreAPIToken(
+		currentUser.IDString(),
+		currentUser.Login,
+		CICDUserTokenType,
+		token.Name,
+		expiresAt,
+		parseTokenScopeClaim(metadata.Scope),
+		false,
+	)
+	if err != nil {
+		errorHandler.Handle(errors.Wrap(err, "failed to create and store API token"))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
+	}
+
+	revokeAt := time.Now().Add(gracePeriod)
+	metadata.ReplacedBy = newTokenID
+	metadata.RevokeAt = &revokeAt
+
+	if gracePeriod == 0 {
+		err = revokeRotatedToken(db, metadata)
+	} else {
+		err = db.Save(metadata).Error
+	}
+	if err != nil {
+		errorHandler.Handle(errors.Wrap(err, "failed to schedule rotated token revocation"))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"id": newTokenID, "token": signedToken, "revokeAt": revokeAt})
+}
+
+// canManageTokens checks whether the current user can manage its own tokens and replies with an error if not.
+// Tokens can only be managed with the full power of a human user: scoped tokens and service accounts are not allowed.
+func canManageTokens(c *gin.Context, user *User) bool {
+	return requireFullAccess(c, user, "manage tokens")
+}
+
+// NewFullAccessMiddleware returns a middleware that rejects scoped tokens and service accounts.
+// Routes outside of organizations are not covered by token scope enforcement,
+// so the ones changing the user or its organizations must be protected by this middleware.
+func NewFullAccessMiddleware(action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if user := GetCurrentUser(c.Request); user != nil {
+			requireFullAccess(c, user, action)
+		}
+	}
+}
+
+// requireFullAccess checks whether the user acts with the full power of a human user and replies with an error if not.
+func requireFullAccess(c *gin.Context, user *User, action string) bool {
+	var message string
+
+	if _, ok := user.ServiceAccountID(); ok {
+		message = fmt.Sprintf("Service accounts cannot %s", action)
+	} else if user.TokenScope != nil {
+		message = fmt.Sprintf("Scoped tokens cannot %s", action)
+	} else {
+		return true
+	}
+
+	c.AbortWithStatusJSON(http.StatusForbidden, pkgCommon.ErrorResponse{
+		Code:    http.StatusForbidden,
+		Message: message,
+		Error:   message,
+	})
+
+	return false
+}
+
+func saveTokenMetadata(userID string, tokenID string, scope *TokenScope) error {
+	err := config.DB().Save(&TokenMetadata{TokenID: tokenID, UserID: userID, Scope: tokenScopeClaim(scope)}).Error
+	if err != nil {
+		return errors.Wrap(err, "failed to save token metadata")
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"time"
+
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// OrganizationTokenPolicy describes the restrictions of an organization on API tokens.
+type OrganizationTokenPolicy struct {
+	OrganizationID uint `gorm:"primary_key;auto_increment:false"`
+
+	// MaxTokenAgeSeconds is the maximum age of tokens accepted by the organization (0 means unlimited)
+	MaxTokenAgeSeconds int64 `gorm:"not null"`
+
+	UpdatedAt time.Time
+}
+
+// TableName changes the default table name.
+func (OrganizationTokenPolicy) TableName() string {
+	return "organization_token_policies"
+}
+
+// MaxTokenAge returns the maximum age of tokens accepted by the organization (0 means unlimited).
+func (p OrganizationTokenPolicy) MaxTokenAge() time.Duration {
+	return time.Duration(p.MaxTokenAgeSeconds) * time.Second
+}
+
+// Allows checks whether a token issued at the given time is accepted by the organization.
+func (p OrganizationTokenPolicy) Allows(issuedAt time.Time, now time.Time) bool {
+	return p.MaxTokenAgeSeconds == 0 || now.Sub(issuedAt) <= p.MaxTokenAge()
+}
+
+// GetOrganizationTokenPolicy returns the token policy of an organization.
+// Organizations without a policy get an unrestricted one.
+func GetOrganizationTokenPolicy(db *gorm.DB, orgID uint) (*OrganizationTokenPolicy, error) {
+	policy := OrganizationTokenPolicy{OrganizationID: orgID}
+
+	err := db.Where(OrganizationTokenPolicy{OrganizationID: orgID}).First(&policy).Error
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return nil, errors.Wrap(err, "failed to query organization token policy")
+	}
+
+	return &policy, nil
+}
+
+// limitTokenExpiry limits the expiry of a new token to the strictest maximum token age of the given organizations.
+func limitTokenExpiry(db *gorm.DB, orgIDs []uint, expiresAt *time.Time) (*time.Time, error) {
+	if len(orgIDs) == 0 {
+		return expiresAt, nil
+	}
+
+	var policies []OrganizationTokenPolicy
+
+	err := db.Where("organization_id IN (?) AND max_token_age_seconds > 0", orgIDs).Find(&policies).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query organization token policies")
+	}
+
+	now := time.Now()
+	for _, policy := range policies {
+		maxExpiresAt := now.Add(policy.MaxTokenAge())
+		if expiresAt == nil || expiresAt.After(maxExpiresAt) {
+			expiresAt = &maxExpiresAt
+		}
+	}
+
+	return expiresAt, nil
+}
+
+// getUserOrganizationIDs returns the IDs of the organizations a user is a member of.
+func getUserOrganizationIDs(db *gorm.DB, userID uint) ([]uint, error) {
+	var orgIDs []uint
+
+	err := db.Model(UserOrganization{}).Where(UserOrganization{UserID: userID}).Pluck("organization_id", &orgIDs).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query user organizations")
+	}
+
+	return orgIDs, nil
+}
+
+// TokenPolicyRequest describes the token policy of an organization.
+type TokenPolicyRequest struct {
+	// MaxTokenAge is a duration (eg. 720h), empty or 0 means unlimited
+	MaxTokenAge string `json:"maxTokenAge"`
+}
+
+// GetTokenPolicy returns the token policy of the current organization.
+func GetTokenPolicy(c *gin.Context) {
+	organization := GetCurrentOrganization(c.Request)
+
+	policy, err := GetOrganizationTokenPolicy(Auth.GetDB(c.Request), organization.ID)
+	if err != nil {
+		errorHandler.Handle(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to get token policy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenPolicyRequest{MaxTokenAge: policy.MaxTokenAge().String()})
+}
+
+// SetTokenPolicy sets the token policy of the current organization.
+func SetTokenPolicy(c *gin.Context) {
+	var request TokenPolicyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "Error parsing request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var maxTokenAge time.Duration
+	if request.MaxTokenAge != "" {
+		var err error
+		maxTokenAge, err = time.ParseDuration(request.MaxTokenAge)
+		if err != nil || maxTokenAge < 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "Maximum token age must be a non-negative duration (eg. 720h)",
+				Error:   "invalid maximum token age",
+			})
+			return
+		}
+	}
+
+	policy := OrganizationTokenPolicy{
+		OrganizationID:     GetCurrentOrganization(c.Request).ID,
+		MaxTokenAgeSeconds: int64(maxTokenAge / time.Second),
+	}
+
+	if err := Auth.GetDB(c.Request).Save(&policy).Error; err != nil {
+		errorHandler.Handle(errors.Wrap(err, "failed to save organization token policy"))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "Failed to save token policy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenPolicyRequest{MaxTokenAge: policy.MaxTokenAge().String()})
+}
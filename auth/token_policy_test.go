@@ -0,0 +1,97 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrganizationTokenPolicy_Allows(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2019, time.May, 20, 12, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		maxAge   time.Duration
+		issuedAt time.Time
+		allowed  bool
+	}{
+		"unlimited":          {0, now.Add(-365 * 24 * time.Hour), true},
+		"young token":        {24 * time.Hour, now.Add(-time.Hour), true},
+		"exactly at max age": {24 * time.Hour, now.Add(-24 * time.Hour), true},
+		"old token":          {24 * time.Hour, now.Add(-25 * time.Hour), false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := OrganizationTokenPolicy{MaxTokenAgeSeconds: int64(test.maxAge / time.Second)}
+
+			assert.Equal(t, test.allowed, policy.Allows(test.issuedAt, now))
+		})
+	}
+}
+
+func TestLimitTokenExpiry(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.AutoMigrate(OrganizationTokenPolicy{}).Error)
+	require.NoError(t, db.Create(&OrganizationTokenPolicy{OrganizationID: 1, MaxTokenAgeSeconds: 3600}).Error)
+	require.NoError(t, db.Create(&OrganizationTokenPolicy{OrganizationID: 2, MaxTokenAgeSeconds: 600}).Error)
+	require.NoError(t, db.Create(&OrganizationTokenPolicy{OrganizationID: 3}).Error)
+
+	inAMinute := time.Now().Add(time.Minute)
+
+	tests := map[string]struct {
+		orgIDs    []uint
+		expiresAt *time.Time
+		maxAge    time.Duration
+	}{
+		"unlimited token":          {[]uint{1}, nil, time.Hour},
+		"strictest organization":   {[]uint{1, 2, 3}, nil, 10 * time.Minute},
+		"shorter requested expiry": {[]uint{1}, &inAMinute, time.Minute},
+		"unrestricted":             {[]uint{3}, nil, 0},
+		"no organizations":         {nil, nil, 0},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			expiresAt, err := limitTokenExpiry(db, test.orgIDs, test.expiresAt)
+			require.NoError(t, err)
+
+			if test.maxAge == 0 {
+				assert.Nil(t, expiresAt)
+
+				return
+			}
+
+			require.NotNil(t, expiresAt)
+			assert.WithinDuration(t, time.Now().Add(test.maxAge), *expiresAt, 5*time.Second)
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+const (
+	apiInvokeScope = "api:invoke"
+
+	tokenScopeOrganizationPrefix = "org:"
+	tokenScopeClusterPrefix      = "cluster:"
+	tokenScopeReadOnly           = "access:read"
+)
+
+// Token access levels
+const (
+	TokenAccessRead      = "read"
+	TokenAccessReadWrite = "readwrite"
+)
+
+// TokenScope limits what an API token can access on behalf of its user.
+// Scoped tokens can never exceed the permissions of the user they belong to.
+type TokenScope struct {
+	// OrganizationID is the only organization the token can access
+	OrganizationID uint `json:"organizationId"`
+
+	// ClusterIDs optionally limits the token to the given clusters of the organization
+	ClusterIDs []uint `json:"clusterIds,omitempty"`
+
+	// ReadOnly limits the token to read requests
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// AllowsCluster checks whether the scope allows accessing a cluster.
+func (s *TokenScope) AllowsCluster(clusterID uint) bool {
+	if len(s.ClusterIDs) == 0 {
+		return true
+	}
+
+	for _, id := range s.ClusterIDs {
+		if id == clusterID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateTokenScope checks that a user can access the organization and the clusters of a token scope.
+func validateTokenScope(db *gorm.DB, user *User, scope *TokenScope) error {
+	role, err := GetUserRole(db, user.ID, scope.OrganizationID)
+	if err != nil {
+		return err
+	} else if role == "" {
+		return errors.Errorf("user is not a member of organization %d", scope.OrganizationID)
+	}
+
+	if len(scope.ClusterIDs) == 0 {
+		return nil
+	}
+
+	var count int
+	err = db.Table("clusters").
+		Where("organization_id = ? AND id IN (?) AND deleted_at IS NULL", scope.OrganizationID, scope.ClusterIDs).
+		Count(&count).Error
+	if err != nil {
+		return errors.Wrap(err, "failed to query clusters of organization")
+	}
+
+	clusterIDs := make(map[uint]bool, len(scope.ClusterIDs))
+	for _, id := range scope.ClusterIDs {
+		clusterIDs[id] = true
+	}
+
+	if count != len(clusterIDs) {
+		return errors.Errorf("some of the clusters are not found in organization %d", scope.OrganizationID)
+	}
+
+	return nil
+}
+
+// tokenScopeClaim encodes a token scope as a space separated list of scopes stored in the JWT scope claim.
+func tokenScopeClaim(scope *TokenScope) string {
+	if scope == nil {
+		return apiInvokeScope
+	}
+
+	scopes := []string{apiInvokeScope}
+
+	scopes = append(scopes, fmt.Sprintf("%s%d", tokenScopeOrganizationPrefix, scope.OrganizationID))
+
+	for _, clusterID := range scope.ClusterIDs {
+		scopes = append(scopes, fmt.Sprintf("%s%d", tokenScopeClusterPrefix, clusterID))
+	}
+
+	if scope.ReadOnly {
+		scopes = append(scopes, tokenScopeReadOnly)
+	}
+
+	return strings.Join(scopes, " ")
+}
+
+// parseTokenScopeClaim decodes the token scope from the JWT scope claim.
+// Nil is returned for unscoped tokens.
+func parseTokenScopeClaim(claim string) *TokenScope {
+	var scope TokenScope
+	var scoped bool
+
+	for _, s := range strings.Fields(claim) {
+		switch {
+		case strings.HasPrefix(s, tokenScopeOrganizationPrefix):
+			// malformed scopes still make the token scoped, so that it cannot access anything
+			scoped = true
+
+			id, err := strconv.ParseUint(strings.TrimPrefix(s, tokenScopeOrganizationPrefix), 10, 32)
+			if err != nil {
+				continue
+			}
+
+			scope.OrganizationID = uint(id)
+
+		case strings.HasPrefix(s, tokenScopeClusterPrefix):
+			scoped = true
+
+			// a malformed cluster ID is kept as 0, which matches no cluster
+			id, _ := strconv.ParseUint(strings.TrimPrefix(s, tokenScopeClusterPrefix), 10, 32)
+
+			scope.ClusterIDs = append(scope.ClusterIDs, uint(id))
+
+		case s == tokenScopeReadOnly:
+			scope.ReadOnly = true
+			scoped = true
+		}
+	}
+
+	if !scoped {
+		return nil
+	}
+
+	return &scope
+}
@@ -0,0 +1,105 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	qorauth "github.com/qor/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenScopeClaim(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		scope *TokenScope
+		claim string
+	}{
+		"unscoped":       {nil, "api:invoke"},
+		"organization":   {&TokenScope{OrganizationID: 1}, "api:invoke org:1"},
+		"clusters":       {&TokenScope{OrganizationID: 1, ClusterIDs: []uint{2, 3}}, "api:invoke org:1 cluster:2 cluster:3"},
+		"read only":      {&TokenScope{OrganizationID: 1, ReadOnly: true}, "api:invoke org:1 access:read"},
+		"all limitation": {&TokenScope{OrganizationID: 4, ClusterIDs: []uint{5}, ReadOnly: true}, "api:invoke org:4 cluster:5 access:read"},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.claim, tokenScopeClaim(test.scope))
+			assert.Equal(t, test.scope, parseTokenScopeClaim(test.claim))
+		})
+	}
+}
+
+func TestParseTokenScopeClaim_Malformed(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		claim    string
+		expected *TokenScope
+	}{
+		"malformed organization": {"api:invoke org:x", &TokenScope{}},
+		"malformed cluster":      {"api:invoke org:1 cluster:x", &TokenScope{OrganizationID: 1, ClusterIDs: []uint{0}}},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, parseTokenScopeClaim(test.claim))
+		})
+	}
+}
+
+func TestFullAccessMiddleware(t *testing.T) {
+	tests := map[string]struct {
+		user         *User
+		expectedCode int
+	}{
+		"user":            {&User{ID: 1}, http.StatusOK},
+		"scoped token":    {&User{ID: 1, TokenScope: &TokenScope{OrganizationID: 1, ReadOnly: true}}, http.StatusForbidden},
+		"service account": {&User{Login: "serviceaccounts/12", TokenType: ServiceAccountTokenType}, http.StatusForbidden},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.New()
+			router.PATCH("/me", NewFullAccessMiddleware("update the user"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodPatch, "/me", nil)
+			req = req.WithContext(context.WithValue(req.Context(), qorauth.CurrentUser, test.user))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, test.expectedCode, w.Code)
+		})
+	}
+}
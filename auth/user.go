@@ -63,16 +63,20 @@ type AuthIdentity struct {
 
 //User struct
 type User struct {
-	ID            uint           `gorm:"primary_key" json:"id"`
-	CreatedAt     time.Time      `json:"createdAt"`
-	UpdatedAt     time.Time      `json:"updatedAt"`
-	Name          string         `form:"name" json:"name,omitempty"`
-	Email         string         `form:"email" json:"email,omitempty"`
-	Login         string         `gorm:"unique;not null" form:"login" json:"login"`
-	Image         string         `form:"image" json:"image,omitempty"`
-	Organizations []Organization `gorm:"many2many:user_organizations" json:"organizations,omitempty"`
-	Virtual       bool           `json:"-" gorm:"-"` // Used only internally
-	APIToken      string         `json:"-" gorm:"-"` // Used only internally
+	ID            uint            `gorm:"primary_key" json:"id"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
+	Name          string          `form:"name" json:"name,omitempty"`
+	Email         string          `form:"email" json:"email,omitempty"`
+	Login         string          `gorm:"unique;not null" form:"login" json:"login"`
+	Image         string          `form:"image" json:"image,omitempty"`
+	Organizations []Organization  `gorm:"many2many:user_organizations" json:"organizations,omitempty"`
+	Virtual       bool            `json:"-" gorm:"-"` // Used only internally
+	TokenType     bauth.TokenType `json:"-" gorm:"-"` // Used only internally
+	APIToken      string          `json:"-" gorm:"-"` // Used only internally
+	TokenScope    *TokenScope     `json:"-" gorm:"-"` // Used only internally
+	TokenID       string          `json:"-" gorm:"-"` // Used only internally
+	TokenIssuedAt time.Time       `json:"-" gorm:"-"` // Used only internally
 }
 
 //CICDUser struct
@@ -95,6 +99,7 @@ type UserOrganization struct {
 	UserID         uint
 	OrganizationID uint
 	Role           string `gorm:"default:'admin'"`
+	GroupManaged   bool   `gorm:"not null;default:false"` // the membership is decided by identity provider group mappings
 }
 
 //Organization struct
@@ -181,17 +186,21 @@ type BanzaiUserStorer struct {
 	orgImporter      *OrgImporter
 }
 
-func getOrganizationsFromDex(schema *auth.Schema) ([]string, error) {
+func getGroupsFromDex(schema *auth.Schema) []string {
 	var dexClaims struct {
 		Groups []string
 	}
 
 	if err := mapstructure.Decode(schema.RawInfo, &dexClaims); err != nil {
-		return nil, nil
+		return nil
 	}
 
+	return dexClaims.Groups
+}
+
+func getOrganizationsFromDex(schema *auth.Schema) ([]string, error) {
 	var organizations []string
-	for _, group := range dexClaims.Groups {
+	for _, group := range getGroupsFromDex(schema) {
 		if !strings.Contains(group, ":") {
 			organizations = append(organizations, group)
 		}
@@ -261,7 +270,7 @@ func (bus BanzaiUserStorer) Save(schema *auth.Schema, authCtx *auth.Context) (us
 		}
 	}
 
-	// When a user registers a default organization is created in which he/she is admin
+	// When a user registers a default organization is created which he/she owns
 	userOrg := Organization{
 		Name:     currentUser.Login,
 		Provider: getBackendProvider(schema.Provider),
@@ -273,21 +282,34 @@ func (bus BanzaiUserStorer) Save(schema *auth.Schema, authCtx *auth.Context) (us
 		return nil, "", emperror.Wrap(err, "failed to create user organization")
 	}
 
+	userRoleInOrg := UserOrganization{UserID: currentUser.ID, OrganizationID: currentUser.Organizations[0].ID}
+	err = db.Model(&UserOrganization{}).Where(userRoleInOrg).Update("role", RoleOwner).Error
+	if err != nil {
+		return nil, "", emperror.Wrap(err, "failed to save user role in organization")
+	}
+
 	err = helm.InstallLocalHelm(helm.GenerateHelmRepoEnv(currentUser.Organizations[0].Name))
 	if err != nil {
 		log.Errorf("Error during local helm install: %s", err.Error())
 	}
 
-	bus.accessManager.GrantDefaultAccessToUser(currentUser.IDString())
-	bus.accessManager.AddOrganizationPolicies(currentUser.Organizations[0].ID)
-	bus.accessManager.GrantOrganizationAccessToUser(currentUser.IDString(), currentUser.Organizations[0].ID)
+	err = bus.accessManager.GrantOrganizationAccessToUser(currentUser.IDString(), currentUser.Organizations[0].ID)
+	if err != nil {
+		return nil, "", err
+	}
+
 	bus.events.OrganizationRegistered(currentUser.Organizations[0].ID, currentUser.ID)
 
-	// Import organizations in case of DEX
-	if schema.Provider == ProviderDexGithub {
-		err = bus.orgImporter.ImportOrganizationsFromDex(currentUser, organizations, ProviderGithub)
-	} else if schema.Provider == ProviderDexGitlab {
-		err = bus.orgImporter.ImportOrganizationsFromDex(currentUser, organizations, ProviderGitlab)
+	// Organization memberships are decided by the group mappings if there are any,
+	// those are synchronized by the session storer on every login.
+	// Otherwise organizations are imported in case of DEX.
+	if bus.orgImporter.groupMapper == nil {
+		switch schema.Provider {
+		case ProviderDexGithub:
+			err = bus.orgImporter.ImportOrganizationsFromDex(currentUser, organizations, ProviderGithub)
+		case ProviderDexGitlab:
+			err = bus.orgImporter.ImportOrganizationsFromDex(currentUser, organizations, ProviderGitlab)
+		}
 	}
 
 	return currentUser, fmt.Sprint(db.NewScope(currentUser).PrimaryKeyValue()), err
@@ -378,18 +400,22 @@ type OrgImporter struct {
 	db            *gorm.DB
 	accessManager accessManager
 	events        authEvents
+	groupMapper   *GroupMapper
 }
 
 // NewOrgImporter returns a new OrgImporter instance.
+// Organization memberships are synchronized with the identity provider groups if a group mapper is given.
 func NewOrgImporter(
 	db *gorm.DB,
 	accessManager accessManager,
 	events eventBus,
+	groupMapper *GroupMapper,
 ) *OrgImporter {
 	return &OrgImporter{
 		db:            db,
 		accessManager: accessManager,
 		events:        ebAuthEvents{eb: events},
+		groupMapper:   groupMapper,
 	}
 }
 
@@ -428,8 +454,9 @@ func (i *OrgImporter) ImportOrganizations(currentUser *User, orgs []organization
 	}
 
 	for id, created := range orgIDs {
-		i.accessManager.AddOrganizationPolicies(id)
-		i.accessManager.GrantOrganizationAccessToUser(currentUser.IDString(), id)
+		if err := i.accessManager.GrantOrganizationAccessToUser(currentUser.IDString(), id); err != nil {
+			return err
+		}
 
 		if created {
 			i.events.OrganizationRegistered(id, currentUser.ID)
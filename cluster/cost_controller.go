@@ -0,0 +1,101 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+)
+
+// CostController periodically samples the node pool sizes of running clusters
+// to account their running cost.
+type CostController struct {
+	manager  *CostManager
+	clusters *Manager
+
+	// sampleInterval is how often the node pools are sampled
+	sampleInterval time.Duration
+
+	stop chan struct{}
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewCostController instantiates a new cluster cost controller
+func NewCostController(
+	manager *CostManager,
+	clusters *Manager,
+	sampleInterval time.Duration,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *CostController {
+	return &CostController{
+		manager:        manager,
+		clusters:       clusters,
+		sampleInterval: sampleInterval,
+		stop:           make(chan struct{}),
+		logger:         logger,
+		errorHandler:   errorHandler,
+	}
+}
+
+func (c *CostController) Start() error {
+	c.logger.Info("starting cluster cost controller")
+
+	go c.run()
+
+	return nil
+}
+
+func (c *CostController) Stop() {
+	c.logger.Info("shutting cluster cost controller")
+	close(c.stop)
+}
+
+func (c *CostController) run() {
+	ticker := time.NewTicker(c.sampleInterval)
+	defer ticker.Stop()
+
+	for {
+		c.sampleClusters(time.Now())
+
+		select {
+		case <-ticker.C:
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *CostController) sampleClusters(now time.Time) {
+	ctx := context.Background()
+
+	clusters, err := c.clusters.GetAllClusters(ctx)
+	if err != nil {
+		c.errorHandler.Handle(err)
+
+		return
+	}
+
+	for _, cluster := range clusters {
+		if err := c.manager.RecordCosts(ctx, cluster, now); err != nil {
+			c.errorHandler.Handle(err)
+		}
+	}
+}
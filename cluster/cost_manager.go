@@ -0,0 +1,387 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/internal/cloudinfo"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// HoursPerMonth is the average number of hours in a month used for monthly cost estimates.
+const HoursPerMonth = 730
+
+// noLabelValue is the group name of clusters without the label used for grouping costs.
+const noLabelValue = "<none>"
+
+type clusterCostRecords interface {
+	FindLatestByCluster(clusterID uint) ([]intCluster.ClusterCostRecordModel, error)
+	FindByCluster(clusterID uint, from time.Time, to time.Time) ([]intCluster.ClusterCostRecordModel, error)
+	FindByOrganization(organizationID uint, from time.Time, to time.Time) ([]intCluster.ClusterCostRecordModel, error)
+	Save(record *intCluster.ClusterCostRecordModel) error
+}
+
+type nodePrices interface {
+	// GetNodePrice returns the hourly price of a single node.
+	GetNodePrice(cloud string, service string, region string, instanceType string, spot bool) (float64, error)
+}
+
+// CloudinfoNodePrices returns node prices from Cloudinfo.
+type CloudinfoNodePrices struct {
+	logger logrus.FieldLogger
+}
+
+// NewCloudinfoNodePrices returns a node price provider backed by Cloudinfo.
+func NewCloudinfoNodePrices(logger logrus.FieldLogger) *CloudinfoNodePrices {
+	return &CloudinfoNodePrices{logger: logger}
+}
+
+// GetNodePrice returns the hourly price of a single node.
+func (p *CloudinfoNodePrices) GetNodePrice(cloud string, service string, region string, instanceType string, spot bool) (float64, error) {
+	return cloudinfo.GetNodePrice(p.logger, cloud, service, region, instanceType, spot)
+}
+
+// CostManager estimates the cost of clusters and accounts their running cost based on node pool sizes over time.
+type CostManager struct {
+	records clusterCostRecords
+	prices  nodePrices
+	labels  clusterLabels
+
+	// maxSampleGap is the longest time between two samples still considered as continuous running
+	maxSampleGap time.Duration
+
+	logger logrus.FieldLogger
+}
+
+// NewCostManager returns a new CostManager instance.
+func NewCostManager(
+	records clusterCostRecords,
+	prices nodePrices,
+	labels clusterLabels,
+	maxSampleGap time.Duration,
+	logger logrus.FieldLogger,
+) *CostManager {
+	return &CostManager{
+		records:      records,
+		prices:       prices,
+		labels:       labels,
+		maxSampleGap: maxSampleGap,
+		logger:       logger,
+	}
+}
+
+// EstimateCreate returns the estimated cost of a cluster described by a create request.
+func (m *CostManager) EstimateCreate(ctx context.Context, request *pkgCluster.CreateClusterRequest) (*pkgCluster.CostEstimateResponse, error) {
+	distribution := request.GetCostDistribution()
+	if distribution == "" {
+		return nil, errors.WithStack(&invalidError{errors.New("cost estimation is not supported for the requested cluster type")})
+	}
+
+	return m.estimate(request.Cloud, distribution, request.Location, request.GetCostNodePools())
+}
+
+// EstimateUpdate returns the estimated cost of a cluster after applying an update request.
+func (m *CostManager) EstimateUpdate(
+	ctx context.Context,
+	commonCluster CommonCluster,
+	request *pkgCluster.UpdateClusterRequest,
+) (*pkgCluster.CostEstimateResponse, error) {
+	clusterStatus, err := commonCluster.GetStatus()
+	if err != nil {
+		return nil, emperror.WrapWith(err, "failed to retrieve cluster details", "clusterID", commonCluster.GetID())
+	}
+
+	nodePools, err := mergeUpdateCostNodePools(clusterStatus.NodePools, request.GetCostNodePools())
+	if err != nil {
+		return nil, errors.WithStack(&invalidError{err})
+	}
+
+	return m.estimate(clusterStatus.Cloud, clusterStatus.Distribution, clusterStatus.Region, nodePools)
+}
+
+// mergeUpdateCostNodePools completes the node pools of an update request with the current instance types.
+func mergeUpdateCostNodePools(
+	current map[string]*pkgCluster.NodePoolStatus,
+	requested map[string]pkgCluster.CostNodePool,
+) (map[string]pkgCluster.CostNodePool, error) {
+	nodePools := make(map[string]pkgCluster.CostNodePool, len(requested))
+
+	for name, nodePool := range requested {
+		if nodePool.InstanceType == "" {
+			currentNodePool, ok := current[name]
+			if !ok || currentNodePool == nil {
+				return nil, errors.Errorf("instance type is required for new node pool: %s", name)
+			}
+
+			nodePool.InstanceType = currentNodePool.InstanceType
+		}
+
+		nodePools[name] = nodePool
+	}
+
+	return nodePools, nil
+}
+
+func (m *CostManager) estimate(
+	cloud string,
+	distribution string,
+	location string,
+	nodePools map[string]pkgCluster.CostNodePool,
+) (*pkgCluster.CostEstimateResponse, error) {
+	response := &pkgCluster.CostEstimateResponse{
+		Cloud:        cloud,
+		Distribution: distribution,
+		Location:     location,
+		NodePools:    make(map[string]pkgCluster.NodePoolCostEstimate, len(nodePools)),
+	}
+
+	for name, nodePool := range nodePools {
+		if nodePool.InstanceType == "" {
+			return nil, errors.WithStack(&invalidError{errors.Errorf("instance type is required for node pool: %s", name)})
+		}
+
+		price, err := m.prices.GetNodePrice(cloud, distribution, location, nodePool.InstanceType, nodePool.Spot)
+		if err != nil {
+			return nil, emperror.WrapWith(err, "failed to get node price", "nodePool", name, "instanceType", nodePool.InstanceType)
+		}
+
+		hourlyCost := price * float64(nodePool.Count)
+
+		response.NodePools[name] = pkgCluster.NodePoolCostEstimate{
+			CostNodePool: nodePool,
+			UnitPrice:    price,
+			HourlyCost:   hourlyCost,
+			MonthlyCost:  hourlyCost * HoursPerMonth,
+		}
+
+		response.HourlyCost += hourlyCost
+	}
+
+	response.MonthlyCost = response.HourlyCost * HoursPerMonth
+
+	return response, nil
+}
+
+// RecordCosts samples the node pool sizes of a running cluster and extends its cost records.
+// A new record is started whenever the size, instance type or pricing of a node pool changes,
+// or when the previous sample is too old to assume that the node pool kept running in between.
+// Clusters which are not running (anymore) are skipped, so they stop accumulating cost.
+func (m *CostManager) RecordCosts(ctx context.Context, commonCluster CommonCluster, now time.Time) error {
+	clusterStatus, err := commonCluster.GetStatus()
+	if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve cluster details", "clusterID", commonCluster.GetID())
+	}
+
+	switch clusterStatus.Status {
+	case pkgCluster.Running, pkgCluster.Warning, pkgCluster.Updating:
+	default:
+		return nil
+	}
+
+	latestRecords, err := m.records.FindLatestByCluster(commonCluster.GetID())
+	if err != nil {
+		return err
+	}
+
+	latest := make(map[string]intCluster.ClusterCostRecordModel, len(latestRecords))
+	for _, record := range latestRecords {
+		latest[record.NodePool] = record
+	}
+
+	for name, nodePool := range clusterStatus.NodePools {
+		if nodePool == nil {
+			continue
+		}
+
+		spot := nodePool.Preemptible || pkgCluster.IsSpotPrice(nodePool.SpotPrice)
+
+		record, ok := latest[name]
+		continuous := ok && now.Sub(record.EndedAt) <= m.maxSampleGap
+
+		if continuous && record.InstanceType == nodePool.InstanceType && record.Spot == spot && record.Count == nodePool.Count {
+			record.EndedAt = now
+
+			if err := m.records.Save(&record); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if continuous {
+			record.EndedAt = now
+
+			if err := m.records.Save(&record); err != nil {
+				return err
+			}
+		}
+
+		var price float64
+		if nodePool.Count > 0 {
+			price, err = m.prices.GetNodePrice(clusterStatus.Cloud, clusterStatus.Distribution, clusterStatus.Region, nodePool.InstanceType, spot)
+			if err != nil {
+				return emperror.WrapWith(err, "failed to get node price", "clusterID", commonCluster.GetID(), "nodePool", name)
+			}
+		}
+
+		err := m.records.Save(&intCluster.ClusterCostRecordModel{
+			ClusterID:      commonCluster.GetID(),
+			OrganizationID: commonCluster.GetOrganizationId(),
+			ClusterName:    commonCluster.GetName(),
+			NodePool:       name,
+			InstanceType:   nodePool.InstanceType,
+			Spot:           spot,
+			Count:          nodePool.Count,
+			HourlyPrice:    price,
+			StartedAt:      now,
+			EndedAt:        now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetClusterCost returns the running cost of a cluster in a time range broken down by node pools.
+func (m *CostManager) GetClusterCost(ctx context.Context, commonCluster CommonCluster, from time.Time, to time.Time) (*pkgCluster.CostResponse, error) {
+	if !to.After(from) {
+		return nil, errors.WithStack(&invalidError{errors.New("the end of the time range must be after its start")})
+	}
+
+	records, err := m.records.FindByCluster(commonCluster.GetID(), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	response := sumCosts(records, from, to, func(record intCluster.ClusterCostRecordModel) pkgCluster.CostItem {
+		return pkgCluster.CostItem{Name: record.NodePool}
+	})
+
+	return &response, nil
+}
+
+// GetOrganizationCost returns the running cost of the clusters of an organization in a time range
+// broken down by clusters or, if a label key is given, by the values of that cluster label (eg. team).
+func (m *CostManager) GetOrganizationCost(
+	ctx context.Context,
+	organizationID uint,
+	from time.Time,
+	to time.Time,
+	groupByLabel string,
+) (*pkgCluster.CostResponse, error) {
+	if !to.After(from) {
+		return nil, errors.WithStack(&invalidError{errors.New("the end of the time range must be after its start")})
+	}
+
+	records, err := m.records.FindByOrganization(organizationID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	if groupByLabel == "" {
+		response := sumCosts(records, from, to, func(record intCluster.ClusterCostRecordModel) pkgCluster.CostItem {
+			return pkgCluster.CostItem{Name: record.ClusterName, ClusterID: record.ClusterID}
+		})
+
+		return &response, nil
+	}
+
+	var clusterIDs []uint
+	seen := make(map[uint]bool)
+	for _, record := range records {
+		if !seen[record.ClusterID] {
+			seen[record.ClusterID] = true
+			clusterIDs = append(clusterIDs, record.ClusterID)
+		}
+	}
+
+	clusterLabels, err := m.labels.FindByClusters(clusterIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	response := sumCosts(records, from, to, func(record intCluster.ClusterCostRecordModel) pkgCluster.CostItem {
+		value, ok := clusterLabels[record.ClusterID][groupByLabel]
+		if !ok {
+			value = noLabelValue
+		}
+
+		return pkgCluster.CostItem{Name: value}
+	})
+
+	return &response, nil
+}
+
+// sumCosts accumulates the cost of records in a time range into items grouped by the returned item identity.
+func sumCosts(
+	records []intCluster.ClusterCostRecordModel,
+	from time.Time,
+	to time.Time,
+	itemOf func(record intCluster.ClusterCostRecordModel) pkgCluster.CostItem,
+) pkgCluster.CostResponse {
+	response := pkgCluster.CostResponse{
+		From:  from,
+		To:    to,
+		Items: []pkgCluster.CostItem{},
+	}
+
+	items := make(map[pkgCluster.CostItem]*pkgCluster.CostItem)
+
+	for _, record := range records {
+		cost := record.CostBetween(from, to)
+
+		key := itemOf(record)
+		item, ok := items[key]
+		if !ok {
+			item = &pkgCluster.CostItem{Name: key.Name, ClusterID: key.ClusterID}
+			items[key] = item
+		}
+
+		if record.Spot {
+			item.SpotCost += cost
+			response.SpotCost += cost
+		} else {
+			item.OnDemandCost += cost
+			response.OnDemandCost += cost
+		}
+
+		item.TotalCost += cost
+		response.TotalCost += cost
+	}
+
+	for _, item := range items {
+		response.Items = append(response.Items, *item)
+	}
+
+	sort.Slice(response.Items, func(i, j int) bool {
+		if response.Items[i].Name != response.Items[j].Name {
+			return response.Items[i].Name < response.Items[j].Name
+		}
+
+		return response.Items[i].ClusterID < response.Items[j].ClusterID
+	})
+
+	return response
+}
@@ -0,0 +1,142 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+func TestSumCosts(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2019, time.May, 6, 0, 0, 0, 0, time.UTC)
+
+	records := []intCluster.ClusterCostRecordModel{
+		{ClusterID: 1, ClusterName: "a", Count: 2, HourlyPrice: 0.5, StartedAt: base, EndedAt: base.Add(10 * time.Hour)},
+		{ClusterID: 1, ClusterName: "a", Count: 4, HourlyPrice: 0.25, Spot: true, StartedAt: base, EndedAt: base.Add(4 * time.Hour)},
+		{ClusterID: 2, ClusterName: "b", Count: 1, HourlyPrice: 1, StartedAt: base.Add(8 * time.Hour), EndedAt: base.Add(20 * time.Hour)},
+	}
+
+	byCluster := func(record intCluster.ClusterCostRecordModel) pkgCluster.CostItem {
+		return pkgCluster.CostItem{Name: record.ClusterName, ClusterID: record.ClusterID}
+	}
+
+	tests := map[string]struct {
+		from     time.Time
+		to       time.Time
+		expected pkgCluster.CostResponse
+	}{
+		"whole range": {
+			from: base,
+			to:   base.Add(24 * time.Hour),
+			expected: pkgCluster.CostResponse{
+				OnDemandCost: 22,
+				SpotCost:     4,
+				TotalCost:    26,
+				Items: []pkgCluster.CostItem{
+					{Name: "a", ClusterID: 1, OnDemandCost: 10, SpotCost: 4, TotalCost: 14},
+					{Name: "b", ClusterID: 2, OnDemandCost: 12, TotalCost: 12},
+				},
+			},
+		},
+		"partial overlap": {
+			from: base.Add(2 * time.Hour),
+			to:   base.Add(9 * time.Hour),
+			expected: pkgCluster.CostResponse{
+				OnDemandCost: 8,
+				SpotCost:     2,
+				TotalCost:    10,
+				Items: []pkgCluster.CostItem{
+					{Name: "a", ClusterID: 1, OnDemandCost: 7, SpotCost: 2, TotalCost: 9},
+					{Name: "b", ClusterID: 2, OnDemandCost: 1, TotalCost: 1},
+				},
+			},
+		},
+		"no overlap": {
+			from: base.Add(-2 * time.Hour),
+			to:   base,
+			expected: pkgCluster.CostResponse{
+				Items: []pkgCluster.CostItem{
+					{Name: "a", ClusterID: 1},
+					{Name: "b", ClusterID: 2},
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			test.expected.From = test.from
+			test.expected.To = test.to
+
+			assert.Equal(t, test.expected, sumCosts(records, test.from, test.to, byCluster))
+		})
+	}
+}
+
+func TestMergeUpdateCostNodePools(t *testing.T) {
+	t.Parallel()
+
+	current := map[string]*pkgCluster.NodePoolStatus{
+		"pool1": {Count: 3, InstanceType: "Standard_D2_v2"},
+	}
+
+	tests := map[string]struct {
+		requested map[string]pkgCluster.CostNodePool
+		expected  map[string]pkgCluster.CostNodePool
+		valid     bool
+	}{
+		"resize existing pool": {
+			requested: map[string]pkgCluster.CostNodePool{"pool1": {Count: 5}},
+			expected:  map[string]pkgCluster.CostNodePool{"pool1": {InstanceType: "Standard_D2_v2", Count: 5}},
+			valid:     true,
+		},
+		"change instance type": {
+			requested: map[string]pkgCluster.CostNodePool{"pool1": {InstanceType: "Standard_D4_v2", Count: 1}},
+			expected:  map[string]pkgCluster.CostNodePool{"pool1": {InstanceType: "Standard_D4_v2", Count: 1}},
+			valid:     true,
+		},
+		"new pool without instance type": {
+			requested: map[string]pkgCluster.CostNodePool{"pool2": {Count: 1}},
+			valid:     false,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			nodePools, err := mergeUpdateCostNodePools(current, test.requested)
+			if test.valid {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, nodePools)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
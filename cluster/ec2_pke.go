@@ -531,6 +531,49 @@ func (c *EC2ClusterPKE) UpdatePKECluster(ctx context.Context, request *pkgCluste
 	return workflowError
 }
 
+// UpgradePKECluster upgrades the Kubernetes version of the cluster node by node.
+func (c *EC2ClusterPKE) UpgradePKECluster(ctx context.Context, version string, rollbackOnFailure bool, workflowClient client.Client, externalBaseURL string) error {
+	input := pkeworkflow.UpgradeClusterWorkflowInput{
+		OrganizationID:            uint(c.GetOrganizationId()),
+		ClusterID:                 uint(c.GetID()),
+		ClusterName:               c.GetName(),
+		SecretID:                  string(c.GetSecretId()),
+		Region:                    c.GetLocation(),
+		PipelineExternalURL:       externalBaseURL,
+		KubernetesVersion:         version,
+		PreviousKubernetesVersion: c.model.Kubernetes.Version,
+		RollbackOnFailure:         rollbackOnFailure,
+	}
+
+	// autoscaled node pools may grow up to their maximum size during the upgrade
+	nodePools := c.GetNodePools()
+	var nodeCount int
+	for _, np := range nodePools {
+		count := np.Count
+		if np.Autoscaling && np.MaxCount > count {
+			count = np.MaxCount
+		}
+
+		nodeCount += count
+	}
+
+	workflowOptions := client.StartWorkflowOptions{
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: pkeworkflow.UpgradeClusterWorkflowTimeout(len(nodePools), nodeCount),
+	}
+	exec, err := workflowClient.ExecuteWorkflow(ctx, workflowOptions, pkeworkflow.UpgradeClusterWorkflowName, input)
+	if err != nil {
+		return err
+	}
+
+	err = c.SetCurrentWorkflowID(exec.GetID())
+	if err != nil {
+		return err
+	}
+
+	return exec.Get(ctx, nil)
+}
+
 func (c *EC2ClusterPKE) UpdateNodePools(*pkgCluster.UpdateNodePoolsRequest, uint) error {
 	panic("implement me")
 }
@@ -924,6 +967,18 @@ func (c *EC2ClusterPKE) GetKubernetesVersion() (string, error) {
 	return c.model.Kubernetes.Version, nil
 }
 
+// SaveKubernetesVersion saves the Kubernetes version used by new nodes of the cluster.
+func (c *EC2ClusterPKE) SaveKubernetesVersion(version string) error {
+	c.model.Kubernetes.Version = version
+
+	err := c.db.Save(&c.model.Kubernetes).Error
+	if err != nil {
+		return emperror.WrapWith(err, "failed to save kubernetes version", "version", version)
+	}
+
+	return nil
+}
+
 // GetNetworkCloudProvider return cloud provider specific network information.
 func (c *EC2ClusterPKE) GetNetworkCloudProvider() (cloudProvider, vpcID string, subnets []string, err error) {
 	cp := c.model.Network.CloudProvider
@@ -14,6 +14,10 @@
 
 package cluster
 
+import (
+	"time"
+)
+
 type clusterEvents interface {
 	// ClusterCreated event is emitted when a cluster creation workflow finishes.
 	ClusterCreated(clusterID uint)
@@ -25,6 +29,9 @@ type clusterEvents interface {
 	// this event is fired regardless of whether the cluster update succeeded or
 	// only partially succeeded (cluster is in warning state)
 	ClusterUpdated(clusterID uint)
+
+	// ClusterExpiring event is emitted once when a cluster is about to be deleted because its TTL expires.
+	ClusterExpiring(orgID uint, clusterID uint, clusterName string, expiresAt time.Time)
 }
 
 type nopClusterEvents struct {
@@ -43,6 +50,9 @@ func (*nopClusterEvents) ClusterDeleted(orgID uint, clusterName string) {
 func (*nopClusterEvents) ClusterUpdated(clusterID uint) {
 }
 
+func (*nopClusterEvents) ClusterExpiring(orgID uint, clusterID uint, clusterName string, expiresAt time.Time) {
+}
+
 type eventBus interface {
 	Publish(topic string, args ...interface{})
 }
@@ -52,9 +62,10 @@ type clusterEventBus struct {
 }
 
 const (
-	clusterCreatedTopic = "cluster_created"
-	clusterDeletedTopic = "cluster_deleted"
-	clusterUpdatedTopic = "cluster_updated"
+	clusterCreatedTopic  = "cluster_created"
+	clusterDeletedTopic  = "cluster_deleted"
+	clusterUpdatedTopic  = "cluster_updated"
+	clusterExpiringTopic = "cluster_expiring"
 )
 
 func NewClusterEvents(eb eventBus) *clusterEventBus {
@@ -74,3 +85,7 @@ func (c *clusterEventBus) ClusterDeleted(orgID uint, clusterName string) {
 func (c *clusterEventBus) ClusterUpdated(clusterID uint) {
 	c.eb.Publish(clusterUpdatedTopic, clusterID)
 }
+
+func (c *clusterEventBus) ClusterExpiring(orgID uint, clusterID uint, clusterName string, expiresAt time.Time) {
+	c.eb.Publish(clusterExpiringTopic, orgID, clusterID, clusterName, expiresAt)
+}
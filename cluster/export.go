@@ -0,0 +1,353 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	internalPke "github.com/banzaicloud/pipeline/internal/providers/pke"
+	"github.com/banzaicloud/pipeline/model"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cluster/ack"
+	"github.com/banzaicloud/pipeline/pkg/cluster/aks"
+	"github.com/banzaicloud/pipeline/pkg/cluster/dummy"
+	"github.com/banzaicloud/pipeline/pkg/cluster/eks"
+	"github.com/banzaicloud/pipeline/pkg/cluster/gke"
+	"github.com/banzaicloud/pipeline/pkg/cluster/kubernetes"
+	"github.com/banzaicloud/pipeline/pkg/cluster/pke"
+)
+
+type exportNotSupportedError struct {
+	distribution string
+}
+
+func (e exportNotSupportedError) Error() string {
+	return fmt.Sprintf("exporting %s clusters is not supported", e.distribution)
+}
+
+func (exportNotSupportedError) IsInvalid() bool {
+	return true
+}
+
+// ExportCreateClusterRequest rebuilds the create request of an existing cluster from its stored models.
+//
+// Post hook parameters are not stored, so post hooks of the enabled features are exported without parameters.
+// Node pool labels are taken from nodePoolLabels when present for the node pool
+// (labels are not stored in the database either), otherwise from the model.
+func ExportCreateClusterRequest(commonCluster CommonCluster, nodePoolLabels map[string]map[string]string) (*pkgCluster.CreateClusterRequest, error) {
+	request := &pkgCluster.CreateClusterRequest{
+		Name:         commonCluster.GetName(),
+		Location:     commonCluster.GetLocation(),
+		Cloud:        commonCluster.GetCloud(),
+		SecretId:     commonCluster.GetSecretId(),
+		PostHooks:    exportPostHooks(commonCluster),
+		Properties:   &pkgCluster.CreateClusterProperties{},
+		ScaleOptions: commonCluster.GetScaleOptions(),
+		TtlMinutes:   uint(commonCluster.GetTTL() / time.Minute),
+	}
+
+	if request.ScaleOptions != nil && !request.ScaleOptions.Enabled {
+		request.ScaleOptions = nil
+	}
+
+	labels := func(nodePoolName string, modelLabels map[string]string) map[string]string {
+		if l, ok := nodePoolLabels[nodePoolName]; ok {
+			return l
+		}
+
+		return modelLabels
+	}
+
+	switch c := commonCluster.(type) {
+	case *ACKCluster:
+		request.Properties.CreateClusterACK = exportACKProperties(c.modelCluster, labels)
+
+	case *EKSCluster:
+		request.Properties.CreateClusterEKS = exportEKSProperties(c.modelCluster, labels)
+
+	case *AKSCluster:
+		request.Properties.CreateClusterAKS = exportAKSProperties(c.modelCluster, labels)
+
+	case *GKECluster:
+		request.Properties.CreateClusterGKE = exportGKEProperties(c, labels)
+
+	case *OKECluster:
+		request.Properties.CreateClusterOKE = c.modelCluster.OKE.GetClusterRequestFromModel()
+
+		for name, nodePool := range request.Properties.CreateClusterOKE.NodePools {
+			nodePool.Labels = labels(name, nodePool.Labels)
+		}
+
+	case *EC2ClusterPKE:
+		request.Properties.CreateClusterPKE = exportPKEProperties(c.model, labels)
+
+	case *DummyCluster:
+		request.Properties.CreateClusterDummy = &dummy.CreateClusterDummy{
+			Node: &dummy.Node{
+				KubernetesVersion: c.modelCluster.Dummy.KubernetesVersion,
+				Count:             c.modelCluster.Dummy.NodeCount,
+			},
+		}
+
+	case *KubeCluster:
+		request.Properties.CreateClusterKubernetes = &kubernetes.CreateClusterKubernetes{
+			Metadata: c.modelCluster.Kubernetes.Metadata,
+		}
+
+	default:
+		return nil, errors.WithStack(exportNotSupportedError{distribution: commonCluster.GetDistribution()})
+	}
+
+	return request, nil
+}
+
+// exportPostHooks returns the post hooks of the features enabled on the cluster.
+func exportPostHooks(commonCluster CommonCluster) pkgCluster.PostHooks {
+	postHooks := make(pkgCluster.PostHooks)
+
+	if commonCluster.GetMonitoring() {
+		postHooks[pkgCluster.InstallMonitoring] = nil
+	}
+
+	if commonCluster.GetLogging() {
+		postHooks[pkgCluster.InstallLogging] = nil
+	}
+
+	if commonCluster.GetServiceMesh() {
+		postHooks[pkgCluster.InstallServiceMesh] = nil
+	}
+
+	if commonCluster.GetSecurityScan() {
+		postHooks[pkgCluster.InstallAnchoreImageValidator] = nil
+	}
+
+	return postHooks
+}
+
+func exportACKProperties(modelCluster *model.ClusterModel, labels func(string, map[string]string) map[string]string) *ack.CreateClusterACK {
+	nodePools := make(ack.NodePools, len(modelCluster.ACK.NodePools))
+	for _, np := range modelCluster.ACK.NodePools {
+		if np == nil {
+			continue
+		}
+
+		nodePools[np.Name] = &ack.NodePool{
+			InstanceType: np.InstanceType,
+			MinCount:     np.MinCount,
+			MaxCount:     np.MaxCount,
+			Labels:       labels(np.Name, np.Labels),
+		}
+	}
+
+	return &ack.CreateClusterACK{
+		RegionID:                 modelCluster.ACK.RegionID,
+		ZoneID:                   modelCluster.ACK.ZoneID,
+		MasterInstanceType:       modelCluster.ACK.MasterInstanceType,
+		MasterSystemDiskCategory: modelCluster.ACK.MasterSystemDiskCategory,
+		MasterSystemDiskSize:     modelCluster.ACK.MasterSystemDiskSize,
+		NodePools:                nodePools,
+		VSwitchID:                modelCluster.ACK.VSwitchID,
+	}
+}
+
+func exportEKSProperties(modelCluster *model.ClusterModel, labels func(string, map[string]string) map[string]string) *eks.CreateClusterEKS {
+	nodePools := make(map[string]*eks.NodePool, len(modelCluster.EKS.NodePools))
+	for _, np := range modelCluster.EKS.NodePools {
+		if np == nil {
+			continue
+		}
+
+		nodePools[np.Name] = &eks.NodePool{
+			InstanceType: np.NodeInstanceType,
+			SpotPrice:    np.NodeSpotPrice,
+			Autoscaling:  np.Autoscaling,
+			MinCount:     np.NodeMinCount,
+			MaxCount:     np.NodeMaxCount,
+			Count:        np.Count,
+			Image:        np.NodeImage,
+			Labels:       labels(np.Name, np.Labels),
+		}
+	}
+
+	properties := &eks.CreateClusterEKS{
+		Version:   modelCluster.EKS.Version,
+		NodePools: nodePools,
+	}
+
+	if vpcID, vpcCidr := stringValue(modelCluster.EKS.VpcId), stringValue(modelCluster.EKS.VpcCidr); vpcID != "" || vpcCidr != "" {
+		properties.Vpc = &eks.ClusterVPC{
+			VpcId: vpcID,
+			Cidr:  vpcCidr,
+		}
+	}
+
+	properties.RouteTableId = stringValue(modelCluster.EKS.RouteTableId)
+
+	for _, subnet := range modelCluster.EKS.Subnets {
+		if subnet == nil {
+			continue
+		}
+
+		properties.Subnets = append(properties.Subnets, &eks.ClusterSubnet{
+			SubnetId: stringValue(subnet.SubnetId),
+			Cidr:     stringValue(subnet.Cidr),
+		})
+	}
+
+	return properties
+}
+
+func exportAKSProperties(modelCluster *model.ClusterModel, labels func(string, map[string]string) map[string]string) *aks.CreateClusterAKS {
+	nodePools := make(map[string]*aks.NodePoolCreate, len(modelCluster.AKS.NodePools))
+	for _, np := range modelCluster.AKS.NodePools {
+		if np == nil {
+			continue
+		}
+
+		nodePools[np.Name] = &aks.NodePoolCreate{
+			Autoscaling:      np.Autoscaling,
+			MinCount:         np.NodeMinCount,
+			MaxCount:         np.NodeMaxCount,
+			Count:            np.Count,
+			NodeInstanceType: np.NodeInstanceType,
+			VNetSubnetID:     np.VNetSubnetID,
+			Labels:           labels(np.Name, np.Labels),
+		}
+	}
+
+	return &aks.CreateClusterAKS{
+		ResourceGroup:     modelCluster.AKS.ResourceGroup,
+		KubernetesVersion: modelCluster.AKS.KubernetesVersion,
+		NodePools:         nodePools,
+	}
+}
+
+func exportGKEProperties(c *GKECluster, labels func(string, map[string]string) map[string]string) *gke.CreateClusterGKE {
+	nodePools := make(map[string]*gke.NodePool, len(c.model.NodePools))
+	for _, np := range c.model.NodePools {
+		if np == nil {
+			continue
+		}
+
+		nodePools[np.Name] = &gke.NodePool{
+			Autoscaling:      np.Autoscaling,
+			MinCount:         np.NodeMinCount,
+			MaxCount:         np.NodeMaxCount,
+			Count:            np.NodeCount,
+			NodeInstanceType: np.NodeInstanceType,
+			Preemptible:      np.Preemptible,
+			Labels:           labels(np.Name, np.Labels),
+		}
+	}
+
+	return &gke.CreateClusterGKE{
+		NodeVersion: c.model.NodeVersion,
+		NodePools:   nodePools,
+		Master: &gke.Master{
+			Version: c.model.MasterVersion,
+		},
+		Vpc:       c.model.Vpc,
+		Subnet:    c.model.Subnet,
+		ProjectId: c.model.ProjectId,
+	}
+}
+
+func exportPKEProperties(m *internalPke.EC2PKEClusterModel, labels func(string, map[string]string) map[string]string) *pke.CreateClusterPKE {
+	nodePools := make(pke.NodePools, 0, len(m.NodePools))
+	for _, np := range m.NodePools {
+		roles := make(pke.Roles, 0, len(np.Roles))
+		for _, role := range np.Roles {
+			roles = append(roles, pke.Role(role))
+		}
+
+		hosts := make(pke.Hosts, 0, len(np.Hosts))
+		for _, host := range np.Hosts {
+			hostRoles := make(pke.Roles, 0, len(host.Roles))
+			for _, role := range host.Roles {
+				hostRoles = append(hostRoles, pke.Role(role))
+			}
+
+			hostLabels := make(pke.Labels, len(host.Labels))
+			for k, v := range host.Labels {
+				hostLabels[k] = v
+			}
+
+			taints := make(pke.Taints, 0, len(host.Taints))
+			for _, taint := range host.Taints {
+				taints = append(taints, pke.Taint(taint))
+			}
+
+			hosts = append(hosts, pke.Host{
+				Name:             host.Name,
+				PrivateIP:        host.PrivateIP,
+				NetworkInterface: host.NetworkInterface,
+				Roles:            hostRoles,
+				Labels:           hostLabels,
+				Taints:           taints,
+			})
+		}
+
+		nodePools = append(nodePools, pke.NodePool{
+			Name:           np.Name,
+			Roles:          roles,
+			Hosts:          hosts,
+			Provider:       pke.NodePoolProvider(np.Provider),
+			ProviderConfig: np.ProviderConfig,
+			Labels:         labels(np.Name, np.Labels),
+			Autoscaling:    np.Autoscaling,
+		})
+	}
+
+	extraArgs := make(pke.ExtraArgs, 0, len(m.KubeADM.ExtraArgs))
+	for _, arg := range m.KubeADM.ExtraArgs {
+		extraArgs = append(extraArgs, pke.ExtraArg(arg))
+	}
+
+	return &pke.CreateClusterPKE{
+		Network: pke.Network{
+			ServiceCIDR:      m.Network.ServiceCIDR,
+			PodCIDR:          m.Network.PodCIDR,
+			Provider:         pke.NetworkProvider(m.Network.Provider),
+			APIServerAddress: m.Network.APIServerAddress,
+			ProviderConfig:   m.Network.CloudProviderConfig,
+		},
+		NodePools: nodePools,
+		Kubernetes: pke.Kubernetes{
+			Version: m.Kubernetes.Version,
+			RBAC: pke.RBAC{
+				Enabled: m.Kubernetes.RBAC.Enabled,
+			},
+		},
+		KubeADM: pke.KubeADM{
+			ExtraArgs: extraArgs,
+		},
+		CRI: pke.CRI{
+			Runtime:       pke.Runtime(m.CRI.Runtime),
+			RuntimeConfig: m.CRI.RuntimeConfig,
+		},
+		DexEnabled: m.DexEnabled,
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
@@ -0,0 +1,98 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cluster/eks"
+)
+
+func TestExportCreateClusterRequest_EKS(t *testing.T) {
+	t.Parallel()
+
+	request := &pkgCluster.CreateClusterRequest{
+		Name:     "test-cluster",
+		Location: "eu-west-1",
+		Cloud:    pkgCluster.Amazon,
+		SecretId: "secret",
+		Properties: &pkgCluster.CreateClusterProperties{
+			CreateClusterEKS: &eks.CreateClusterEKS{
+				Version: "1.12",
+				NodePools: map[string]*eks.NodePool{
+					"pool1": {
+						InstanceType: "m5.large",
+						SpotPrice:    "0.1",
+						Autoscaling:  true,
+						MinCount:     1,
+						MaxCount:     3,
+						Count:        2,
+						Image:        "ami-123",
+						Labels:       map[string]string{"stored": "label"},
+					},
+					"pool2": {
+						InstanceType: "m5.xlarge",
+						MinCount:     1,
+						MaxCount:     1,
+						Count:        1,
+					},
+				},
+				Vpc: &eks.ClusterVPC{
+					VpcId: "vpc-123",
+					Cidr:  "10.0.0.0/16",
+				},
+				Subnets: []*eks.ClusterSubnet{
+					{SubnetId: "subnet-123", Cidr: "10.0.1.0/24"},
+				},
+			},
+		},
+		ScaleOptions: &pkgCluster.ScaleOptions{
+			Enabled:    true,
+			DesiredCpu: 4,
+			DesiredMem: 8,
+			Excludes:   []string{"app"},
+		},
+		TtlMinutes: 60,
+	}
+
+	commonCluster, err := CreateEKSClusterFromRequest(request, 1, 1)
+	require.NoError(t, err)
+
+	// scale options are only returned once persisted
+	commonCluster.modelCluster.ScaleOptions.ID = 1
+	commonCluster.SetMonitoring(true)
+
+	exported, err := ExportCreateClusterRequest(commonCluster, map[string]map[string]string{
+		"pool2": {"live": "label"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, request.Name, exported.Name)
+	assert.Equal(t, request.Location, exported.Location)
+	assert.Equal(t, request.Cloud, exported.Cloud)
+	assert.Equal(t, request.SecretId, exported.SecretId)
+	assert.Equal(t, request.TtlMinutes, exported.TtlMinutes)
+	assert.Equal(t, request.ScaleOptions, exported.ScaleOptions)
+	assert.Equal(t, pkgCluster.PostHooks{pkgCluster.InstallMonitoring: nil}, exported.PostHooks)
+
+	expected := *request.Properties.CreateClusterEKS
+	expected.NodePools["pool2"].Labels = map[string]string{"live": "label"}
+
+	assert.Equal(t, &expected, exported.Properties.CreateClusterEKS)
+}
@@ -0,0 +1,161 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// HibernationController periodically checks hibernation schedules and scales node pools
+// of clusters down outside working hours and back up when working hours start.
+type HibernationController struct {
+	manager   *HibernationManager
+	schedules hibernationSchedules
+
+	// checkInterval is how often the schedules are evaluated
+	checkInterval time.Duration
+
+	stop chan struct{}
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewHibernationController instantiates a new cluster hibernation controller
+func NewHibernationController(
+	manager *HibernationManager,
+	schedules hibernationSchedules,
+	checkInterval time.Duration,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *HibernationController {
+	return &HibernationController{
+		manager:       manager,
+		schedules:     schedules,
+		checkInterval: checkInterval,
+		stop:          make(chan struct{}),
+		logger:        logger,
+		errorHandler:  errorHandler,
+	}
+}
+
+func (c *HibernationController) Start() error {
+	c.logger.Info("starting cluster hibernation controller")
+
+	go c.run()
+
+	return nil
+}
+
+func (c *HibernationController) Stop() {
+	c.logger.Info("shutting cluster hibernation controller")
+	close(c.stop)
+}
+
+func (c *HibernationController) run() {
+	ticker := time.NewTicker(c.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		c.checkSchedules(time.Now())
+
+		select {
+		case <-ticker.C:
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *HibernationController) checkSchedules(now time.Time) {
+	schedules, err := c.schedules.FindActive()
+	if err != nil {
+		c.errorHandler.Handle(err)
+
+		return
+	}
+
+	for i := range schedules {
+		if err := c.handleSchedule(&schedules[i], now); err != nil {
+			c.errorHandler.Handle(err)
+		}
+	}
+}
+
+func (c *HibernationController) handleSchedule(schedule *intCluster.HibernationScheduleModel, now time.Time) error {
+	ctx := context.Background()
+
+	cluster, err := c.manager.clusters.GetClusterByIDOnly(ctx, schedule.ClusterID)
+	if intCluster.IsClusterNotFoundError(err) {
+		c.logger.WithField("clusterID", schedule.ClusterID).Info("cluster not found, removing its hibernation schedule")
+
+		return c.schedules.Delete(schedule.ClusterID)
+	} else if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve cluster", "clusterID", schedule.ClusterID)
+	}
+
+	clusterDetail, err := cluster.GetStatus()
+	if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve cluster details", "clusterID", schedule.ClusterID)
+	}
+
+	log := c.logger.WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"clusterID":    cluster.GetID(),
+		"cluster":      cluster.GetName(),
+		"status":       clusterDetail.Status,
+		"hibernated":   schedule.Hibernated,
+	})
+
+	// clusters being updated are picked up again on the next check
+	if clusterDetail.Status != pkgCluster.Running && clusterDetail.Status != pkgCluster.Warning {
+		log.Debugf("cluster is not in any of [%s, %s] states, skip further processing", pkgCluster.Running, pkgCluster.Warning)
+
+		return nil
+	}
+
+	sleepTime := false
+	if schedule.Enabled {
+		parsedSchedule, err := intCluster.ParseHibernationSchedule(*schedule)
+		if err != nil {
+			return emperror.WrapWith(err, "invalid hibernation schedule", "clusterID", schedule.ClusterID)
+		}
+
+		sleepTime = parsedSchedule.IsSleepTime(now)
+	}
+
+	switch {
+	case sleepTime && !schedule.Hibernated:
+		err = c.manager.Hibernate(ctx, cluster, schedule)
+		if err != nil {
+			return emperror.WrapWith(err, "failed to hibernate cluster", "clusterID", schedule.ClusterID)
+		}
+
+	case !sleepTime && schedule.Hibernated:
+		err = c.manager.WakeUp(ctx, cluster, schedule)
+		if err != nil {
+			return emperror.WrapWith(err, "failed to wake up cluster", "clusterID", schedule.ClusterID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,362 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pipelineContext "github.com/banzaicloud/pipeline/internal/platform/context"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/banzaicloud/pipeline/pkg/cluster/pke"
+)
+
+type hibernationSchedules interface {
+	FindByCluster(clusterID uint) (*intCluster.HibernationScheduleModel, error)
+	FindActive() ([]intCluster.HibernationScheduleModel, error)
+	Save(schedule *intCluster.HibernationScheduleModel) error
+	Delete(clusterID uint) error
+}
+
+// HibernationManager manages hibernation schedules and scales node pools of hibernated clusters.
+type HibernationManager struct {
+	schedules       hibernationSchedules
+	clusters        *Manager
+	externalBaseURL string
+
+	logger logrus.FieldLogger
+}
+
+// NewHibernationManager returns a new HibernationManager instance.
+func NewHibernationManager(schedules hibernationSchedules, clusters *Manager, externalBaseURL string, logger logrus.FieldLogger) *HibernationManager {
+	return &HibernationManager{
+		schedules:       schedules,
+		clusters:        clusters,
+		externalBaseURL: externalBaseURL,
+		logger:          logger,
+	}
+}
+
+// GetSchedule returns the hibernation schedule of a cluster or nil if there is none.
+func (m *HibernationManager) GetSchedule(ctx context.Context, commonCluster CommonCluster) (*intCluster.HibernationScheduleModel, error) {
+	return m.schedules.FindByCluster(commonCluster.GetID())
+}
+
+// SetSchedule creates or replaces the hibernation schedule of a cluster.
+func (m *HibernationManager) SetSchedule(
+	ctx context.Context,
+	commonCluster CommonCluster,
+	schedule intCluster.HibernationScheduleModel,
+	userID uint,
+) (*intCluster.HibernationScheduleModel, error) {
+	if !isHibernationSupported(commonCluster) {
+		return nil, errors.WithStack(&invalidError{errors.Errorf("hibernation is not supported for %s clusters", commonCluster.GetCloud())})
+	}
+
+	if schedule.Weekdays == "" {
+		schedule.Weekdays = intCluster.DefaultHibernationWeekdays
+	}
+
+	if _, err := intCluster.ParseHibernationSchedule(schedule); err != nil {
+		return nil, errors.WithStack(&invalidError{err})
+	}
+
+	clusterDetail, err := commonCluster.GetStatus()
+	if err != nil {
+		return nil, emperror.WrapWith(err, "failed to retrieve cluster details", "clusterID", commonCluster.GetID())
+	}
+
+	nodePools := []string(schedule.NodePools)
+	if len(nodePools) == 0 {
+		for name := range clusterDetail.NodePools {
+			nodePools = append(nodePools, name)
+		}
+	}
+
+	for _, name := range nodePools {
+		nodePool, ok := clusterDetail.NodePools[name]
+		if !ok {
+			return nil, errors.WithStack(&invalidError{errors.Errorf("unable to find node pool with name: %s", name)})
+		}
+
+		if nodePool != nil && !canHibernateNodePool(commonCluster, nodePool) {
+			return nil, errors.WithStack(&invalidError{errors.Errorf(
+				"node pool %s is autoscaled, autoscaled node pools of %s clusters cannot be hibernated",
+				name,
+				commonCluster.GetCloud(),
+			)})
+		}
+	}
+
+	current, err := m.schedules.FindByCluster(commonCluster.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.ClusterID = commonCluster.GetID()
+	schedule.CreatedBy = userID
+
+	// keep the saved sizes of an already hibernated cluster so that it can be woken up later
+	if current != nil {
+		schedule.ID = current.ID
+		schedule.CreatedAt = current.CreatedAt
+		schedule.Hibernated = current.Hibernated
+		schedule.HibernatedAt = current.HibernatedAt
+		schedule.SavedNodePools = current.SavedNodePools
+	}
+
+	if err := m.schedules.Save(&schedule); err != nil {
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// DeleteSchedule removes the hibernation schedule of a cluster and wakes it up if it is hibernated.
+func (m *HibernationManager) DeleteSchedule(ctx context.Context, commonCluster CommonCluster) error {
+	schedule, err := m.schedules.FindByCluster(commonCluster.GetID())
+	if err != nil {
+		return err
+	}
+
+	if schedule == nil {
+		return nil
+	}
+
+	if schedule.Hibernated {
+		if err := m.WakeUp(ctx, commonCluster, schedule); err != nil {
+			return err
+		}
+	}
+
+	return m.schedules.Delete(commonCluster.GetID())
+}
+
+// Hibernate scales the scheduled node pools of a cluster down and saves their current sizes.
+func (m *HibernationManager) Hibernate(ctx context.Context, commonCluster CommonCluster, schedule *intCluster.HibernationScheduleModel) error {
+	logger := m.getLogger(ctx, commonCluster)
+
+	clusterDetail, err := commonCluster.GetStatus()
+	if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve cluster details", "clusterID", commonCluster.GetID())
+	}
+
+	nodePools := []string(schedule.NodePools)
+	if len(nodePools) == 0 {
+		for name := range clusterDetail.NodePools {
+			nodePools = append(nodePools, name)
+		}
+	}
+
+	saved := make(intCluster.HibernationNodePoolSizes)
+	targets := make(intCluster.HibernationNodePoolSizes)
+
+	for _, name := range nodePools {
+		nodePool, ok := clusterDetail.NodePools[name]
+		if !ok || nodePool == nil {
+			logger.WithField("nodePool", name).Warn("node pool not found, skip hibernating it")
+
+			continue
+		}
+
+		// autoscaling may have been enabled since the schedule was set
+		if !canHibernateNodePool(commonCluster, nodePool) {
+			logger.WithField("nodePool", name).Warn("node pool is autoscaled, skip hibernating it")
+
+			continue
+		}
+
+		saved[name] = intCluster.HibernationNodePoolSize{
+			Autoscaling: nodePool.Autoscaling,
+			MinCount:    nodePool.MinCount,
+			MaxCount:    nodePool.MaxCount,
+			Count:       nodePool.Count,
+		}
+
+		count := hibernatedNodePoolCount(nodePool, schedule.MinCount)
+		targets[name] = intCluster.HibernationNodePoolSize{
+			MinCount: count,
+			MaxCount: nodePool.MaxCount,
+			Count:    count,
+		}
+	}
+
+	if len(targets) == 0 {
+		logger.Info("no node pools to hibernate")
+
+		return nil
+	}
+
+	logger.Info("hibernating cluster")
+
+	if err := m.resize(ctx, commonCluster, clusterDetail, targets, schedule.CreatedBy); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	schedule.Hibernated = true
+	schedule.HibernatedAt = &now
+	schedule.SavedNodePools = saved
+
+	return m.schedules.Save(schedule)
+}
+
+// WakeUp restores the node pool sizes of a hibernated cluster.
+func (m *HibernationManager) WakeUp(ctx context.Context, commonCluster CommonCluster, schedule *intCluster.HibernationScheduleModel) error {
+	logger := m.getLogger(ctx, commonCluster)
+
+	clusterDetail, err := commonCluster.GetStatus()
+	if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve cluster details", "clusterID", commonCluster.GetID())
+	}
+
+	targets := make(intCluster.HibernationNodePoolSizes)
+	for name, size := range schedule.SavedNodePools {
+		if _, ok := clusterDetail.NodePools[name]; !ok {
+			logger.WithField("nodePool", name).Warn("node pool not found, skip waking it up")
+
+			continue
+		}
+
+		targets[name] = size
+	}
+
+	if len(targets) > 0 {
+		logger.Info("waking up cluster")
+
+		if err := m.resize(ctx, commonCluster, clusterDetail, targets, schedule.CreatedBy); err != nil {
+			return err
+		}
+	}
+
+	schedule.Hibernated = false
+	schedule.HibernatedAt = nil
+	schedule.SavedNodePools = nil
+
+	return m.schedules.Save(schedule)
+}
+
+// resize initiates a node pool update through the regular cluster update flow.
+func (m *HibernationManager) resize(
+	ctx context.Context,
+	commonCluster CommonCluster,
+	clusterDetail *pkgCluster.GetClusterStatusResponse,
+	sizes intCluster.HibernationNodePoolSizes,
+	userID uint,
+) error {
+	var updater clusterUpdater
+
+	switch commonCluster.(type) {
+	case *EC2ClusterPKE:
+		// PKE updates the whole set of node pools, unchanged ones have to be sent as well
+		nodePools := make(pke.UpdateNodePools, len(clusterDetail.NodePools))
+		for name, nodePool := range clusterDetail.NodePools {
+			updateNodePool := pke.UpdateNodePool{
+				InstanceType: nodePool.InstanceType,
+				SpotPrice:    nodePool.SpotPrice,
+				Autoscaling:  nodePool.Autoscaling,
+				MinCount:     nodePool.MinCount,
+				MaxCount:     nodePool.MaxCount,
+				Count:        nodePool.Count,
+			}
+
+			if size, ok := sizes[name]; ok {
+				updateNodePool.Autoscaling = size.Autoscaling
+				updateNodePool.MinCount = size.MinCount
+				updateNodePool.MaxCount = size.MaxCount
+				updateNodePool.Count = size.Count
+			}
+
+			nodePools[name] = updateNodePool
+		}
+
+		request := &pkgCluster.UpdateClusterRequest{
+			Cloud: commonCluster.GetCloud(),
+			UpdateProperties: pkgCluster.UpdateProperties{
+				PKE: &pke.UpdateClusterPKE{NodePools: nodePools},
+			},
+			ScaleOptions: commonCluster.GetScaleOptions(),
+			TtlMinutes:   uint(commonCluster.GetTTL() / time.Minute),
+		}
+
+		updater = NewCommonClusterUpdater(request, commonCluster, userID, m.clusters.workflowClient, m.externalBaseURL)
+
+	case *EKSCluster, *AKSCluster, *GKECluster:
+		request := &pkgCluster.UpdateNodePoolsRequest{
+			NodePools: make(map[string]*pkgCluster.NodePoolData, len(sizes)),
+		}
+		for name, size := range sizes {
+			request.NodePools[name] = &pkgCluster.NodePoolData{Count: size.Count}
+		}
+
+		updater = NewCommonNodepoolUpdater(request, commonCluster, userID)
+
+	default:
+		return errors.WithStack(&invalidError{errors.Errorf("hibernation is not supported for %s clusters", commonCluster.GetCloud())})
+	}
+
+	updateCtx := UpdateContext{
+		OrganizationID: commonCluster.GetOrganizationId(),
+		UserID:         userID,
+		ClusterID:      commonCluster.GetID(),
+	}
+
+	return m.clusters.UpdateCluster(ctx, updateCtx, updater)
+}
+
+func (m *HibernationManager) getLogger(ctx context.Context, commonCluster CommonCluster) logrus.FieldLogger {
+	return pipelineContext.LoggerWithCorrelationID(ctx, m.logger).WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"clusterID":    commonCluster.GetID(),
+		"cluster":      commonCluster.GetName(),
+	})
+}
+
+func isHibernationSupported(commonCluster CommonCluster) bool {
+	switch commonCluster.(type) {
+	case *EC2ClusterPKE, *EKSCluster, *AKSCluster, *GKECluster:
+		return true
+	default:
+		return false
+	}
+}
+
+// canHibernateNodePool returns true if a node pool can be scaled down during hibernation.
+// Only PKE node pools can have their autoscaling disabled, on other clouds the autoscaler
+// would keep an autoscaled node pool between its limits.
+func canHibernateNodePool(commonCluster CommonCluster, nodePool *pkgCluster.NodePoolStatus) bool {
+	if _, ok := commonCluster.(*EC2ClusterPKE); ok {
+		return true
+	}
+
+	return !nodePool.Autoscaling
+}
+
+// hibernatedNodePoolCount returns the size a node pool is scaled down to during hibernation.
+func hibernatedNodePoolCount(nodePool *pkgCluster.NodePoolStatus, minCount int) int {
+	count := minCount
+
+	// never scale up a node pool when hibernating it
+	if count > nodePool.Count {
+		count = nodePool.Count
+	}
+
+	return count
+}
@@ -0,0 +1,62 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+func TestCanHibernateNodePool(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cluster     CommonCluster
+		autoscaling bool
+		expected    bool
+	}{
+		"pke":            {&EC2ClusterPKE{}, false, true},
+		"autoscaled pke": {&EC2ClusterPKE{}, true, true},
+		"eks":            {&EKSCluster{}, false, true},
+		"autoscaled eks": {&EKSCluster{}, true, false},
+		"autoscaled aks": {&AKSCluster{}, true, false},
+		"autoscaled gke": {&GKECluster{}, true, false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			nodePool := &pkgCluster.NodePoolStatus{Autoscaling: test.autoscaling, MinCount: 2, MaxCount: 5, Count: 3}
+
+			assert.Equal(t, test.expected, canHibernateNodePool(test.cluster, nodePool))
+		})
+	}
+}
+
+func TestHibernatedNodePoolCount(t *testing.T) {
+	t.Parallel()
+
+	nodePool := &pkgCluster.NodePoolStatus{Count: 3}
+
+	assert.Equal(t, 0, hibernatedNodePoolCount(nodePool, 0))
+	assert.Equal(t, 1, hibernatedNodePoolCount(nodePool, 1))
+	assert.Equal(t, 3, hibernatedNodePoolCount(nodePool, 5), "node pools should never be scaled up")
+}
@@ -16,6 +16,7 @@ package cluster
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -25,14 +26,22 @@ import (
 	"go.uber.org/cadence/activity"
 	"go.uber.org/cadence/workflow"
 
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
 )
 
 const RunPostHooksWorkflowName = "run-posthooks"
 
+const postHookStatusChangeID = "posthook-status"
+
+const organizationPostHooksChangeID = "organization-posthooks"
+
 type RunPostHooksWorkflowInput struct {
 	ClusterID uint
 	PostHooks []RunPostHooksWorkflowInputPostHook
+
+	// OrganizationPostHooks appends the enabled user defined post hooks of the organization after the given ones.
+	OrganizationPostHooks bool
 }
 
 type RunPostHooksWorkflowInputPostHook struct {
@@ -56,6 +65,34 @@ func RunPostHooksWorkflow(ctx workflow.Context, input RunPostHooksWorkflowInput)
 
 	ctx = workflow.WithActivityOptions(ctx, ao)
 
+	if input.OrganizationPostHooks && workflow.GetVersion(ctx, organizationPostHooksChangeID, workflow.DefaultVersion, 1) == 1 {
+		activityInput := ListOrganizationPostHooksActivityInput{
+			ClusterID: input.ClusterID,
+		}
+
+		var organizationPostHooks []RunPostHooksWorkflowInputPostHook
+
+		err := workflow.ExecuteActivity(ctx, ListOrganizationPostHooksActivityName, activityInput).Get(ctx, &organizationPostHooks)
+		if err != nil {
+			return err
+		}
+
+		input.PostHooks = append(input.PostHooks, organizationPostHooks...)
+	}
+
+	// post hook statuses are only recorded by workflows started after they were introduced
+	if workflow.GetVersion(ctx, postHookStatusChangeID, workflow.DefaultVersion, 1) == 1 {
+		activityInput := MarkPostHooksPendingActivityInput{
+			ClusterID: input.ClusterID,
+			PostHooks: input.PostHooks,
+		}
+
+		err := workflow.ExecuteActivity(ctx, MarkPostHooksPendingActivityName, activityInput).Get(ctx, nil)
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, hook := range input.PostHooks {
 		activityInput := RunPostHookActivityInput{
 			ClusterID: input.ClusterID,
@@ -95,16 +132,18 @@ type RunPostHookActivityInput struct {
 }
 
 type RunPostHookActivity struct {
-	manager *Manager
+	manager  *Manager
+	statuses postHookStatuses
 }
 
-func NewRunPostHookActivity(manager *Manager) *RunPostHookActivity {
+func NewRunPostHookActivity(manager *Manager, statuses postHookStatuses) *RunPostHookActivity {
 	return &RunPostHookActivity{
-		manager: manager,
+		manager:  manager,
+		statuses: statuses,
 	}
 }
-func (a *RunPostHookActivity) Execute(ctx context.Context, input RunPostHookActivityInput) error {
-	hook, ok := HookMap[input.HookName]
+func (a *RunPostHookActivity) Execute(ctx context.Context, input RunPostHookActivityInput) (err error) {
+	hook, ok := getPostHookFunction(input.HookName)
 	if !ok {
 		return errors.New("hook function not found")
 	}
@@ -130,6 +169,16 @@ func (a *RunPostHookActivity) Execute(ctx context.Context, input RunPostHookActi
 
 	logger.Infow("starting posthook function", "param", input.HookParam)
 
+	if statusErr := a.statuses.MarkRunning(input.ClusterID, input.HookName); statusErr != nil {
+		logger.Errorw("failed to record posthook status", "error", statusErr.Error())
+	}
+
+	defer func() {
+		if statusErr := a.statuses.MarkFinished(input.ClusterID, input.HookName, err); statusErr != nil {
+			logger.Errorw("failed to record posthook status", "error", statusErr.Error())
+		}
+	}()
+
 	statusMsg := fmt.Sprintf("running %s", hook)
 	if err := hook.Do(cluster); err != nil {
 		err := emperror.Wrap(err, "posthook failed")
@@ -144,3 +193,102 @@ func (a *RunPostHookActivity) Execute(ctx context.Context, input RunPostHookActi
 
 	return nil
 }
+
+const MarkPostHooksPendingActivityName = "mark-posthooks-pending"
+
+type MarkPostHooksPendingActivityInput struct {
+	ClusterID uint
+	PostHooks []RunPostHooksWorkflowInputPostHook
+}
+
+// MarkPostHooksPendingActivity resets the recorded state of the post hooks about to be run.
+type MarkPostHooksPendingActivity struct {
+	statuses postHookStatuses
+}
+
+func NewMarkPostHooksPendingActivity(statuses postHookStatuses) *MarkPostHooksPendingActivity {
+	return &MarkPostHooksPendingActivity{
+		statuses: statuses,
+	}
+}
+
+// Re-run post hooks keep their recorded position, new ones are appended after the recorded ones.
+func (a *MarkPostHooksPendingActivity) Execute(ctx context.Context, input MarkPostHooksPendingActivityInput) error {
+	recorded, err := a.statuses.FindByCluster(input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	positions := make(map[string]int, len(recorded))
+	nextPosition := 0
+
+	for _, status := range recorded {
+		positions[status.Name] = status.Position
+
+		if status.Position >= nextPosition {
+			nextPosition = status.Position + 1
+		}
+	}
+
+	postHooks := make([]intCluster.PostHookStatusModel, 0, len(input.PostHooks))
+
+	for _, hook := range input.PostHooks {
+		var param string
+		if hook.Param != nil {
+			rawParam, err := json.Marshal(hook.Param)
+			if err != nil {
+				return emperror.WrapWith(err, "failed to encode posthook param", "postHook", hook.Name)
+			}
+
+			param = string(rawParam)
+		}
+
+		position, ok := positions[hook.Name]
+		if !ok {
+			position = nextPosition
+			positions[hook.Name] = position
+			nextPosition++
+		}
+
+		postHooks = append(postHooks, intCluster.PostHookStatusModel{
+			Name:     hook.Name,
+			Position: position,
+			Param:    param,
+		})
+	}
+
+	return a.statuses.MarkPending(input.ClusterID, postHooks)
+}
+
+const ListOrganizationPostHooksActivityName = "list-organization-posthooks"
+
+type ListOrganizationPostHooksActivityInput struct {
+	ClusterID uint
+}
+
+// ListOrganizationPostHooksActivity returns the enabled user defined post hooks of the organization of a cluster.
+type ListOrganizationPostHooksActivity struct {
+	manager   *Manager
+	postHooks organizationPostHooks
+}
+
+func NewListOrganizationPostHooksActivity(manager *Manager, postHooks organizationPostHooks) *ListOrganizationPostHooksActivity {
+	return &ListOrganizationPostHooksActivity{
+		manager:   manager,
+		postHooks: postHooks,
+	}
+}
+
+func (a *ListOrganizationPostHooksActivity) Execute(ctx context.Context, input ListOrganizationPostHooksActivityInput) ([]RunPostHooksWorkflowInputPostHook, error) {
+	cluster, err := a.manager.GetClusterByIDOnly(ctx, input.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	postHooks, err := a.postHooks.FindByOrganization(cluster.GetOrganizationId())
+	if err != nil {
+		return nil, err
+	}
+
+	return buildOrganizationPostHookFunctions(postHooks), nil
+}
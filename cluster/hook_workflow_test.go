@@ -0,0 +1,102 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+type postHookStatusesStub struct {
+	recorded []intCluster.PostHookStatusModel
+	pending  []intCluster.PostHookStatusModel
+}
+
+func (s *postHookStatusesStub) FindByCluster(clusterID uint) ([]intCluster.PostHookStatusModel, error) {
+	return s.recorded, nil
+}
+
+func (s *postHookStatusesStub) MarkPending(clusterID uint, postHooks []intCluster.PostHookStatusModel) error {
+	s.pending = postHooks
+
+	return nil
+}
+
+func (s *postHookStatusesStub) MarkRunning(clusterID uint, name string) error {
+	return nil
+}
+
+func (s *postHookStatusesStub) MarkFinished(clusterID uint, name string, hookErr error) error {
+	return nil
+}
+
+func TestMarkPostHooksPendingActivity_KeepsRecordedPositions(t *testing.T) {
+	statuses := &postHookStatusesStub{
+		recorded: []intCluster.PostHookStatusModel{
+			{Name: "CreatePipelineNamespacePostHook", Position: 0},
+			{Name: "InstallLogging", Position: 1},
+			{Name: "InstallMonitoring", Position: 2},
+		},
+	}
+
+	activity := NewMarkPostHooksPendingActivity(statuses)
+
+	err := activity.Execute(context.Background(), MarkPostHooksPendingActivityInput{
+		ClusterID: 1,
+		PostHooks: []RunPostHooksWorkflowInputPostHook{
+			{Name: "InstallMonitoring"},
+			{Name: "InstallKubernetesDashboardPostHook"},
+			{Name: "InstallLogging", Param: map[string]string{"bucket": "logs"}},
+			{Name: "InstallHorizontalPodAutoscalerPostHook"},
+		},
+	})
+	require.NoError(t, err)
+
+	expected := []intCluster.PostHookStatusModel{
+		{Name: "InstallMonitoring", Position: 2},
+		{Name: "InstallKubernetesDashboardPostHook", Position: 3},
+		{Name: "InstallLogging", Position: 1, Param: `{"bucket":"logs"}`},
+		{Name: "InstallHorizontalPodAutoscalerPostHook", Position: 4},
+	}
+
+	assert.Equal(t, expected, statuses.pending)
+}
+
+func TestMarkPostHooksPendingActivity_FirstRun(t *testing.T) {
+	statuses := &postHookStatusesStub{}
+
+	activity := NewMarkPostHooksPendingActivity(statuses)
+
+	err := activity.Execute(context.Background(), MarkPostHooksPendingActivityInput{
+		ClusterID: 1,
+		PostHooks: []RunPostHooksWorkflowInputPostHook{
+			{Name: "CreatePipelineNamespacePostHook"},
+			{Name: "InstallLogging"},
+		},
+	})
+	require.NoError(t, err)
+
+	expected := []intCluster.PostHookStatusModel{
+		{Name: "CreatePipelineNamespacePostHook", Position: 0},
+		{Name: "InstallLogging", Position: 1},
+	}
+
+	assert.Equal(t, expected, statuses.pending)
+}
@@ -122,6 +122,21 @@ var HookMap = map[string]PostFunctioner{
 	},
 }
 
+// getPostHookFunction returns the posthook function registered with the given name.
+// User defined organization posthooks are resolved by their name prefix.
+func getPostHookFunction(name string) (PostFunctioner, bool) {
+	if strings.HasPrefix(name, pkgCluster.OrganizationPostHookPrefix) {
+		return &PostFunctionWithParam{
+			f:            InstallOrganizationPostHook,
+			ErrorHandler: ErrorHandler{},
+		}, true
+	}
+
+	hook, ok := HookMap[name]
+
+	return hook, ok
+}
+
 // BasePostHookFunctions default posthook functions after cluster create
 // nolint: gochecknoglobals
 var BasePostHookFunctions = []string{
@@ -57,6 +57,11 @@ import (
 	"github.com/banzaicloud/pipeline/secret"
 )
 
+const (
+	externalDNSChart       = pkgHelm.StableRepository + "/external-dns"
+	externalDNSReleaseName = "dns"
+)
+
 // pollingKubernetesConfig polls kubeconfig from the cloud
 func pollingKubernetesConfig(cluster CommonCluster) ([]byte, error) {
 
@@ -549,6 +554,30 @@ func InstallAnchoreImageValidator(cluster CommonCluster, param pkgCluster.PostHo
 	return nil
 }
 
+// InstallOrganizationPostHook installs the Helm chart of a user defined organization posthook
+func InstallOrganizationPostHook(cluster CommonCluster, param pkgCluster.PostHookParam) error {
+	var postHookParam pkgCluster.OrganizationPostHookParam
+	err := castToPostHookParam(&param, &postHookParam)
+	if err != nil {
+		return emperror.Wrap(err, "posthook param failed")
+	}
+
+	err = installDeployment(
+		cluster,
+		postHookParam.Namespace,
+		postHookParam.Chart,
+		postHookParam.ReleaseName,
+		[]byte(postHookParam.Values),
+		postHookParam.ChartVersion,
+		false,
+	)
+	if err != nil {
+		return emperror.WrapWith(err, "installing organization posthook chart failed", "chart", postHookParam.Chart)
+	}
+
+	return nil
+}
+
 func installAllowAllWhitelist(cluster CommonCluster) error {
 	kubeConfig, err := cluster.GetK8sConfig()
 	if err != nil {
@@ -817,7 +846,7 @@ func RegisterDomainPostHook(commonCluster CommonCluster) error {
 	}
 	chartVersion := viper.GetString(pipConfig.DNSExternalDnsChartVersion)
 
-	return installDeployment(commonCluster, route53SecretNamespace, pkgHelm.StableRepository+"/external-dns", "dns", externalDnsValuesJson, chartVersion, false)
+	return installDeployment(commonCluster, route53SecretNamespace, externalDNSChart, externalDNSReleaseName, externalDnsValuesJson, chartVersion, false)
 }
 
 // LabelNodesWithNodePoolName add node pool name labels for all nodes.
@@ -0,0 +1,144 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Masterminds/semver"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/cadence/client"
+
+	pipelineContext "github.com/banzaicloud/pipeline/internal/platform/context"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+type kubernetesUpgrader interface {
+	GetKubernetesVersion() (string, error)
+	UpgradePKECluster(ctx context.Context, version string, rollbackOnFailure bool, workflowClient client.Client, externalBaseURL string) error
+}
+
+// KubernetesUpgradeManager upgrades the Kubernetes version of clusters.
+type KubernetesUpgradeManager struct {
+	clusters        *Manager
+	externalBaseURL string
+
+	logger logrus.FieldLogger
+}
+
+// NewKubernetesUpgradeManager returns a new KubernetesUpgradeManager instance.
+func NewKubernetesUpgradeManager(clusters *Manager, externalBaseURL string, logger logrus.FieldLogger) *KubernetesUpgradeManager {
+	return &KubernetesUpgradeManager{
+		clusters:        clusters,
+		externalBaseURL: externalBaseURL,
+		logger:          logger,
+	}
+}
+
+// Upgrade starts upgrading the Kubernetes version of a cluster.
+// The progress of the upgrade is reported through the status (message) of the cluster.
+// When rollbackOnFailure is not set, a failed upgrade is paused and can be resumed by starting it again.
+func (m *KubernetesUpgradeManager) Upgrade(ctx context.Context, commonCluster CommonCluster, version string, rollbackOnFailure bool) error {
+	logger := pipelineContext.LoggerWithCorrelationID(ctx, m.logger).WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"clusterID":    commonCluster.GetID(),
+		"cluster":      commonCluster.GetName(),
+		"version":      version,
+	})
+
+	upgrader, ok := commonCluster.(kubernetesUpgrader)
+	if !ok {
+		return errors.WithStack(&invalidError{errors.Errorf("Kubernetes upgrade is not supported for %s clusters", commonCluster.GetCloud())})
+	}
+
+	clusterStatus, err := commonCluster.GetStatus()
+	if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve cluster status", "clusterID", commonCluster.GetID())
+	}
+
+	// a paused upgrade (warning) can be resumed
+	if clusterStatus.Status != pkgCluster.Running && clusterStatus.Status != pkgCluster.Warning {
+		return errors.WithStack(&invalidError{errors.Errorf("cluster is not running (status: %s)", clusterStatus.Status)})
+	}
+
+	currentVersion, err := upgrader.GetKubernetesVersion()
+	if err != nil {
+		return emperror.WrapWith(err, "failed to retrieve Kubernetes version", "clusterID", commonCluster.GetID())
+	}
+
+	if err := validateKubernetesUpgrade(currentVersion, version); err != nil {
+		return errors.WithStack(&invalidError{err})
+	}
+
+	err = commonCluster.SetStatus(pkgCluster.Updating, fmt.Sprintf("Kubernetes upgrade to %s is in progress", version))
+	if err != nil {
+		return emperror.Wrap(err, "could not update cluster status")
+	}
+
+	logger.Info("upgrading Kubernetes")
+
+	go func() {
+		errorHandler := m.clusters.getClusterErrorHandler(ctx, commonCluster)
+		defer emperror.HandleRecover(errorHandler.WithStatus(pkgCluster.Warning, "internal error while upgrading Kubernetes"))
+
+		err := upgrader.UpgradePKECluster(ctx, version, rollbackOnFailure, m.clusters.workflowClient, m.externalBaseURL)
+		if err != nil {
+			if setErr := commonCluster.SetStatus(pkgCluster.Warning, err.Error()); setErr != nil {
+				logger.Error(setErr.Error())
+			}
+			m.clusters.events.ClusterUpdated(commonCluster.GetID())
+
+			errorHandler.Handle(emperror.Wrap(err, "error upgrading Kubernetes"))
+
+			return
+		}
+
+		if err := commonCluster.SetStatus(pkgCluster.Running, pkgCluster.RunningMessage); err != nil {
+			errorHandler.Handle(emperror.Wrap(err, "could not update cluster status"))
+		}
+		m.clusters.events.ClusterUpdated(commonCluster.GetID())
+
+		logger.Info("Kubernetes upgraded successfully")
+	}()
+
+	return nil
+}
+
+// validateKubernetesUpgrade checks whether a cluster can be upgraded from the current Kubernetes version to the target one.
+// The version can be re-applied (to resume a paused upgrade), but minor versions cannot be skipped.
+func validateKubernetesUpgrade(current string, target string) error {
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return errors.Wrapf(err, "invalid current Kubernetes version %q", current)
+	}
+
+	targetVersion, err := semver.NewVersion(target)
+	if err != nil {
+		return errors.Wrapf(err, "invalid Kubernetes version %q", target)
+	}
+
+	if targetVersion.LessThan(currentVersion) {
+		return errors.Errorf("Kubernetes version cannot be downgraded from %s to %s", current, target)
+	}
+
+	if targetVersion.Major() != currentVersion.Major() || targetVersion.Minor() > currentVersion.Minor()+1 {
+		return errors.Errorf("Kubernetes cannot be upgraded from %s to %s, upgrade one minor version at a time", current, target)
+	}
+
+	return nil
+}
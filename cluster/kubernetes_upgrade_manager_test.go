@@ -0,0 +1,55 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateKubernetesUpgrade(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		current string
+		target  string
+		valid   bool
+	}{
+		"patch upgrade":   {"1.13.3", "1.13.5", true},
+		"minor upgrade":   {"1.12.2", "1.13.5", true},
+		"resume":          {"1.13.5", "1.13.5", true},
+		"skipping minor":  {"1.12.2", "1.14.1", false},
+		"major upgrade":   {"1.13.3", "2.0.0", false},
+		"downgrade":       {"1.13.3", "1.12.2", false},
+		"invalid target":  {"1.13.3", "latest", false},
+		"invalid current": {"", "1.13.5", false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateKubernetesUpgrade(test.current, test.target)
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
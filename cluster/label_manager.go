@@ -0,0 +1,88 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+type clusterLabels interface {
+	FindByCluster(clusterID uint) (map[string]string, error)
+	FindByClusters(clusterIDs []uint) (map[uint]map[string]string, error)
+	Replace(clusterID uint, labels map[string]string) error
+}
+
+// LabelManager manages the user defined labels of clusters (not to be confused with node pool labels).
+type LabelManager struct {
+	labels clusterLabels
+}
+
+// NewLabelManager returns a new LabelManager instance.
+func NewLabelManager(labels clusterLabels) *LabelManager {
+	return &LabelManager{
+		labels: labels,
+	}
+}
+
+// GetLabels returns the labels of a cluster.
+func (m *LabelManager) GetLabels(ctx context.Context, clusterID uint) (map[string]string, error) {
+	return m.labels.FindByCluster(clusterID)
+}
+
+// SetLabels replaces the labels of a cluster.
+func (m *LabelManager) SetLabels(ctx context.Context, clusterID uint, clusterLabels map[string]string) error {
+	if err := pkgCluster.ValidateLabels(clusterLabels); err != nil {
+		return errors.WithStack(&invalidError{err})
+	}
+
+	return m.labels.Replace(clusterID, clusterLabels)
+}
+
+// SelectClusters returns the clusters matching a Kubernetes style label selector (eg. team=a,env!=prod)
+// along with the labels of every returned cluster. An empty selector matches every cluster.
+func (m *LabelManager) SelectClusters(
+	ctx context.Context,
+	clusters []CommonCluster,
+	selector string,
+) ([]CommonCluster, map[uint]map[string]string, error) {
+	parsedSelector, err := labels.Parse(selector)
+	if err != nil {
+		return nil, nil, errors.WithStack(&invalidError{errors.WithMessage(err, "invalid label selector")})
+	}
+
+	clusterIDs := make([]uint, 0, len(clusters))
+	for _, cluster := range clusters {
+		clusterIDs = append(clusterIDs, cluster.GetID())
+	}
+
+	clusterLabels, err := m.labels.FindByClusters(clusterIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selected := make([]CommonCluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		if parsedSelector.Matches(labels.Set(clusterLabels[cluster.GetID()])) {
+			selected = append(selected, cluster)
+		}
+	}
+
+	return selected, clusterLabels, nil
+}
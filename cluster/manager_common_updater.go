@@ -94,6 +94,18 @@ func (c *commonUpdater) Validate(ctx context.Context) error {
 
 // Prepare implements the clusterUpdater interface.
 func (c *commonUpdater) Prepare(ctx context.Context) (CommonCluster, error) {
+	if err := c.prepareRequest(); err != nil {
+		return nil, err
+	}
+
+	if err := c.cluster.SetStatus(cluster.Updating, cluster.UpdatingMessage); err != nil {
+		return nil, err
+	}
+	return c.cluster, c.cluster.Persist()
+}
+
+// prepareRequest adds defaults to the update request, validates it and determines what has changed.
+func (c *commonUpdater) prepareRequest() error {
 	c.cluster.AddDefaultsToUpdate(c.request)
 
 	c.scaleOptionsChanged = isDifferent(c.request.ScaleOptions, c.cluster.GetScaleOptions()) == nil
@@ -103,7 +115,7 @@ func (c *commonUpdater) Prepare(ctx context.Context) (CommonCluster, error) {
 	if err := c.cluster.CheckEqualityToUpdate(c.request); err != nil {
 		c.clusterPropertiesChanged = false
 		if !c.scaleOptionsChanged && !c.ttlChanged {
-			return nil, &commonUpdateValidationError{
+			return &commonUpdateValidationError{
 				msg:            err.Error(),
 				invalidRequest: true,
 			}
@@ -111,16 +123,13 @@ func (c *commonUpdater) Prepare(ctx context.Context) (CommonCluster, error) {
 	}
 
 	if err := c.request.Validate(); err != nil {
-		return nil, &commonUpdateValidationError{
+		return &commonUpdateValidationError{
 			msg:            err.Error(),
 			invalidRequest: true,
 		}
 	}
 
-	if err := c.cluster.SetStatus(cluster.Updating, cluster.UpdatingMessage); err != nil {
-		return nil, err
-	}
-	return c.cluster, c.cluster.Persist()
+	return nil
 }
 
 // Update implements the clusterUpdater interface.
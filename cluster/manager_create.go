@@ -72,22 +72,8 @@ func (m *Manager) CreateCluster(ctx context.Context, creationCtx CreationContext
 	}
 
 	logger.Debug("validating secret")
-	if len(creationCtx.SecretIDs) > 0 {
-		var err error
-		for _, secretID := range creationCtx.SecretIDs {
-			err = m.secrets.ValidateSecretType(creationCtx.OrganizationID, secretID, creationCtx.Provider)
-			if err == nil {
-				creationCtx.SecretID = secretID
-				break
-			}
-		}
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		if err := m.secrets.ValidateSecretType(creationCtx.OrganizationID, creationCtx.SecretID, creationCtx.Provider); err != nil {
-			return nil, err
-		}
+	if err := m.validateSecrets(&creationCtx); err != nil {
+		return nil, err
 	}
 
 	logger.Debug("validating creation context")
@@ -149,6 +135,25 @@ func (m *Manager) CreateCluster(ctx context.Context, creationCtx CreationContext
 	return cluster, nil
 }
 
+// validateSecrets validates the secret(s) of the creation context.
+// If multiple secrets are given, the first valid one becomes the cluster secret.
+func (m *Manager) validateSecrets(creationCtx *CreationContext) error {
+	if len(creationCtx.SecretIDs) == 0 {
+		return m.secrets.ValidateSecretType(creationCtx.OrganizationID, creationCtx.SecretID, creationCtx.Provider)
+	}
+
+	var err error
+	for _, secretID := range creationCtx.SecretIDs {
+		err = m.secrets.ValidateSecretType(creationCtx.OrganizationID, secretID, creationCtx.Provider)
+		if err == nil {
+			creationCtx.SecretID = secretID
+			break
+		}
+	}
+
+	return err
+}
+
 func (m *Manager) assertNotExists(ctx CreationContext) error {
 	exists, err := m.clusters.Exists(ctx.OrganizationID, ctx.Name)
 	if err != nil {
@@ -222,6 +227,8 @@ func (m *Manager) createCluster(
 	input := RunPostHooksWorkflowInput{
 		ClusterID: cluster.GetID(),
 		PostHooks: BuildWorkflowPostHookFunctions(postHooks, true),
+
+		OrganizationPostHooks: true,
 	}
 
 	workflowOptions := client.StartWorkflowOptions{
@@ -0,0 +1,222 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+type clusterPlanner interface {
+	// Plan returns the changes the creation or update would make without touching the cloud or the database.
+	Plan(ctx context.Context) (*pkgCluster.ClusterPlan, error)
+}
+
+type planNotSupportedError struct{}
+
+func (planNotSupportedError) Error() string {
+	return "dry run is not supported for this cluster"
+}
+
+func (planNotSupportedError) IsInvalid() bool {
+	return true
+}
+
+// PlanCreateCluster runs the same checks as CreateCluster and returns the changes the creation would make.
+func (m *Manager) PlanCreateCluster(ctx context.Context, creationCtx CreationContext, creator clusterCreator) (*pkgCluster.ClusterPlan, error) {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": creationCtx.OrganizationID,
+		"user":         creationCtx.UserID,
+		"cluster":      creationCtx.Name,
+	})
+
+	planner, ok := creator.(clusterPlanner)
+	if !ok {
+		return nil, errors.WithStack(planNotSupportedError{})
+	}
+
+	if err := m.assertNotExists(creationCtx); err != nil {
+		return nil, err
+	}
+
+	logger.Debug("validating secret")
+	if err := m.validateSecrets(&creationCtx); err != nil {
+		return nil, err
+	}
+
+	logger.Debug("validating creation context")
+	if err := creator.Validate(ctx); err != nil {
+		return nil, errors.Wrap(&invalidError{err}, "validation failed")
+	}
+
+	logger.Debug("planning cluster creation")
+	plan, err := planner.Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.PostHooks = planCreationPostHooks(creationCtx.PostHooks)
+
+	return plan, nil
+}
+
+// PlanUpdateCluster runs the same checks as UpdateCluster and returns the changes the update would make.
+func (m *Manager) PlanUpdateCluster(ctx context.Context, updateCtx UpdateContext, updater clusterUpdater) (*pkgCluster.ClusterPlan, error) {
+	logger := m.getLogger(ctx).WithFields(logrus.Fields{
+		"organization": updateCtx.OrganizationID,
+		"user":         updateCtx.UserID,
+		"cluster":      updateCtx.ClusterID,
+	})
+
+	planner, ok := updater.(clusterPlanner)
+	if !ok {
+		return nil, errors.WithStack(planNotSupportedError{})
+	}
+
+	logger.Debug("validating update context")
+
+	err := updater.Validate(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "cluster update validation failed")
+	}
+
+	logger.Debug("planning cluster update")
+
+	return planner.Plan(ctx)
+}
+
+// planCreationPostHooks returns the post hooks the cluster creation workflow would run.
+func planCreationPostHooks(postHooks pkgCluster.PostHooks) []pkgCluster.PostHookPlan {
+	// BuildWorkflowPostHookFunctions modifies its input
+	hooks := make(pkgCluster.PostHooks, len(postHooks)+1)
+	for name, param := range postHooks {
+		hooks[name] = param
+	}
+
+	// added by the creation flow once the desired labels are known
+	hooks[pkgCluster.SetupNodePoolLabelsSet] = nil
+
+	var plans []pkgCluster.PostHookPlan
+	for _, hook := range BuildWorkflowPostHookFunctions(hooks, true) {
+		plans = append(plans, pkgCluster.PostHookPlan{
+			Name:   hook.Name,
+			Action: pkgCluster.PlanActionAdd,
+			Param:  hook.Param,
+		})
+	}
+
+	return plans
+}
+
+// Plan implements the clusterPlanner interface.
+func (c *commonCreator) Plan(ctx context.Context) (*pkgCluster.ClusterPlan, error) {
+	status, err := c.cluster.GetStatus()
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get desired cluster status")
+	}
+
+	plan := &pkgCluster.ClusterPlan{
+		Name:         status.Name,
+		Cloud:        status.Cloud,
+		Distribution: status.Distribution,
+		Location:     status.Location,
+		ScaleOptions: c.request.ScaleOptions,
+		NodePools:    pkgCluster.PlanNodePools(nil, status.NodePools),
+	}
+
+	if status.Version != "" {
+		plan.Version = &pkgCluster.PlanValueChange{To: status.Version}
+	}
+
+	if c.request.TtlMinutes > 0 {
+		plan.TtlMinutes = &pkgCluster.PlanValueChange{To: c.request.TtlMinutes}
+	}
+
+	return plan, nil
+}
+
+// Plan implements the clusterPlanner interface.
+func (c *commonUpdater) Plan(ctx context.Context) (*pkgCluster.ClusterPlan, error) {
+	if err := c.prepareRequest(); err != nil {
+		return nil, err
+	}
+
+	status, err := c.cluster.GetStatus()
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not get cluster status")
+	}
+
+	plan := &pkgCluster.ClusterPlan{
+		Name:         status.Name,
+		Cloud:        status.Cloud,
+		Distribution: status.Distribution,
+		Location:     status.Location,
+	}
+
+	if c.ttlChanged {
+		plan.TtlMinutes = &pkgCluster.PlanValueChange{
+			From: uint(c.cluster.GetTTL() / time.Minute),
+			To:   c.request.TtlMinutes,
+		}
+	}
+
+	if c.scaleOptionsChanged {
+		plan.ScaleOptions = c.request.ScaleOptions
+	}
+
+	if c.clusterPropertiesChanged {
+		desiredNodePools := getNodePoolsFromUpdateRequest(c.request)
+
+		if c.request.GKE != nil {
+			if c.request.GKE.Master != nil && c.request.GKE.Master.Version != "" && c.request.GKE.Master.Version != status.Version {
+				plan.Version = &pkgCluster.PlanValueChange{
+					From: status.Version,
+					To:   c.request.GKE.Master.Version,
+				}
+			}
+
+			for _, nodePool := range desiredNodePools {
+				nodePool.Version = c.request.GKE.NodeVersion
+			}
+		}
+
+		plan.NodePools = pkgCluster.PlanNodePools(status.NodePools, desiredNodePools)
+	}
+
+	if !plan.HasChanges() {
+		return plan, nil
+	}
+
+	// these steps are executed by the update flow after the cluster has been updated
+	postUpdateSteps := []string{pkgCluster.InstallClusterAutoscalerPostHook}
+	if len(plan.NodePools) > 0 {
+		postUpdateSteps = append(postUpdateSteps, pkgCluster.SetupNodePoolLabelsSet, pkgCluster.LabelNodesWithNodePoolName)
+	}
+
+	for _, step := range postUpdateSteps {
+		plan.PostHooks = append(plan.PostHooks, pkgCluster.PostHookPlan{
+			Name:   step,
+			Action: pkgCluster.PlanActionChange,
+		})
+	}
+
+	return plan, nil
+}
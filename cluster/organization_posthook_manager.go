@@ -0,0 +1,197 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pipelineContext "github.com/banzaicloud/pipeline/internal/platform/context"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+const maxOrganizationPostHookNameLength = 50
+
+type organizationPostHooks interface {
+	FindByOrganization(organizationID uint) ([]intCluster.OrganizationPostHookModel, error)
+	FindOne(organizationID uint, name string) (*intCluster.OrganizationPostHookModel, error)
+	Save(postHook *intCluster.OrganizationPostHookModel) error
+	Delete(postHook *intCluster.OrganizationPostHookModel) error
+}
+
+// OrganizationPostHookManager manages the user defined post hooks of organizations.
+// These post hooks install a Helm chart on every new cluster of the organization after the built-in post hooks.
+type OrganizationPostHookManager struct {
+	postHooks organizationPostHooks
+
+	logger logrus.FieldLogger
+}
+
+// NewOrganizationPostHookManager returns a new OrganizationPostHookManager instance.
+func NewOrganizationPostHookManager(postHooks organizationPostHooks, logger logrus.FieldLogger) *OrganizationPostHookManager {
+	return &OrganizationPostHookManager{
+		postHooks: postHooks,
+		logger:    logger,
+	}
+}
+
+// ListPostHooks returns the post hooks of an organization in execution order.
+func (m *OrganizationPostHookManager) ListPostHooks(ctx context.Context, organizationID uint) ([]intCluster.OrganizationPostHookModel, error) {
+	return m.postHooks.FindByOrganization(organizationID)
+}
+
+// GetPostHook returns a post hook of an organization or nil if it does not exist.
+func (m *OrganizationPostHookManager) GetPostHook(ctx context.Context, organizationID uint, name string) (*intCluster.OrganizationPostHookModel, error) {
+	return m.postHooks.FindOne(organizationID, name)
+}
+
+// CreatePostHook registers a new post hook for an organization.
+func (m *OrganizationPostHookManager) CreatePostHook(ctx context.Context, postHook *intCluster.OrganizationPostHookModel) error {
+	if err := validateOrganizationPostHook(postHook); err != nil {
+		return errors.WithStack(&invalidError{err})
+	}
+
+	existing, err := m.postHooks.FindOne(postHook.OrganizationID, postHook.Name)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		return errors.WithStack(&invalidError{errors.Errorf("posthook %s already exists", postHook.Name)})
+	}
+
+	err = m.postHooks.Save(postHook)
+	if err != nil {
+		return err
+	}
+
+	m.getLogger(ctx, postHook).Info("organization posthook created")
+
+	return nil
+}
+
+// UpdatePostHook replaces the definition of an existing post hook of an organization.
+// If the post hook does not exist nil is returned.
+func (m *OrganizationPostHookManager) UpdatePostHook(
+	ctx context.Context,
+	organizationID uint,
+	name string,
+	update intCluster.OrganizationPostHookModel,
+) (*intCluster.OrganizationPostHookModel, error) {
+	postHook, err := m.postHooks.FindOne(organizationID, name)
+	if err != nil || postHook == nil {
+		return nil, err
+	}
+
+	postHook.Chart = update.Chart
+	postHook.ChartVersion = update.ChartVersion
+	postHook.Namespace = update.Namespace
+	postHook.Values = update.Values
+	postHook.Priority = update.Priority
+	postHook.Enabled = update.Enabled
+
+	if err := validateOrganizationPostHook(postHook); err != nil {
+		return nil, errors.WithStack(&invalidError{err})
+	}
+
+	err = m.postHooks.Save(postHook)
+	if err != nil {
+		return nil, err
+	}
+
+	m.getLogger(ctx, postHook).Info("organization posthook updated")
+
+	return postHook, nil
+}
+
+// DeletePostHook removes a post hook of an organization.
+// It returns false if the post hook does not exist.
+func (m *OrganizationPostHookManager) DeletePostHook(ctx context.Context, organizationID uint, name string) (bool, error) {
+	postHook, err := m.postHooks.FindOne(organizationID, name)
+	if err != nil || postHook == nil {
+		return false, err
+	}
+
+	err = m.postHooks.Delete(postHook)
+	if err != nil {
+		return false, err
+	}
+
+	m.getLogger(ctx, postHook).Info("organization posthook deleted")
+
+	return true, nil
+}
+
+func (m *OrganizationPostHookManager) getLogger(ctx context.Context, postHook *intCluster.OrganizationPostHookModel) logrus.FieldLogger {
+	return pipelineContext.LoggerWithCorrelationID(ctx, m.logger).WithFields(logrus.Fields{
+		"organization": postHook.OrganizationID,
+		"postHook":     postHook.Name,
+	})
+}
+
+// validateOrganizationPostHook checks whether an organization post hook can be installed as a Helm release.
+func validateOrganizationPostHook(postHook *intCluster.OrganizationPostHookModel) error {
+	if len(postHook.Name) > maxOrganizationPostHookNameLength {
+		return errors.Errorf("posthook name must be no more than %d characters", maxOrganizationPostHookNameLength)
+	}
+
+	if errs := validation.IsDNS1123Label(postHook.Name); len(errs) > 0 {
+		return errors.Errorf("invalid posthook name %q: %s", postHook.Name, errs[0])
+	}
+
+	if postHook.Chart == "" {
+		return errors.New("posthook chart must be specified")
+	}
+
+	if errs := validation.IsDNS1123Label(postHook.Namespace); len(errs) > 0 {
+		return errors.Errorf("invalid posthook namespace %q: %s", postHook.Namespace, errs[0])
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal([]byte(postHook.Values), &values); err != nil {
+		return errors.Wrap(err, "posthook values must be a valid YAML object")
+	}
+
+	return nil
+}
+
+// buildOrganizationPostHookFunctions builds posthook workflow input from the enabled organization post hooks.
+func buildOrganizationPostHookFunctions(postHooks []intCluster.OrganizationPostHookModel) []RunPostHooksWorkflowInputPostHook {
+	var workflowPostHooks []RunPostHooksWorkflowInputPostHook
+
+	for _, postHook := range postHooks {
+		if !postHook.Enabled {
+			continue
+		}
+
+		workflowPostHooks = append(workflowPostHooks, RunPostHooksWorkflowInputPostHook{
+			Name: pkgCluster.OrganizationPostHookPrefix + postHook.Name,
+			Param: pkgCluster.OrganizationPostHookParam{
+				Chart:        postHook.Chart,
+				ChartVersion: postHook.ChartVersion,
+				Namespace:    postHook.Namespace,
+				ReleaseName:  postHook.Name,
+				Values:       postHook.Values,
+			},
+		})
+	}
+
+	return workflowPostHooks
+}
@@ -0,0 +1,78 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+func TestValidateOrganizationPostHook(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		postHook intCluster.OrganizationPostHookModel
+		valid    bool
+	}{
+		"valid":             {intCluster.OrganizationPostHookModel{Name: "agent", Chart: "stable/agent", Namespace: "agents", Values: "replicas: 2"}, true},
+		"no values":         {intCluster.OrganizationPostHookModel{Name: "agent", Chart: "stable/agent", Namespace: "agents"}, true},
+		"invalid name":      {intCluster.OrganizationPostHookModel{Name: "Agent_1", Chart: "stable/agent", Namespace: "agents"}, false},
+		"missing chart":     {intCluster.OrganizationPostHookModel{Name: "agent", Namespace: "agents"}, false},
+		"invalid namespace": {intCluster.OrganizationPostHookModel{Name: "agent", Chart: "stable/agent", Namespace: "kube system"}, false},
+		"invalid values":    {intCluster.OrganizationPostHookModel{Name: "agent", Chart: "stable/agent", Namespace: "agents", Values: "- a\n- b"}, false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateOrganizationPostHook(&test.postHook)
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestBuildOrganizationPostHookFunctions(t *testing.T) {
+	t.Parallel()
+
+	postHooks := []intCluster.OrganizationPostHookModel{
+		{Name: "first", Chart: "stable/first", Namespace: "default", Enabled: true},
+		{Name: "disabled", Chart: "stable/disabled", Namespace: "default"},
+		{Name: "second", Chart: "stable/second", ChartVersion: "1.0.0", Namespace: "agents", Values: "a: b", Enabled: true},
+	}
+
+	expected := []RunPostHooksWorkflowInputPostHook{
+		{
+			Name:  "org:first",
+			Param: pkgCluster.OrganizationPostHookParam{Chart: "stable/first", Namespace: "default", ReleaseName: "first"},
+		},
+		{
+			Name:  "org:second",
+			Param: pkgCluster.OrganizationPostHookParam{Chart: "stable/second", ChartVersion: "1.0.0", Namespace: "agents", ReleaseName: "second", Values: "a: b"},
+		},
+	}
+
+	assert.Equal(t, expected, buildOrganizationPostHookFunctions(postHooks))
+}
@@ -0,0 +1,160 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/cadence/client"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pipelineContext "github.com/banzaicloud/pipeline/internal/platform/context"
+)
+
+type postHookStatuses interface {
+	FindByCluster(clusterID uint) ([]intCluster.PostHookStatusModel, error)
+	MarkPending(clusterID uint, postHooks []intCluster.PostHookStatusModel) error
+	MarkRunning(clusterID uint, name string) error
+	MarkFinished(clusterID uint, name string, hookErr error) error
+}
+
+// PostHookManager exposes the execution state of cluster post hooks and re-runs selected ones.
+type PostHookManager struct {
+	statuses       postHookStatuses
+	workflowClient client.Client
+
+	logger logrus.FieldLogger
+}
+
+// NewPostHookManager returns a new PostHookManager instance.
+func NewPostHookManager(statuses postHookStatuses, workflowClient client.Client, logger logrus.FieldLogger) *PostHookManager {
+	return &PostHookManager{
+		statuses:       statuses,
+		workflowClient: workflowClient,
+		logger:         logger,
+	}
+}
+
+// GetStatuses returns the state of the post hooks last run on a cluster.
+func (m *PostHookManager) GetStatuses(ctx context.Context, commonCluster CommonCluster) ([]intCluster.PostHookStatusModel, error) {
+	return m.statuses.FindByCluster(commonCluster.GetID())
+}
+
+// ReRunPostHooks re-runs the named (or all) post hooks of a cluster with their original parameters.
+// When failedOnly is set, only the post hooks that failed the last time are re-run.
+func (m *PostHookManager) ReRunPostHooks(
+	ctx context.Context,
+	commonCluster CommonCluster,
+	names []string,
+	failedOnly bool,
+) ([]intCluster.PostHookStatusModel, error) {
+	logger := pipelineContext.LoggerWithCorrelationID(ctx, m.logger).WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"clusterID":    commonCluster.GetID(),
+		"cluster":      commonCluster.GetName(),
+	})
+
+	statuses, err := m.statuses.FindByCluster(commonCluster.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := selectPostHooks(statuses, names, failedOnly)
+	if err != nil {
+		return nil, errors.WithStack(&invalidError{err})
+	}
+
+	input := RunPostHooksWorkflowInput{
+		ClusterID: commonCluster.GetID(),
+	}
+
+	for _, status := range selected {
+		var param interface{}
+		if status.Param != "" {
+			if err := json.Unmarshal([]byte(status.Param), &param); err != nil {
+				return nil, emperror.WrapWith(err, "failed to decode posthook param", "postHook", status.Name)
+			}
+		}
+
+		input.PostHooks = append(input.PostHooks, RunPostHooksWorkflowInputPostHook{
+			Name:  status.Name,
+			Param: param,
+		})
+	}
+
+	workflowOptions := client.StartWorkflowOptions{
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: 2 * time.Hour, // TODO: lower timeout
+	}
+
+	exec, err := m.workflowClient.ExecuteWorkflow(ctx, workflowOptions, RunPostHooksWorkflowName, input)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "failed to start workflow", "workflowName", RunPostHooksWorkflowName)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"workflowName":  RunPostHooksWorkflowName,
+		"workflowID":    exec.GetID(),
+		"workflowRunID": exec.GetRunID(),
+	}).Info("workflow started successfully")
+
+	return selected, nil
+}
+
+// selectPostHooks returns the post hooks to be re-run in their original order.
+func selectPostHooks(statuses []intCluster.PostHookStatusModel, names []string, failedOnly bool) ([]intCluster.PostHookStatusModel, error) {
+	byName := make(map[string]bool, len(names))
+	for _, name := range names {
+		byName[name] = true
+	}
+
+	for _, name := range names {
+		found := false
+		for _, status := range statuses {
+			if status.Name == name {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, errors.Errorf("posthook %s has not been run on the cluster", name)
+		}
+	}
+
+	var selected []intCluster.PostHookStatusModel
+	for _, status := range statuses {
+		if len(byName) > 0 && !byName[status.Name] {
+			continue
+		}
+
+		if failedOnly && status.Status != intCluster.PostHookStatusFailed {
+			continue
+		}
+
+		selected = append(selected, status)
+	}
+
+	if len(selected) == 0 {
+		return nil, errors.New("there are no posthooks to re-run")
+	}
+
+	return selected, nil
+}
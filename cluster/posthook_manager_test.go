@@ -0,0 +1,70 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+func TestSelectPostHooks(t *testing.T) {
+	t.Parallel()
+
+	statuses := []intCluster.PostHookStatusModel{
+		{Name: "CreatePipelineNamespacePostHook", Status: intCluster.PostHookStatusSucceeded},
+		{Name: "InstallLogging", Status: intCluster.PostHookStatusFailed},
+		{Name: "InstallMonitoring", Status: intCluster.PostHookStatusPending},
+	}
+
+	tests := map[string]struct {
+		names      []string
+		failedOnly bool
+		expected   []string
+	}{
+		"all":               {nil, false, []string{"CreatePipelineNamespacePostHook", "InstallLogging", "InstallMonitoring"}},
+		"failed":            {nil, true, []string{"InstallLogging"}},
+		"named":             {[]string{"InstallMonitoring", "CreatePipelineNamespacePostHook"}, false, []string{"CreatePipelineNamespacePostHook", "InstallMonitoring"}},
+		"named and failed":  {[]string{"InstallLogging", "InstallMonitoring"}, true, []string{"InstallLogging"}},
+		"nothing to re-run": {[]string{"InstallMonitoring"}, true, nil},
+		"unknown posthook":  {[]string{"InstallKubernetesDashboardPostHook"}, false, nil},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			selected, err := selectPostHooks(statuses, test.names, test.failedOnly)
+			if test.expected == nil {
+				assert.Error(t, err)
+
+				return
+			}
+			require.NoError(t, err)
+
+			var names []string
+			for _, status := range selected {
+				names = append(names, status.Name)
+			}
+
+			assert.Equal(t, test.expected, names)
+		})
+	}
+}
@@ -0,0 +1,515 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/banzaicloud/pipeline/auth"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+)
+
+// RBAC role kinds
+const (
+	RBACRoleKindClusterRole = "ClusterRole"
+	RBACRoleKindRole        = "Role"
+)
+
+// rbacBindingLabel marks the Kubernetes role bindings managed by Pipeline, its value is the ID of the binding.
+const rbacBindingLabel = "pipeline.banzaicloud.io/rbac-binding"
+
+// RBACBindingRequest describes a request binding a Pipeline user or an identity provider group to a Kubernetes role.
+type RBACBindingRequest struct {
+	UserID    uint   `json:"userId,omitempty"`
+	Group     string `json:"group,omitempty"`
+	RoleKind  string `json:"roleKind" binding:"required"`
+	RoleName  string `json:"roleName" binding:"required"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RBACBinding describes a Pipeline user or an identity provider group bound to a Kubernetes role in a cluster.
+type RBACBinding struct {
+	ID        uint   `json:"id"`
+	UserID    uint   `json:"userId,omitempty"`
+	Group     string `json:"group,omitempty"`
+	RoleKind  string `json:"roleKind"`
+	RoleName  string `json:"roleName"`
+	Namespace string `json:"namespace,omitempty"`
+	CreatedBy uint   `json:"createdBy,omitempty"`
+}
+
+type clusterRBACBindings interface {
+	FindByCluster(clusterID uint) ([]intCluster.ClusterRBACBindingModel, error)
+	FindByUser(userID uint) ([]intCluster.ClusterRBACBindingModel, error)
+	FindOne(clusterID uint, id uint) (*intCluster.ClusterRBACBindingModel, error)
+	Save(binding *intCluster.ClusterRBACBindingModel) error
+	Delete(binding *intCluster.ClusterRBACBindingModel) error
+}
+
+type organizationMembers interface {
+	// GetMember returns a member of an organization or nil if the user is not a member.
+	GetMember(organizationID uint, userID uint) (*auth.User, error)
+}
+
+// OrganizationMembers looks up organization members in the database.
+type OrganizationMembers struct {
+	db *gorm.DB
+}
+
+// NewOrganizationMembers returns a new OrganizationMembers instance.
+func NewOrganizationMembers(db *gorm.DB) *OrganizationMembers {
+	return &OrganizationMembers{db: db}
+}
+
+// GetMember returns a member of an organization or nil if the user is not a member.
+func (m *OrganizationMembers) GetMember(organizationID uint, userID uint) (*auth.User, error) {
+	role, err := auth.GetUserRole(m.db, userID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if role == "" {
+		return nil, nil
+	}
+
+	var user auth.User
+
+	err = m.db.Where(auth.User{ID: userID}).First(&user).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch user", "userID", userID)
+	}
+
+	return &user, nil
+}
+
+type rbacClusterGetter interface {
+	GetClusterByIDOnly(ctx context.Context, clusterID uint) (CommonCluster, error)
+}
+
+// RBACBindingManager binds Pipeline users and identity provider groups to Kubernetes roles in clusters.
+// Bindings are reconciled as ClusterRoleBindings and RoleBindings with subjects matching
+// the email and groups claims of the OIDC tokens issued for the cluster.
+type RBACBindingManager struct {
+	bindings clusterRBACBindings
+	members  organizationMembers
+	clusters rbacClusterGetter
+
+	newClient func(kubeConfig []byte) (kubernetes.Interface, error)
+
+	logger logrus.FieldLogger
+}
+
+// NewRBACBindingManager returns a new RBACBindingManager instance.
+func NewRBACBindingManager(
+	bindings clusterRBACBindings,
+	members organizationMembers,
+	clusters rbacClusterGetter,
+	logger logrus.FieldLogger,
+) *RBACBindingManager {
+	return &RBACBindingManager{
+		bindings: bindings,
+		members:  members,
+		clusters: clusters,
+		newClient: func(kubeConfig []byte) (kubernetes.Interface, error) {
+			return k8sclient.NewClientFromKubeConfig(kubeConfig)
+		},
+		logger: logger,
+	}
+}
+
+// GetBindings returns the RBAC bindings of a cluster.
+func (m *RBACBindingManager) GetBindings(ctx context.Context, cluster CommonCluster) ([]RBACBinding, error) {
+	models, err := m.bindings.FindByCluster(cluster.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := make([]RBACBinding, 0, len(models))
+	for _, model := range models {
+		bindings = append(bindings, newRBACBinding(model))
+	}
+
+	return bindings, nil
+}
+
+// CreateBinding saves a new RBAC binding and reconciles the role bindings of the cluster.
+func (m *RBACBindingManager) CreateBinding(ctx context.Context, cluster CommonCluster, request RBACBindingRequest, createdBy uint) (*RBACBinding, error) {
+	if err := validateRBACBindingRequest(request); err != nil {
+		return nil, err
+	}
+
+	if request.UserID != 0 {
+		user, err := m.members.GetMember(cluster.GetOrganizationId(), request.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		if user == nil {
+			return nil, errors.WithStack(&invalidError{errors.Errorf("user %d is not a member of the organization", request.UserID)})
+		}
+	}
+
+	model := intCluster.ClusterRBACBindingModel{
+		ClusterID: cluster.GetID(),
+		UserID:    request.UserID,
+		Group:     request.Group,
+		RoleKind:  request.RoleKind,
+		RoleName:  request.RoleName,
+		Namespace: request.Namespace,
+		CreatedBy: createdBy,
+	}
+
+	if err := m.bindings.Save(&model); err != nil {
+		return nil, err
+	}
+
+	binding := newRBACBinding(model)
+
+	return &binding, m.Reconcile(ctx, cluster)
+}
+
+// DeleteBinding deletes an RBAC binding and reconciles the role bindings of the cluster.
+// It returns false if the binding does not exist.
+func (m *RBACBindingManager) DeleteBinding(ctx context.Context, cluster CommonCluster, id uint) (bool, error) {
+	model, err := m.bindings.FindOne(cluster.GetID(), id)
+	if err != nil {
+		return false, err
+	}
+
+	if model == nil {
+		return false, nil
+	}
+
+	if err := m.bindings.Delete(model); err != nil {
+		return true, err
+	}
+
+	return true, m.Reconcile(ctx, cluster)
+}
+
+// Reconcile makes the role bindings managed by Pipeline in the cluster match the stored RBAC bindings.
+// Users who are no longer members of the organization lose their bindings.
+func (m *RBACBindingManager) Reconcile(ctx context.Context, cluster CommonCluster) error {
+	logger := m.logger.WithFields(logrus.Fields{
+		"organization": cluster.GetOrganizationId(),
+		"cluster":      cluster.GetID(),
+	})
+
+	models, err := m.bindings.FindByCluster(cluster.GetID())
+	if err != nil {
+		return err
+	}
+
+	subjects := make(map[uint]rbacv1.Subject, len(models))
+	for _, model := range models {
+		subject, err := m.getSubject(cluster.GetOrganizationId(), model)
+		if err != nil {
+			return err
+		}
+
+		if subject == nil {
+			logger.WithField("binding", model.ID).Info("skipping rbac binding of a user without organization membership or email")
+			continue
+		}
+
+		subjects[model.ID] = *subject
+	}
+
+	clusterRoleBindings, roleBindings := desiredRBACBindings(models, subjects)
+
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return emperror.Wrap(err, "could not get k8s config")
+	}
+
+	client, err := m.newClient(kubeConfig)
+	if err != nil {
+		return err
+	}
+
+	errs := emperror.NewMultiErrorBuilder()
+
+	errs.Add(reconcileClusterRoleBindings(client, clusterRoleBindings))
+	errs.Add(reconcileRoleBindings(client, roleBindings))
+
+	return emperror.WrapWith(errs.ErrOrNil(), "could not reconcile rbac bindings", "clusterID", cluster.GetID())
+}
+
+// ReconcileMemberClusters reconciles the clusters of an organization a user has RBAC bindings in.
+// It is called when a user leaves the organization, so that the role bindings of ex-members are deleted.
+func (m *RBACBindingManager) ReconcileMemberClusters(organizationID uint, userID uint) {
+	logger := m.logger.WithFields(logrus.Fields{
+		"organization": organizationID,
+		"user":         userID,
+	})
+
+	models, err := m.bindings.FindByUser(userID)
+	if err != nil {
+		logger.WithError(err).Error("failed to reconcile rbac bindings of removed organization member")
+		return
+	}
+
+	ctx := context.Background()
+	reconciled := make(map[uint]bool)
+
+	for _, model := range models {
+		if reconciled[model.ClusterID] {
+			continue
+		}
+		reconciled[model.ClusterID] = true
+
+		cluster, err := m.clusters.GetClusterByIDOnly(ctx, model.ClusterID)
+		if err != nil {
+			logger.WithError(err).WithField("cluster", model.ClusterID).Error("failed to get cluster to reconcile rbac bindings")
+			continue
+		}
+
+		if cluster.GetOrganizationId() != organizationID {
+			continue
+		}
+
+		if err := m.Reconcile(ctx, cluster); err != nil {
+			logger.WithError(err).WithField("cluster", model.ClusterID).Error("failed to reconcile rbac bindings of removed organization member")
+		}
+	}
+}
+
+func (m *RBACBindingManager) getSubject(organizationID uint, model intCluster.ClusterRBACBindingModel) (*rbacv1.Subject, error) {
+	if model.Group != "" {
+		return &rbacv1.Subject{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: model.Group}, nil
+	}
+
+	user, err := m.members.GetMember(organizationID, model.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user == nil || user.Email == "" {
+		return nil, nil
+	}
+
+	return &rbacv1.Subject{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: user.Email}, nil
+}
+
+func validateRBACBindingRequest(request RBACBindingRequest) error {
+	var err error
+
+	switch {
+	case (request.UserID == 0) == (request.Group == ""):
+		err = errors.New("either userId or group must be specified")
+
+	case request.RoleKind != RBACRoleKindClusterRole && request.RoleKind != RBACRoleKindRole:
+		err = errors.Errorf("roleKind must be %s or %s", RBACRoleKindClusterRole, RBACRoleKindRole)
+
+	case request.RoleKind == RBACRoleKindRole && request.Namespace == "":
+		err = errors.New("namespace is required for binding a Role")
+
+	case request.Namespace != "" && len(validation.IsDNS1123Label(request.Namespace)) > 0:
+		err = errors.Errorf("invalid namespace: %s", request.Namespace)
+	}
+
+	if err != nil {
+		return errors.WithStack(&invalidError{err})
+	}
+
+	return nil
+}
+
+func newRBACBinding(model intCluster.ClusterRBACBindingModel) RBACBinding {
+	return RBACBinding{
+		ID:        model.ID,
+		UserID:    model.UserID,
+		Group:     model.Group,
+		RoleKind:  model.RoleKind,
+		RoleName:  model.RoleName,
+		Namespace: model.Namespace,
+		CreatedBy: model.CreatedBy,
+	}
+}
+
+func rbacBindingName(id uint) string {
+	return fmt.Sprintf("pipeline-rbac-binding-%d", id)
+}
+
+// desiredRBACBindings returns the role bindings the cluster should have.
+// Bindings without namespace are cluster wide, the others are namespaced (they may still refer to a ClusterRole).
+func desiredRBACBindings(models []intCluster.ClusterRBACBindingModel, subjects map[uint]rbacv1.Subject) ([]rbacv1.ClusterRoleBinding, []rbacv1.RoleBinding) {
+	var clusterRoleBindings []rbacv1.ClusterRoleBinding
+	var roleBindings []rbacv1.RoleBinding
+
+	for _, model := range models {
+		subject, ok := subjects[model.ID]
+		if !ok {
+			continue
+		}
+
+		meta := metav1.ObjectMeta{
+			Name:      rbacBindingName(model.ID),
+			Namespace: model.Namespace,
+			Labels: map[string]string{
+				rbacBindingLabel: strconv.FormatUint(uint64(model.ID), 10),
+			},
+		}
+
+		roleRef := rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     model.RoleKind,
+			Name:     model.RoleName,
+		}
+
+		if model.Namespace == "" {
+			clusterRoleBindings = append(clusterRoleBindings, rbacv1.ClusterRoleBinding{
+				ObjectMeta: meta,
+				Subjects:   []rbacv1.Subject{subject},
+				RoleRef:    roleRef,
+			})
+		} else {
+			roleBindings = append(roleBindings, rbacv1.RoleBinding{
+				ObjectMeta: meta,
+				Subjects:   []rbacv1.Subject{subject},
+				RoleRef:    roleRef,
+			})
+		}
+	}
+
+	return clusterRoleBindings, roleBindings
+}
+
+func reconcileClusterRoleBindings(client kubernetes.Interface, desired []rbacv1.ClusterRoleBinding) error {
+	api := client.RbacV1().ClusterRoleBindings()
+
+	existing, err := api.List(metav1.ListOptions{LabelSelector: rbacBindingLabel})
+	if err != nil {
+		return emperror.Wrap(err, "could not list cluster role bindings")
+	}
+
+	current := make(map[string]rbacv1.ClusterRoleBinding, len(existing.Items))
+	for _, binding := range existing.Items {
+		current[binding.Name] = binding
+	}
+
+	errs := emperror.NewMultiErrorBuilder()
+
+	for _, binding := range desired {
+		binding := binding
+
+		currentBinding, ok := current[binding.Name]
+		delete(current, binding.Name)
+
+		switch {
+		case !ok:
+			_, err = api.Create(&binding)
+
+		// role references are immutable
+		case currentBinding.RoleRef != binding.RoleRef:
+			err = api.Delete(binding.Name, &metav1.DeleteOptions{})
+			if err == nil {
+				_, err = api.Create(&binding)
+			}
+
+		case !reflect.DeepEqual(currentBinding.Subjects, binding.Subjects):
+			currentBinding.Subjects = binding.Subjects
+			_, err = api.Update(&currentBinding)
+
+		default:
+			err = nil
+		}
+
+		errs.Add(emperror.WrapWith(err, "could not apply cluster role binding", "binding", binding.Name))
+	}
+
+	for name := range current {
+		err := api.Delete(name, &metav1.DeleteOptions{})
+		if k8sErrors.IsNotFound(err) {
+			err = nil
+		}
+
+		errs.Add(emperror.WrapWith(err, "could not delete cluster role binding", "binding", name))
+	}
+
+	return errs.ErrOrNil()
+}
+
+func reconcileRoleBindings(client kubernetes.Interface, desired []rbacv1.RoleBinding) error {
+	existing, err := client.RbacV1().RoleBindings(metav1.NamespaceAll).List(metav1.ListOptions{LabelSelector: rbacBindingLabel})
+	if err != nil {
+		return emperror.Wrap(err, "could not list role bindings")
+	}
+
+	key := func(binding rbacv1.RoleBinding) string {
+		return binding.Namespace + "/" + binding.Name
+	}
+
+	current := make(map[string]rbacv1.RoleBinding, len(existing.Items))
+	for _, binding := range existing.Items {
+		current[key(binding)] = binding
+	}
+
+	errs := emperror.NewMultiErrorBuilder()
+
+	for _, binding := range desired {
+		binding := binding
+		api := client.RbacV1().RoleBindings(binding.Namespace)
+
+		currentBinding, ok := current[key(binding)]
+		delete(current, key(binding))
+
+		switch {
+		case !ok:
+			_, err = api.Create(&binding)
+
+		// role references are immutable
+		case currentBinding.RoleRef != binding.RoleRef:
+			err = api.Delete(binding.Name, &metav1.DeleteOptions{})
+			if err == nil {
+				_, err = api.Create(&binding)
+			}
+
+		case !reflect.DeepEqual(currentBinding.Subjects, binding.Subjects):
+			currentBinding.Subjects = binding.Subjects
+			_, err = api.Update(&currentBinding)
+
+		default:
+			err = nil
+		}
+
+		errs.Add(emperror.WrapWith(err, "could not apply role binding", "binding", key(binding)))
+	}
+
+	for _, binding := range current {
+		err := client.RbacV1().RoleBindings(binding.Namespace).Delete(binding.Name, &metav1.DeleteOptions{})
+		if k8sErrors.IsNotFound(err) {
+			err = nil
+		}
+
+		errs.Add(emperror.WrapWith(err, "could not delete role binding", "binding", key(binding)))
+	}
+
+	return errs.ErrOrNil()
+}
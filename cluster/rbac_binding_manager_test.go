@@ -0,0 +1,87 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+func TestValidateRBACBindingRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		request RBACBindingRequest
+		valid   bool
+	}{
+		"user cluster role":     {RBACBindingRequest{UserID: 1, RoleKind: RBACRoleKindClusterRole, RoleName: "view"}, true},
+		"group namespaced role": {RBACBindingRequest{Group: "devs", RoleKind: RBACRoleKindRole, RoleName: "deployer", Namespace: "apps"}, true},
+		"no subject":            {RBACBindingRequest{RoleKind: RBACRoleKindClusterRole, RoleName: "view"}, false},
+		"both subjects":         {RBACBindingRequest{UserID: 1, Group: "devs", RoleKind: RBACRoleKindClusterRole, RoleName: "view"}, false},
+		"unknown role kind":     {RBACBindingRequest{UserID: 1, RoleKind: "Group", RoleName: "view"}, false},
+		"role without ns":       {RBACBindingRequest{UserID: 1, RoleKind: RBACRoleKindRole, RoleName: "deployer"}, false},
+		"invalid namespace":     {RBACBindingRequest{UserID: 1, RoleKind: RBACRoleKindClusterRole, RoleName: "view", Namespace: "Apps"}, false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateRBACBindingRequest(test.request)
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				_, ok := errors.Cause(err).(*invalidError)
+				assert.True(t, ok)
+			}
+		})
+	}
+}
+
+func TestDesiredRBACBindings(t *testing.T) {
+	t.Parallel()
+
+	models := []intCluster.ClusterRBACBindingModel{
+		{ID: 1, UserID: 1, RoleKind: RBACRoleKindClusterRole, RoleName: "view"},
+		{ID: 2, Group: "devs", RoleKind: RBACRoleKindClusterRole, RoleName: "edit", Namespace: "apps"},
+		{ID: 3, UserID: 2, RoleKind: RBACRoleKindClusterRole, RoleName: "admin"},
+	}
+
+	subjects := map[uint]rbacv1.Subject{
+		1: {Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "john@example.com"},
+		2: {Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "devs"},
+	}
+
+	clusterRoleBindings, roleBindings := desiredRBACBindings(models, subjects)
+
+	if assert.Len(t, clusterRoleBindings, 1) {
+		assert.Equal(t, "pipeline-rbac-binding-1", clusterRoleBindings[0].Name)
+		assert.Equal(t, "view", clusterRoleBindings[0].RoleRef.Name)
+		assert.Equal(t, []rbacv1.Subject{subjects[1]}, clusterRoleBindings[0].Subjects)
+	}
+
+	if assert.Len(t, roleBindings, 1) {
+		assert.Equal(t, "apps", roleBindings[0].Namespace)
+		assert.Equal(t, rbacv1.GroupKind, roleBindings[0].Subjects[0].Kind)
+		assert.Equal(t, "2", roleBindings[0].Labels[rbacBindingLabel])
+	}
+}
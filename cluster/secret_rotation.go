@@ -0,0 +1,132 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/ghodss/yaml"
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+
+	"github.com/banzaicloud/pipeline/auth"
+	pipConfig "github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/helm"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// RotatedSecretConsumer describes the deployments of a cluster using the credentials of a rotated secret.
+type RotatedSecretConsumer struct {
+	ClusterID   uint `json:"clusterId"`
+	Autoscaler  bool `json:"autoscaler"`
+	ExternalDNS bool `json:"externalDns"`
+	Ark         bool `json:"ark"`
+}
+
+type rotatedSecretStore interface {
+	Get(organizationID uint, secretID string) (*secret.SecretItemResponse, error)
+	Update(organizationID uint, secretID string, request *secret.CreateSecretRequest) error
+}
+
+// updateAutoscalerCredentials upgrades the cluster autoscaler, if it is deployed, with the current cluster secret.
+func updateAutoscalerCredentials(cluster CommonCluster) error {
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return emperror.Wrap(err, "failed to get k8s config")
+	}
+
+	if !isAutoscalerDeployedAlready(releaseName, kubeConfig) {
+		return nil
+	}
+
+	return emperror.Wrap(DeployClusterAutoscaler(cluster), "failed to upgrade cluster autoscaler")
+}
+
+// updateExternalDNSCredentials upgrades external-dns, if it is deployed, with the current Route53 credentials.
+func updateExternalDNSCredentials(cluster CommonCluster, route53Secret *secret.SecretItemResponse) error {
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return emperror.Wrap(err, "failed to get k8s config")
+	}
+
+	name := externalDNSReleaseName
+	deployments, err := helm.ListDeployments(&name, "", kubeConfig)
+	if err != nil {
+		return emperror.Wrap(err, "failed to list deployments")
+	}
+
+	var deployed bool
+	if deployments != nil {
+		for _, release := range deployments.Releases {
+			if release.Name == externalDNSReleaseName {
+				deployed = true
+				break
+			}
+		}
+	}
+
+	if !deployed {
+		return nil
+	}
+
+	values, err := yaml.Marshal(map[string]interface{}{
+		"aws": map[string]string{
+			"secretKey": route53Secret.Values[pkgSecret.AwsSecretAccessKey],
+			"accessKey": route53Secret.Values[pkgSecret.AwsAccessKeyId],
+			"region":    route53Secret.Values[pkgSecret.AwsRegion],
+		},
+	})
+	if err != nil {
+		return emperror.Wrap(err, "failed to marshal external-dns values")
+	}
+
+	org, err := auth.GetOrganizationById(cluster.GetOrganizationId())
+	if err != nil {
+		return emperror.Wrap(err, "failed to get organization")
+	}
+
+	_, err = helm.UpgradeDeployment(
+		externalDNSReleaseName,
+		externalDNSChart,
+		viper.GetString(pipConfig.DNSExternalDnsChartVersion),
+		nil,
+		values,
+		true,
+		kubeConfig,
+		helm.GenerateHelmRepoEnv(org.Name),
+	)
+
+	return emperror.Wrap(err, "failed to upgrade external-dns")
+}
+
+// updateArkCredentials redeploys Ark, if it is deployed, with the current cluster and bucket secrets.
+func updateArkCredentials(cluster CommonCluster, db *gorm.DB, logger logrus.FieldLogger) error {
+	org, err := auth.GetOrganizationById(cluster.GetOrganizationId())
+	if err != nil {
+		return emperror.Wrap(err, "failed to get organization")
+	}
+
+	deployments := ark.NewARKService(org, cluster, db, logger).GetDeploymentsService()
+
+	if _, err := deployments.GetActiveDeployment(); gorm.IsRecordNotFoundError(err) {
+		return nil
+	} else if err != nil {
+		return emperror.Wrap(err, "failed to get ark deployment")
+	}
+
+	return emperror.Wrap(deployments.Redeploy(), "failed to redeploy ark")
+}
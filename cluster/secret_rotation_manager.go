@@ -0,0 +1,202 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/cadence/client"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/dns/route53"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	pipelineContext "github.com/banzaicloud/pipeline/internal/platform/context"
+	"github.com/banzaicloud/pipeline/secret"
+	"github.com/banzaicloud/pipeline/secret/rotate"
+)
+
+// SecretRotation describes a started rotation of the credentials stored in a secret.
+type SecretRotation struct {
+	WorkflowID      string                  `json:"workflowId"`
+	RunID           string                  `json:"runId"`
+	InstallationIDs []uint                  `json:"installationIds"`
+	Consumers       []RotatedSecretConsumer `json:"consumers"`
+}
+
+// SecretRotationManager replaces the cloud credentials stored in secrets and updates the clusters using them.
+type SecretRotationManager struct {
+	clusters       *Manager
+	installations  secretInstallations
+	db             *gorm.DB
+	workflowClient client.Client
+
+	logger logrus.FieldLogger
+}
+
+// NewSecretRotationManager returns a new SecretRotationManager instance.
+func NewSecretRotationManager(
+	clusters *Manager,
+	installations secretInstallations,
+	db *gorm.DB,
+	workflowClient client.Client,
+	logger logrus.FieldLogger,
+) *SecretRotationManager {
+	return &SecretRotationManager{
+		clusters:       clusters,
+		installations:  installations,
+		db:             db,
+		workflowClient: workflowClient,
+		logger:         logger,
+	}
+}
+
+// RotateSecret starts replacing the credential stored in a secret in the background.
+func (m *SecretRotationManager) RotateSecret(ctx context.Context, organizationID uint, secretID string, updatedBy string) (*SecretRotation, error) {
+	logger := pipelineContext.LoggerWithCorrelationID(ctx, m.logger).WithFields(logrus.Fields{
+		"organization": organizationID,
+		"secret":       secretID,
+	})
+
+	secretItem, err := secret.Store.Get(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := secret.HasForbiddenTag(secretItem.Tags); err != nil {
+		return nil, errors.WithStack(&invalidError{err})
+	}
+
+	if !rotate.IsSupported(secretItem.Type) {
+		return nil, errors.WithStack(&invalidError{errors.Errorf("credentials of %s secrets cannot be rotated", secretItem.Type)})
+	}
+
+	installations, err := m.installations.FindBySecret(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	consumers, err := m.getConsumers(ctx, organizationID, secretItem)
+	if err != nil {
+		return nil, err
+	}
+
+	input := RotateSecretWorkflowInput{
+		OrganizationID: organizationID,
+		SecretID:       secretID,
+		UpdatedBy:      updatedBy,
+		Consumers:      consumers,
+	}
+
+	for _, installation := range installations {
+		input.InstallationIDs = append(input.InstallationIDs, installation.ID)
+	}
+
+	if len(input.InstallationIDs) > 0 {
+		if err := m.installations.MarkPending(input.InstallationIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	workflowOptions := client.StartWorkflowOptions{
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: time.Hour,
+	}
+
+	exec, err := m.workflowClient.ExecuteWorkflow(ctx, workflowOptions, RotateSecretWorkflowName, input)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "failed to start workflow", "workflowName", RotateSecretWorkflowName)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"workflowName":  RotateSecretWorkflowName,
+		"workflowID":    exec.GetID(),
+		"workflowRunID": exec.GetRunID(),
+		"installations": len(input.InstallationIDs),
+		"consumers":     len(input.Consumers),
+	}).Info("workflow started successfully")
+
+	return &SecretRotation{
+		WorkflowID:      exec.GetID(),
+		RunID:           exec.GetRunID(),
+		InstallationIDs: input.InstallationIDs,
+		Consumers:       input.Consumers,
+	}, nil
+}
+
+// getConsumers returns the clusters with deployments using the credentials of a secret.
+func (m *SecretRotationManager) getConsumers(ctx context.Context, organizationID uint, secretItem *secret.SecretItemResponse) ([]RotatedSecretConsumer, error) {
+	consumers := make(map[uint]*RotatedSecretConsumer)
+	consumer := func(clusterID uint) *RotatedSecretConsumer {
+		if _, ok := consumers[clusterID]; !ok {
+			consumers[clusterID] = &RotatedSecretConsumer{ClusterID: clusterID}
+		}
+
+		return consumers[clusterID]
+	}
+
+	clusters, err := m.clusters.GetClustersBySecretID(ctx, organizationID, secretItem.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cluster := range clusters {
+		consumer(cluster.GetID()).Autoscaler = true
+		consumer(cluster.GetID()).Ark = true
+	}
+
+	if secretItem.Name == route53.IAMUserAccessKeySecretName {
+		clusters, err := m.clusters.GetClusters(ctx, organizationID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cluster := range clusters {
+			consumer(cluster.GetID()).ExternalDNS = true
+		}
+	}
+
+	org, err := auth.GetOrganizationById(organizationID)
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to get organization")
+	}
+
+	buckets, err := ark.NewBucketsRepository(org, m.db, m.logger).FindBySecretID(secretItem.ID)
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to get backup buckets")
+	}
+
+	for _, bucket := range buckets {
+		if bucket.Deployment.ClusterID != 0 {
+			consumer(bucket.Deployment.ClusterID).Ark = true
+		}
+	}
+
+	result := make([]RotatedSecretConsumer, 0, len(consumers))
+	for _, c := range consumers {
+		result = append(result, *c)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ClusterID < result[j].ClusterID
+	})
+
+	return result, nil
+}
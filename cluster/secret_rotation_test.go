@@ -0,0 +1,94 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+type inmemoryRotatedSecretStore struct {
+	secrets map[string]*secret.SecretItemResponse
+}
+
+func (s *inmemoryRotatedSecretStore) Get(organizationID uint, secretID string) (*secret.SecretItemResponse, error) {
+	secretItem, ok := s.secrets[secretID]
+	if !ok {
+		return nil, secret.ErrSecretNotExists
+	}
+
+	return secretItem, nil
+}
+
+func (s *inmemoryRotatedSecretStore) Update(organizationID uint, secretID string, request *secret.CreateSecretRequest) error {
+	return nil
+}
+
+func TestRevokeSecretKeyActivity(t *testing.T) {
+	t.Parallel()
+
+	store := &inmemoryRotatedSecretStore{
+		secrets: map[string]*secret.SecretItemResponse{
+			"amazon": {
+				ID:   "amazon",
+				Type: pkgCluster.Amazon,
+				Values: map[string]string{
+					pkgSecret.AwsAccessKeyId:     "AKIACURRENT",
+					pkgSecret.AwsSecretAccessKey: "secret",
+				},
+			},
+			"google": {
+				ID:   "google",
+				Type: pkgCluster.Google,
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		input RevokeSecretKeyActivityInput
+		err   string
+	}{
+		"current key": {
+			input: RevokeSecretKeyActivityInput{OrganizationID: 1, SecretID: "amazon", KeyID: "AKIACURRENT"},
+			err:   "refusing to revoke the key stored in the secret",
+		},
+		"unsupported type": {
+			input: RevokeSecretKeyActivityInput{OrganizationID: 1, SecretID: "google", KeyID: "key"},
+			err:   "credentials of google secrets cannot be rotated",
+		},
+		"missing secret": {
+			input: RevokeSecretKeyActivityInput{OrganizationID: 1, SecretID: "missing", KeyID: "key"},
+			err:   secret.ErrSecretNotExists.Error(),
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewRevokeSecretKeyActivity(store).Execute(context.Background(), test.input)
+
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
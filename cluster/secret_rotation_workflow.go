@@ -0,0 +1,346 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/workflow"
+
+	"github.com/banzaicloud/pipeline/dns/route53"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/secret"
+	"github.com/banzaicloud/pipeline/secret/rotate"
+	"github.com/banzaicloud/pipeline/secret/verify"
+)
+
+const RotateSecretWorkflowName = "rotate-secret"
+
+type RotateSecretWorkflowInput struct {
+	OrganizationID  uint
+	SecretID        string
+	UpdatedBy       string
+	InstallationIDs []uint
+	Consumers       []RotatedSecretConsumer
+}
+
+// RotateSecretWorkflow replaces the credential stored in a secret with a new one and re-applies it wherever the secret is used.
+// The previous credential is only revoked when every consumer has been updated, otherwise both remain valid.
+func RotateSecretWorkflow(ctx workflow.Context, input RotateSecretWorkflowInput) error {
+	// creating a key is not retried, so that a failure never leaves several new keys behind
+	rotateOptions := workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    10 * time.Minute,
+		WaitForCancellation:    true,
+	}
+
+	var rotation RotateSecretKeyActivityOutput
+	{
+		activityInput := RotateSecretKeyActivityInput{
+			OrganizationID: input.OrganizationID,
+			SecretID:       input.SecretID,
+			UpdatedBy:      input.UpdatedBy,
+		}
+
+		err := workflow.ExecuteActivity(workflow.WithActivityOptions(ctx, rotateOptions), RotateSecretKeyActivityName, activityInput).Get(ctx, &rotation)
+		if err != nil {
+			return err
+		}
+	}
+
+	retryPolicy := &cadence.RetryPolicy{
+		InitialInterval:    time.Second * 3,
+		BackoffCoefficient: 2,
+		ExpirationInterval: time.Minute * 5,
+		MaximumAttempts:    5,
+	}
+	ao := workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    10 * time.Minute,
+		WaitForCancellation:    true,
+		RetryPolicy:            retryPolicy,
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	futures := make([]workflow.Future, 0, len(input.InstallationIDs)+len(input.Consumers))
+
+	for _, id := range input.InstallationIDs {
+		activityInput := SyncSecretInstallationActivityInput{
+			InstallationID: id,
+		}
+
+		futures = append(futures, workflow.ExecuteActivity(ctx, SyncSecretInstallationActivityName, activityInput))
+	}
+
+	for _, consumer := range input.Consumers {
+		activityInput := UpdateRotatedSecretConsumerActivityInput{
+			OrganizationID: input.OrganizationID,
+			SecretID:       input.SecretID,
+			Consumer:       consumer,
+		}
+
+		futures = append(futures, workflow.ExecuteActivity(ctx, UpdateRotatedSecretConsumerActivityName, activityInput))
+	}
+
+	var failed int
+	for _, future := range futures {
+		if err := future.Get(ctx, nil); err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return errors.Errorf("failed to update %d of %d consumers of the rotated secret, the previous key is kept", failed, len(futures))
+	}
+
+	if rotation.PreviousKeyID == "" {
+		workflow.GetLogger(ctx).Info("the previous key is unknown, it has to be revoked manually")
+
+		return nil
+	}
+
+	activityInput := RevokeSecretKeyActivityInput{
+		OrganizationID: input.OrganizationID,
+		SecretID:       input.SecretID,
+		KeyID:          rotation.PreviousKeyID,
+	}
+
+	return workflow.ExecuteActivity(ctx, RevokeSecretKeyActivityName, activityInput).Get(ctx, nil)
+}
+
+const RotateSecretKeyActivityName = "rotate-secret-key"
+
+// rotatedKeyVerifyAttempts and rotatedKeyVerifyInterval give new keys time to propagate before they are used.
+const (
+	rotatedKeyVerifyAttempts = 12
+	rotatedKeyVerifyInterval = 10 * time.Second
+)
+
+type RotateSecretKeyActivityInput struct {
+	OrganizationID uint
+	SecretID       string
+	UpdatedBy      string
+}
+
+// RotateSecretKeyActivityOutput identifies the keys involved in a rotation, the keys themselves never leave the secret store.
+type RotateSecretKeyActivityOutput struct {
+	PreviousKeyID string
+	KeyID         string
+}
+
+// RotateSecretKeyActivity creates a new key for the identity of a secret, verifies it and stores it in the secret.
+type RotateSecretKeyActivity struct {
+	secrets rotatedSecretStore
+}
+
+func NewRotateSecretKeyActivity(secrets rotatedSecretStore) *RotateSecretKeyActivity {
+	return &RotateSecretKeyActivity{
+		secrets: secrets,
+	}
+}
+
+func (a *RotateSecretKeyActivity) Execute(ctx context.Context, input RotateSecretKeyActivityInput) (*RotateSecretKeyActivityOutput, error) {
+	logger := activity.GetLogger(ctx).Sugar().With(
+		"organization", input.OrganizationID,
+		"secret", input.SecretID,
+	)
+
+	secretItem, err := a.secrets.Get(input.OrganizationID, input.SecretID)
+	if err != nil {
+		return nil, err
+	}
+
+	rotator := rotate.NewRotator(secretItem.Type)
+	if rotator == nil {
+		return nil, errors.Errorf("credentials of %s secrets cannot be rotated", secretItem.Type)
+	}
+
+	output := &RotateSecretKeyActivityOutput{
+		PreviousKeyID: rotator.CurrentKey(secretItem.Values, secretItem.Tags),
+	}
+
+	values, tags, keyID, err := rotator.CreateKey(ctx, secretItem.Values, secretItem.Tags)
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to create new key")
+	}
+
+	output.KeyID = keyID
+
+	logger.Infow("new key created", "keyId", keyID)
+
+	// the new key is revoked if it cannot be put in place, the previous one stays in use
+	revokeNewKey := func() {
+		if err := rotator.RevokeKey(ctx, secretItem.Values, keyID); err != nil {
+			logger.Errorw("failed to revoke new key", "keyId", keyID, "error", err.Error())
+		}
+	}
+
+	if err := verifyRotatedKey(ctx, secretItem.Type, values); err != nil {
+		revokeNewKey()
+
+		return nil, emperror.Wrap(err, "failed to verify new key")
+	}
+
+	err = a.secrets.Update(input.OrganizationID, input.SecretID, &secret.CreateSecretRequest{
+		Name:      secretItem.Name,
+		Type:      secretItem.Type,
+		Values:    values,
+		Tags:      tags,
+		Version:   &secretItem.Version,
+		UpdatedBy: input.UpdatedBy,
+	})
+	if err != nil {
+		revokeNewKey()
+
+		return nil, emperror.Wrap(err, "failed to store new key")
+	}
+
+	return output, nil
+}
+
+func verifyRotatedKey(ctx context.Context, secretType string, values map[string]string) error {
+	verifier := verify.NewVerifier(secretType, values)
+
+	for attempt := 1; ; attempt++ {
+		err := verifier.VerifySecret()
+		if err == nil || attempt == rotatedKeyVerifyAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(rotatedKeyVerifyInterval):
+		}
+	}
+}
+
+const RevokeSecretKeyActivityName = "revoke-secret-key"
+
+type RevokeSecretKeyActivityInput struct {
+	OrganizationID uint
+	SecretID       string
+	KeyID          string
+}
+
+// RevokeSecretKeyActivity revokes a previous key of the identity of a secret.
+type RevokeSecretKeyActivity struct {
+	secrets rotatedSecretStore
+}
+
+func NewRevokeSecretKeyActivity(secrets rotatedSecretStore) *RevokeSecretKeyActivity {
+	return &RevokeSecretKeyActivity{
+		secrets: secrets,
+	}
+}
+
+func (a *RevokeSecretKeyActivity) Execute(ctx context.Context, input RevokeSecretKeyActivityInput) error {
+	secretItem, err := a.secrets.Get(input.OrganizationID, input.SecretID)
+	if err != nil {
+		return err
+	}
+
+	rotator := rotate.NewRotator(secretItem.Type)
+	if rotator == nil {
+		return errors.Errorf("credentials of %s secrets cannot be rotated", secretItem.Type)
+	}
+
+	if rotator.CurrentKey(secretItem.Values, secretItem.Tags) == input.KeyID {
+		return errors.New("refusing to revoke the key stored in the secret")
+	}
+
+	return rotator.RevokeKey(ctx, secretItem.Values, input.KeyID)
+}
+
+const UpdateRotatedSecretConsumerActivityName = "update-rotated-secret-consumer"
+
+type UpdateRotatedSecretConsumerActivityInput struct {
+	OrganizationID uint
+	SecretID       string
+	Consumer       RotatedSecretConsumer
+}
+
+// UpdateRotatedSecretConsumerActivity re-applies the credentials of a rotated secret to the deployments of a cluster.
+type UpdateRotatedSecretConsumerActivity struct {
+	manager *Manager
+	secrets rotatedSecretStore
+	db      *gorm.DB
+	logger  logrus.FieldLogger
+}
+
+func NewUpdateRotatedSecretConsumerActivity(manager *Manager, secrets rotatedSecretStore, db *gorm.DB, logger logrus.FieldLogger) *UpdateRotatedSecretConsumerActivity {
+	return &UpdateRotatedSecretConsumerActivity{
+		manager: manager,
+		secrets: secrets,
+		db:      db,
+		logger:  logger,
+	}
+}
+
+func (a *UpdateRotatedSecretConsumerActivity) Execute(ctx context.Context, input UpdateRotatedSecretConsumerActivityInput) error {
+	info := activity.GetInfo(ctx)
+	logger := activity.GetLogger(ctx).Sugar().With(
+		"clusterID", input.Consumer.ClusterID,
+		"secret", input.SecretID,
+		"workflowID", info.WorkflowExecution.ID,
+		"workflowRunID", info.WorkflowExecution.RunID,
+	)
+
+	cluster, err := a.manager.GetClusterByIDOnly(ctx, input.Consumer.ClusterID)
+	if intCluster.IsClusterNotFoundError(err) {
+		logger.Info("cluster not found, skipping")
+
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if input.Consumer.Autoscaler {
+		if err := updateAutoscalerCredentials(cluster); err != nil {
+			return err
+		}
+	}
+
+	if input.Consumer.ExternalDNS {
+		route53Secret, err := a.secrets.Get(input.OrganizationID, input.SecretID)
+		if err != nil {
+			return err
+		}
+
+		if route53Secret.Name != route53.IAMUserAccessKeySecretName {
+			return errors.Errorf("secret %s is not the Route53 secret", input.SecretID)
+		}
+
+		if err := updateExternalDNSCredentials(cluster, route53Secret); err != nil {
+			return err
+		}
+	}
+
+	if input.Consumer.Ark {
+		if err := updateArkCredentials(cluster, a.db, a.logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
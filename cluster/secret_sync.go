@@ -0,0 +1,230 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/banzaicloud/pipeline/config"
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	secretTypes "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// secretSyncedAtAnnotation is set on the pod templates of workloads consuming a synced secret,
+// so that they are rolled out with the new values.
+const secretSyncedAtAnnotation = "pipeline.banzaicloud.io/secret-synced-at"
+
+type secretInstallations interface {
+	FindBySecret(organizationID uint, secretID string) ([]intCluster.SecretInstallationModel, error)
+	FindOne(id uint) (*intCluster.SecretInstallationModel, error)
+	Save(installation *intCluster.SecretInstallationModel) error
+	Delete(id uint) error
+	MarkPending(ids []uint) error
+	MarkRunning(id uint) error
+	MarkFinished(id uint, version int, syncErr error) error
+}
+
+// recordSecretInstallations records the secrets installed into a cluster as they are by a query.
+// Failing to record an installation does not fail the installation itself.
+func recordSecretInstallations(cc CommonCluster, query *secretTypes.ListSecretsQuery, namespace string) {
+	secrets, err := secret.Store.List(cc.GetOrganizationId(), &secretTypes.ListSecretsQuery{
+		Type: query.Type,
+		Tags: query.Tags,
+		IDs:  query.IDs,
+	})
+	if err != nil {
+		log.Errorf("failed to record secret installations: %s", err.Error())
+		return
+	}
+
+	installations := intCluster.NewSecretInstallations(config.DB())
+
+	for _, s := range secrets {
+		err := installations.Save(&intCluster.SecretInstallationModel{
+			OrganizationID: cc.GetOrganizationId(),
+			SecretID:       s.ID,
+			ClusterID:      cc.GetID(),
+			Namespace:      namespace,
+			Name:           s.Name,
+			SyncedVersion:  s.Version,
+		})
+		if err != nil {
+			log.Errorf("failed to record secret installation: %s", err.Error())
+		}
+	}
+}
+
+// recordSecretInstallation records a secret installed into a cluster under a name with an optional key mapping.
+// Failing to record an installation does not fail the installation itself.
+func recordSecretInstallation(cc CommonCluster, secretName string, req InstallSecretRequest, merge bool) {
+	// installations of literal values only are not synced
+	if req.SourceSecretName == "" {
+		return
+	}
+
+	logger := log.WithFields(logrus.Fields{
+		"organization": cc.GetOrganizationId(),
+		"clusterID":    cc.GetID(),
+		"secret":       req.SourceSecretName,
+	})
+
+	secretItem, err := secret.Store.GetByName(cc.GetOrganizationId(), req.SourceSecretName)
+	if err != nil {
+		logger.Errorf("failed to record secret installation: %s", err.Error())
+		return
+	}
+
+	var spec string
+	if len(req.Spec) > 0 {
+		rawSpec, err := json.Marshal(req.Spec)
+		if err != nil {
+			logger.Errorf("failed to record secret installation: %s", err.Error())
+			return
+		}
+
+		spec = string(rawSpec)
+	}
+
+	err = intCluster.NewSecretInstallations(config.DB()).Save(&intCluster.SecretInstallationModel{
+		OrganizationID: cc.GetOrganizationId(),
+		SecretID:       secretItem.ID,
+		ClusterID:      cc.GetID(),
+		Namespace:      req.Namespace,
+		Name:           secretName,
+		Spec:           spec,
+		Merge:          merge,
+		SyncedVersion:  secretItem.Version,
+	})
+	if err != nil {
+		logger.Errorf("failed to record secret installation: %s", err.Error())
+	}
+}
+
+// newSyncSecretRequest rebuilds the request of a recorded secret installation.
+func newSyncSecretRequest(installation intCluster.SecretInstallationModel, sourceSecretName string) (InstallSecretRequest, error) {
+	req := InstallSecretRequest{
+		SourceSecretName: sourceSecretName,
+		Namespace:        installation.Namespace,
+		Spec:             map[string]InstallSecretRequestSpecItem{},
+		Update:           true,
+	}
+
+	if installation.Spec != "" {
+		if err := json.Unmarshal([]byte(installation.Spec), &req.Spec); err != nil {
+			return req, emperror.WrapWith(err, "failed to decode secret installation spec", "installationID", installation.ID)
+		}
+	}
+
+	return req, nil
+}
+
+// restartSecretConsumers rolls out the workloads of a namespace that use a secret,
+// so that values consumed as environment variables are refreshed as well.
+func restartSecretConsumers(client kubernetes.Interface, namespace string, secretName string, syncedAt time.Time) error {
+	patch := []byte(fmt.Sprintf(
+		`{"spec":{"template":{"metadata":{"annotations":{%q:%q}}}}}`,
+		secretSyncedAtAnnotation,
+		syncedAt.UTC().Format(time.RFC3339),
+	))
+
+	deployments, err := client.AppsV1().Deployments(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return emperror.Wrap(err, "failed to list deployments")
+	}
+
+	for _, deployment := range deployments.Items {
+		if podSpecUsesSecret(deployment.Spec.Template.Spec, secretName) {
+			_, err := client.AppsV1().Deployments(namespace).Patch(deployment.Name, types.StrategicMergePatchType, patch)
+			if err != nil {
+				return emperror.WrapWith(err, "failed to restart deployment", "deployment", deployment.Name)
+			}
+		}
+	}
+
+	statefulSets, err := client.AppsV1().StatefulSets(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return emperror.Wrap(err, "failed to list stateful sets")
+	}
+
+	for _, statefulSet := range statefulSets.Items {
+		if podSpecUsesSecret(statefulSet.Spec.Template.Spec, secretName) {
+			_, err := client.AppsV1().StatefulSets(namespace).Patch(statefulSet.Name, types.StrategicMergePatchType, patch)
+			if err != nil {
+				return emperror.WrapWith(err, "failed to restart stateful set", "statefulSet", statefulSet.Name)
+			}
+		}
+	}
+
+	daemonSets, err := client.AppsV1().DaemonSets(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return emperror.Wrap(err, "failed to list daemon sets")
+	}
+
+	for _, daemonSet := range daemonSets.Items {
+		if podSpecUsesSecret(daemonSet.Spec.Template.Spec, secretName) {
+			_, err := client.AppsV1().DaemonSets(namespace).Patch(daemonSet.Name, types.StrategicMergePatchType, patch)
+			if err != nil {
+				return emperror.WrapWith(err, "failed to restart daemon set", "daemonSet", daemonSet.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// podSpecUsesSecret checks whether a pod mounts a secret or reads it into environment variables.
+func podSpecUsesSecret(spec corev1.PodSpec, secretName string) bool {
+	for _, volume := range spec.Volumes {
+		if volume.Secret != nil && volume.Secret.SecretName == secretName {
+			return true
+		}
+
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.Secret != nil && source.Secret.Name == secretName {
+					return true
+				}
+			}
+		}
+	}
+
+	containers := append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...)
+
+	for _, container := range containers {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil && envFrom.SecretRef.Name == secretName {
+				return true
+			}
+		}
+
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil && env.ValueFrom.SecretKeyRef.Name == secretName {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,98 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/cadence/client"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pipelineContext "github.com/banzaicloud/pipeline/internal/platform/context"
+)
+
+// SecretSyncManager keeps the copies of secrets installed into clusters up to date.
+type SecretSyncManager struct {
+	installations  secretInstallations
+	workflowClient client.Client
+
+	logger logrus.FieldLogger
+}
+
+// NewSecretSyncManager returns a new SecretSyncManager instance.
+func NewSecretSyncManager(installations secretInstallations, workflowClient client.Client, logger logrus.FieldLogger) *SecretSyncManager {
+	return &SecretSyncManager{
+		installations:  installations,
+		workflowClient: workflowClient,
+		logger:         logger,
+	}
+}
+
+// GetInstallations returns the clusters a secret is installed into along with the state of their last sync.
+func (m *SecretSyncManager) GetInstallations(ctx context.Context, organizationID uint, secretID string) ([]intCluster.SecretInstallationModel, error) {
+	return m.installations.FindBySecret(organizationID, secretID)
+}
+
+// SyncSecret starts updating every installed copy of a secret in the background.
+func (m *SecretSyncManager) SyncSecret(ctx context.Context, organizationID uint, secretID string) ([]intCluster.SecretInstallationModel, error) {
+	logger := pipelineContext.LoggerWithCorrelationID(ctx, m.logger).WithFields(logrus.Fields{
+		"organization": organizationID,
+		"secret":       secretID,
+	})
+
+	installations, err := m.installations.FindBySecret(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(installations) == 0 {
+		return installations, nil
+	}
+
+	input := SyncSecretInstallationsWorkflowInput{
+		OrganizationID: organizationID,
+		SecretID:       secretID,
+	}
+
+	for _, installation := range installations {
+		input.InstallationIDs = append(input.InstallationIDs, installation.ID)
+	}
+
+	if err := m.installations.MarkPending(input.InstallationIDs); err != nil {
+		return nil, err
+	}
+
+	workflowOptions := client.StartWorkflowOptions{
+		TaskList:                     "pipeline",
+		ExecutionStartToCloseTimeout: time.Hour,
+	}
+
+	exec, err := m.workflowClient.ExecuteWorkflow(ctx, workflowOptions, SyncSecretInstallationsWorkflowName, input)
+	if err != nil {
+		return nil, emperror.WrapWith(err, "failed to start workflow", "workflowName", SyncSecretInstallationsWorkflowName)
+	}
+
+	logger.WithFields(logrus.Fields{
+		"workflowName":  SyncSecretInstallationsWorkflowName,
+		"workflowID":    exec.GetID(),
+		"workflowRunID": exec.GetRunID(),
+		"installations": len(installations),
+	}).Info("workflow started successfully")
+
+	return m.installations.FindBySecret(organizationID, secretID)
+}
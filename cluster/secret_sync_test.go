@@ -0,0 +1,95 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+)
+
+func TestPodSpecUsesSecret(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		spec     corev1.PodSpec
+		expected bool
+	}{
+		"volume": {
+			spec: corev1.PodSpec{Volumes: []corev1.Volume{
+				{Name: "creds", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "registry"}}},
+			}},
+			expected: true,
+		},
+		"projected volume": {
+			spec: corev1.PodSpec{Volumes: []corev1.Volume{
+				{Name: "creds", VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{{Secret: &corev1.SecretProjection{LocalObjectReference: corev1.LocalObjectReference{Name: "registry"}}}},
+				}}},
+			}},
+			expected: true,
+		},
+		"env var": {
+			spec: corev1.PodSpec{Containers: []corev1.Container{{Env: []corev1.EnvVar{
+				{Name: "PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "registry"},
+					Key:                  "password",
+				}}},
+			}}}},
+			expected: true,
+		},
+		"init container env from": {
+			spec: corev1.PodSpec{InitContainers: []corev1.Container{{EnvFrom: []corev1.EnvFromSource{
+				{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "registry"}}},
+			}}}},
+			expected: true,
+		},
+		"other secret": {
+			spec: corev1.PodSpec{Volumes: []corev1.Volume{
+				{Name: "creds", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "database"}}},
+			}},
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, podSpecUsesSecret(test.spec, "registry"))
+		})
+	}
+}
+
+func TestNewSyncSecretRequest(t *testing.T) {
+	t.Parallel()
+
+	req, err := newSyncSecretRequest(intCluster.SecretInstallationModel{
+		Namespace: "apps",
+		Spec:      `{"DB_PASSWORD":{"Source":"password","SourceMap":null,"Value":""}}`,
+	}, "database")
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "database", req.SourceSecretName)
+		assert.Equal(t, "apps", req.Namespace)
+		assert.True(t, req.Update)
+		assert.Equal(t, InstallSecretRequestSpecItem{Source: "password"}, req.Spec["DB_PASSWORD"])
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"go.uber.org/cadence"
+	"go.uber.org/cadence/activity"
+	"go.uber.org/cadence/workflow"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+const SyncSecretInstallationsWorkflowName = "sync-secret-installations"
+
+type SyncSecretInstallationsWorkflowInput struct {
+	OrganizationID  uint
+	SecretID        string
+	InstallationIDs []uint
+}
+
+// SyncSecretInstallationsWorkflow updates the installed copies of a secret in every cluster in parallel.
+// The result of each installation is recorded separately, one failing cluster does not stop the others.
+func SyncSecretInstallationsWorkflow(ctx workflow.Context, input SyncSecretInstallationsWorkflowInput) error {
+	retryPolicy := &cadence.RetryPolicy{
+		InitialInterval:    time.Second * 3,
+		BackoffCoefficient: 2,
+		ExpirationInterval: time.Minute * 5,
+		MaximumAttempts:    5,
+	}
+	ao := workflow.ActivityOptions{
+		ScheduleToStartTimeout: 10 * time.Minute,
+		StartToCloseTimeout:    10 * time.Minute,
+		WaitForCancellation:    true,
+		RetryPolicy:            retryPolicy,
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	futures := make([]workflow.Future, 0, len(input.InstallationIDs))
+
+	for _, id := range input.InstallationIDs {
+		activityInput := SyncSecretInstallationActivityInput{
+			InstallationID: id,
+		}
+
+		futures = append(futures, workflow.ExecuteActivity(ctx, SyncSecretInstallationActivityName, activityInput))
+	}
+
+	var failed int
+	for _, future := range futures {
+		if err := future.Get(ctx, nil); err != nil {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return errors.Errorf("failed to sync %d of %d secret installations", failed, len(futures))
+	}
+
+	return nil
+}
+
+const SyncSecretInstallationActivityName = "sync-secret-installation"
+
+type SyncSecretInstallationActivityInput struct {
+	InstallationID uint
+}
+
+// SyncSecretInstallationActivity updates an installed copy of a secret and restarts the workloads using it.
+type SyncSecretInstallationActivity struct {
+	manager       *Manager
+	installations secretInstallations
+}
+
+func NewSyncSecretInstallationActivity(manager *Manager, installations secretInstallations) *SyncSecretInstallationActivity {
+	return &SyncSecretInstallationActivity{
+		manager:       manager,
+		installations: installations,
+	}
+}
+
+func (a *SyncSecretInstallationActivity) Execute(ctx context.Context, input SyncSecretInstallationActivityInput) (err error) {
+	installation, err := a.installations.FindOne(input.InstallationID)
+	if err != nil || installation == nil {
+		return err
+	}
+
+	info := activity.GetInfo(ctx)
+	logger := activity.GetLogger(ctx).Sugar().With(
+		"clusterID", installation.ClusterID,
+		"secret", installation.SecretID,
+		"namespace", installation.Namespace,
+		"name", installation.Name,
+		"workflowID", info.WorkflowExecution.ID,
+		"workflowRunID", info.WorkflowExecution.RunID,
+	)
+
+	cluster, err := a.manager.GetClusterByIDOnly(ctx, installation.ClusterID)
+	if intCluster.IsClusterNotFoundError(err) {
+		logger.Info("cluster not found, forgetting secret installation")
+
+		return a.installations.Delete(installation.ID)
+	} else if err != nil {
+		return err
+	}
+
+	secretItem, err := secret.Store.Get(installation.OrganizationID, installation.SecretID)
+	if err == secret.ErrSecretNotExists {
+		logger.Info("secret not found, forgetting secret installation")
+
+		return a.installations.Delete(installation.ID)
+	} else if err != nil {
+		return err
+	}
+
+	if statusErr := a.installations.MarkRunning(installation.ID); statusErr != nil {
+		logger.Errorw("failed to record secret installation status", "error", statusErr.Error())
+	}
+
+	defer func() {
+		if statusErr := a.installations.MarkFinished(installation.ID, secretItem.Version, err); statusErr != nil {
+			logger.Errorw("failed to record secret installation status", "error", statusErr.Error())
+		}
+	}()
+
+	return a.sync(cluster, *installation, secretItem)
+}
+
+func (a *SyncSecretInstallationActivity) sync(cluster CommonCluster, installation intCluster.SecretInstallationModel, secretItem *secret.SecretItemResponse) error {
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return emperror.Wrap(err, "failed to get k8s config")
+	}
+
+	client, err := k8sclient.NewClientFromKubeConfig(kubeConfig)
+	if err != nil {
+		return emperror.Wrap(err, "failed to create kubernetes client")
+	}
+
+	req, err := newSyncSecretRequest(installation, secretItem.Name)
+	if err != nil {
+		return err
+	}
+
+	if installation.Merge {
+		_, err = MergeSecretByK8SConfig(kubeConfig, installation.OrganizationID, installation.Name, req)
+	} else {
+		_, err = InstallSecretByK8SConfig(kubeConfig, installation.OrganizationID, installation.Name, req)
+	}
+	if err != nil {
+		return emperror.Wrap(err, "failed to update kubernetes secret")
+	}
+
+	return restartSecretConsumers(client, installation.Namespace, installation.Name, time.Now())
+}
@@ -0,0 +1,329 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/sirupsen/logrus"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/banzaicloud/pipeline/internal/ark"
+	"github.com/banzaicloud/pipeline/internal/clustergroup/deployment"
+	"github.com/banzaicloud/pipeline/secret"
+	"github.com/banzaicloud/pipeline/spotguide"
+)
+
+// SecretUsages lists everything referencing a secret.
+type SecretUsages struct {
+	Clusters                []SecretUsageCluster                `json:"clusters"`
+	BackupBuckets           []SecretUsageBackupBucket           `json:"backupBuckets"`
+	ArkDeployments          []SecretUsageArkDeployment          `json:"arkDeployments"`
+	SpotguideRepos          []string                            `json:"spotguideRepos"`
+	ClusterGroupDeployments []SecretUsageClusterGroupDeployment `json:"clusterGroupDeployments"`
+	Installations           []SecretUsageInstallation           `json:"installations"`
+}
+
+// SecretUsageCluster describes a cluster created with a secret.
+type SecretUsageCluster struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Cloud        string `json:"cloud"`
+	Distribution string `json:"distribution"`
+}
+
+// SecretUsageBackupBucket describes a backup bucket accessed with a secret.
+type SecretUsageBackupBucket struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Cloud string `json:"cloud"`
+}
+
+// SecretUsageArkDeployment describes an Ark deployment using a secret either as its cluster or its bucket secret.
+type SecretUsageArkDeployment struct {
+	ID        uint   `json:"id"`
+	ClusterID uint   `json:"clusterId"`
+	BucketID  uint   `json:"bucketId"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// SecretUsageClusterGroupDeployment describes a cluster group deployment referencing a secret in its values.
+type SecretUsageClusterGroupDeployment struct {
+	ClusterGroupID uint   `json:"clusterGroupId"`
+	ReleaseName    string `json:"releaseName"`
+	Chart          string `json:"chart"`
+}
+
+// SecretUsageInstallation describes a copy of a secret installed into a cluster.
+type SecretUsageInstallation struct {
+	ClusterID uint   `json:"clusterId"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// InUse returns true if anything references the secret.
+func (u *SecretUsages) InUse() bool {
+	return len(u.Clusters) > 0 ||
+		len(u.BackupBuckets) > 0 ||
+		len(u.ArkDeployments) > 0 ||
+		len(u.SpotguideRepos) > 0 ||
+		len(u.ClusterGroupDeployments) > 0 ||
+		len(u.Installations) > 0
+}
+
+// String summarizes the usages, eg. "2 clusters, 1 installation".
+func (u *SecretUsages) String() string {
+	counts := []struct {
+		count    int
+		singular string
+		plural   string
+	}{
+		{len(u.Clusters), "cluster", "clusters"},
+		{len(u.BackupBuckets), "backup bucket", "backup buckets"},
+		{len(u.ArkDeployments), "ark deployment", "ark deployments"},
+		{len(u.SpotguideRepos), "spotguide repository", "spotguide repositories"},
+		{len(u.ClusterGroupDeployments), "cluster group deployment", "cluster group deployments"},
+		{len(u.Installations), "installation", "installations"},
+	}
+
+	var parts []string
+	for _, c := range counts {
+		switch {
+		case c.count == 1:
+			parts = append(parts, fmt.Sprintf("1 %s", c.singular))
+		case c.count > 1:
+			parts = append(parts, fmt.Sprintf("%d %s", c.count, c.plural))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+type clusterGroupDeployments interface {
+	FindAllByOrganization(organizationName string) ([]*deployment.ClusterGroupDeploymentModel, error)
+}
+
+// SecretUsageManager finds the resources referencing secrets.
+type SecretUsageManager struct {
+	clusters                *Manager
+	installations           secretInstallations
+	clusterGroupDeployments clusterGroupDeployments
+	db                      *gorm.DB
+
+	logger logrus.FieldLogger
+}
+
+// NewSecretUsageManager returns a new SecretUsageManager instance.
+func NewSecretUsageManager(
+	clusters *Manager,
+	installations secretInstallations,
+	clusterGroupDeployments clusterGroupDeployments,
+	db *gorm.DB,
+	logger logrus.FieldLogger,
+) *SecretUsageManager {
+	return &SecretUsageManager{
+		clusters:                clusters,
+		installations:           installations,
+		clusterGroupDeployments: clusterGroupDeployments,
+		db:                      db,
+		logger:                  logger,
+	}
+}
+
+// GetUsages returns everything referencing a secret.
+func (m *SecretUsageManager) GetUsages(ctx context.Context, organizationID uint, secretID string) (*SecretUsages, error) {
+	secretItem, err := secret.Store.Get(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := auth.GetOrganizationById(organizationID)
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to get organization")
+	}
+
+	usages := &SecretUsages{
+		Clusters:                []SecretUsageCluster{},
+		BackupBuckets:           []SecretUsageBackupBucket{},
+		ArkDeployments:          []SecretUsageArkDeployment{},
+		SpotguideRepos:          []string{},
+		ClusterGroupDeployments: []SecretUsageClusterGroupDeployment{},
+		Installations:           []SecretUsageInstallation{},
+	}
+
+	clusters, err := m.clusters.GetClustersBySecretID(ctx, organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cluster := range clusters {
+		usages.Clusters = append(usages.Clusters, SecretUsageCluster{
+			ID:           cluster.GetID(),
+			Name:         cluster.GetName(),
+			Cloud:        cluster.GetCloud(),
+			Distribution: cluster.GetDistribution(),
+		})
+
+		arkDeployment, err := ark.NewDeploymentsRepository(org, cluster, m.db, m.logger).FindFirst()
+		if gorm.IsRecordNotFoundError(err) {
+			continue
+		} else if err != nil {
+			return nil, emperror.Wrap(err, "failed to get ark deployment")
+		}
+
+		usages.ArkDeployments = append(usages.ArkDeployments, newSecretUsageArkDeployment(arkDeployment))
+	}
+
+	buckets, err := ark.NewBucketsRepository(org, m.db, m.logger).FindBySecretID(secretID)
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to get backup buckets")
+	}
+
+	for _, bucket := range buckets {
+		usages.BackupBuckets = append(usages.BackupBuckets, SecretUsageBackupBucket{
+			ID:    bucket.ID,
+			Name:  bucket.BucketName,
+			Cloud: bucket.Cloud,
+		})
+
+		if bucket.Deployment.ID != 0 && !hasArkDeployment(usages.ArkDeployments, bucket.Deployment.ID) {
+			usages.ArkDeployments = append(usages.ArkDeployments, newSecretUsageArkDeployment(&bucket.Deployment))
+		}
+	}
+
+	for _, tag := range secretItem.Tags {
+		if strings.HasPrefix(tag, spotguide.SecretRepoTagPrefix) {
+			usages.SpotguideRepos = append(usages.SpotguideRepos, strings.TrimPrefix(tag, spotguide.SecretRepoTagPrefix))
+		}
+	}
+
+	cgDeployments, err := m.clusterGroupDeployments.FindAllByOrganization(org.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cgDeployment := range cgDeployments {
+		if clusterGroupDeploymentReferences(cgDeployment, secretItem.ID, secretItem.Name) {
+			usages.ClusterGroupDeployments = append(usages.ClusterGroupDeployments, SecretUsageClusterGroupDeployment{
+				ClusterGroupID: cgDeployment.ClusterGroupID,
+				ReleaseName:    cgDeployment.DeploymentReleaseName,
+				Chart:          cgDeployment.DeploymentName,
+			})
+		}
+	}
+
+	installations, err := m.installations.FindBySecret(organizationID, secretID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, installation := range installations {
+		usages.Installations = append(usages.Installations, SecretUsageInstallation{
+			ClusterID: installation.ClusterID,
+			Namespace: installation.Namespace,
+			Name:      installation.Name,
+		})
+	}
+
+	sort.Slice(usages.ArkDeployments, func(i, j int) bool {
+		return usages.ArkDeployments[i].ID < usages.ArkDeployments[j].ID
+	})
+
+	return usages, nil
+}
+
+func newSecretUsageArkDeployment(arkDeployment *ark.ClusterBackupDeploymentsModel) SecretUsageArkDeployment {
+	return SecretUsageArkDeployment{
+		ID:        arkDeployment.ID,
+		ClusterID: arkDeployment.ClusterID,
+		BucketID:  arkDeployment.BucketID,
+		Name:      arkDeployment.Name,
+		Namespace: arkDeployment.Namespace,
+	}
+}
+
+func hasArkDeployment(arkDeployments []SecretUsageArkDeployment, id uint) bool {
+	for _, arkDeployment := range arkDeployments {
+		if arkDeployment.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clusterGroupDeploymentReferences returns true if any value of a cluster group deployment, including its cluster specific overrides,
+// equals one of the given references.
+func clusterGroupDeploymentReferences(cgDeployment *deployment.ClusterGroupDeploymentModel, refs ...string) bool {
+	if valuesReference(cgDeployment.Values, refs...) {
+		return true
+	}
+
+	for _, target := range cgDeployment.TargetClusters {
+		if valuesReference(target.Values, refs...) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// valuesReference returns true if any string in a JSON encoded values document equals one of the given references.
+func valuesReference(rawValues []byte, refs ...string) bool {
+	if len(rawValues) == 0 {
+		return false
+	}
+
+	var values interface{}
+	if err := json.Unmarshal(rawValues, &values); err != nil {
+		return false
+	}
+
+	return valueReferences(values, refs)
+}
+
+func valueReferences(value interface{}, refs []string) bool {
+	switch v := value.(type) {
+	case string:
+		for _, ref := range refs {
+			if v == ref {
+				return true
+			}
+		}
+
+	case map[string]interface{}:
+		for _, item := range v {
+			if valueReferences(item, refs) {
+				return true
+			}
+		}
+
+	case []interface{}:
+		for _, item := range v {
+			if valueReferences(item, refs) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/internal/clustergroup/deployment"
+)
+
+func TestClusterGroupDeploymentReferences(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		deployment *deployment.ClusterGroupDeploymentModel
+		expected   bool
+	}{
+		"nested value": {
+			deployment: &deployment.ClusterGroupDeploymentModel{
+				Values: []byte(`{"auth":{"existingSecret":"mysql-password"}}`),
+			},
+			expected: true,
+		},
+		"list item": {
+			deployment: &deployment.ClusterGroupDeploymentModel{
+				Values: []byte(`{"imagePullSecrets":["registry","mysql-password"]}`),
+			},
+			expected: true,
+		},
+		"target cluster override": {
+			deployment: &deployment.ClusterGroupDeploymentModel{
+				Values: []byte(`{"replicas":2}`),
+				TargetClusters: []*deployment.TargetCluster{
+					{Values: []byte(`{"secretId":"0a1b2c3d"}`)},
+				},
+			},
+			expected: true,
+		},
+		"substring only": {
+			deployment: &deployment.ClusterGroupDeploymentModel{
+				Values: []byte(`{"auth":{"existingSecret":"mysql-password-old"}}`),
+			},
+			expected: false,
+		},
+		"key only": {
+			deployment: &deployment.ClusterGroupDeploymentModel{
+				Values: []byte(`{"mysql-password":true}`),
+			},
+			expected: false,
+		},
+		"invalid values": {
+			deployment: &deployment.ClusterGroupDeploymentModel{
+				Values: []byte(`mysql-password`),
+			},
+			expected: false,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, clusterGroupDeploymentReferences(test.deployment, "0a1b2c3d", "mysql-password"))
+		})
+	}
+}
+
+func TestSecretUsages_String(t *testing.T) {
+	t.Parallel()
+
+	usages := &SecretUsages{
+		Clusters:       []SecretUsageCluster{{ID: 1}, {ID: 2}},
+		SpotguideRepos: []string{"banzaicloud/spotguide-mysql"},
+		Installations:  []SecretUsageInstallation{{ClusterID: 1}},
+	}
+
+	assert.True(t, usages.InUse())
+	assert.Equal(t, "2 clusters, 1 spotguide repository, 1 installation", usages.String())
+	assert.False(t, (&SecretUsages{}).InUse())
+}
@@ -39,7 +39,14 @@ func InstallSecrets(cc CommonCluster, query *secretTypes.ListSecretsQuery, names
 		return nil, err
 	}
 
-	return InstallSecretsByK8SConfig(kubeConfig, cc.GetOrganizationId(), query, namespace)
+	secretSources, err := InstallSecretsByK8SConfig(kubeConfig, cc.GetOrganizationId(), query, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	recordSecretInstallations(cc, query, namespace)
+
+	return secretSources, nil
 }
 
 // InstallSecretsByK8SConfig is the same as InstallSecrets but use this if you already have a K8S config at hand.
@@ -156,7 +163,14 @@ func InstallSecret(cc CommonCluster, secretName string, req InstallSecretRequest
 		return nil, errors.Wrap(err, "failed to get k8s config")
 	}
 
-	return InstallSecretByK8SConfig(kubeConfig, cc.GetOrganizationId(), secretName, req)
+	sourceMeta, err := InstallSecretByK8SConfig(kubeConfig, cc.GetOrganizationId(), secretName, req)
+	if err != nil {
+		return nil, err
+	}
+
+	recordSecretInstallation(cc, secretName, req, false)
+
+	return sourceMeta, nil
 }
 
 // InstallSecretByK8SConfig is the same as InstallSecret but use this if you already have a K8S config at hand.
@@ -230,7 +244,14 @@ func MergeSecret(cc CommonCluster, secretName string, req InstallSecretRequest)
 		return nil, errors.Wrap(err, "failed to get k8s config")
 	}
 
-	return MergeSecretByK8SConfig(kubeConfig, cc.GetOrganizationId(), secretName, req)
+	sourceMeta, err := MergeSecretByK8SConfig(kubeConfig, cc.GetOrganizationId(), secretName, req)
+	if err != nil {
+		return nil, err
+	}
+
+	recordSecretInstallation(cc, secretName, req, true)
+
+	return sourceMeta, nil
 }
 
 // MergeSecretByK8SConfig is the same as MergeSecret but use this if you already have a K8S config at hand.
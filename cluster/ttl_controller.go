@@ -40,6 +40,12 @@ type TTLController struct {
 	// rate limited re-queues on errors
 	queue workqueue.RateLimitingInterface
 
+	// ttlHistory is used to record expiry warnings so that they are issued only once
+	ttlHistory ttlHistory
+
+	// warningLeadTime is how long before deleting a cluster a warning is issued (zero disables warnings)
+	warningLeadTime time.Duration
+
 	logger       logrus.FieldLogger
 	errorHandler emperror.Handler
 }
@@ -75,13 +81,22 @@ func (c *TTLController) Stop() {
 }
 
 // NewTTLController instantiates a new cluster TTL controller
-func NewTTLController(manager *Manager, clusterEvents clusterEventsSubscriber, logger logrus.FieldLogger, errorHandler emperror.Handler) *TTLController {
+func NewTTLController(
+	manager *Manager,
+	clusterEvents clusterEventsSubscriber,
+	ttlHistory ttlHistory,
+	warningLeadTime time.Duration,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *TTLController {
 	return &TTLController{
-		manager:       manager,
-		clusterEvents: clusterEvents,
-		queue:         workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ttl-controller"),
-		logger:        logger,
-		errorHandler:  errorHandler,
+		manager:         manager,
+		clusterEvents:   clusterEvents,
+		queue:           workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ttl-controller"),
+		ttlHistory:      ttlHistory,
+		warningLeadTime: warningLeadTime,
+		logger:          logger,
+		errorHandler:    errorHandler,
 	}
 }
 
@@ -179,6 +194,17 @@ func (c *TTLController) handleCluster(clusterID uint) error {
 		}
 
 	} else {
+		if clusterStartedAt != nil {
+			expiresAt := clusterStartedAt.Add(ttl)
+
+			if c.isExpiryWarningDue(expiresAt, time.Now()) {
+				err = c.warnClusterExpiring(cluster, expiresAt, log)
+				if err != nil {
+					return emperror.WrapWith(err, "failed to warn about cluster expiry", "clusterID", clusterID)
+				}
+			}
+		}
+
 		// schedule for later processing
 		log.Debug("cluster has not reached end of life yet, schedule it for re-check")
 		c.queue.AddAfter(clusterID, time.Duration(5*time.Minute))
@@ -187,6 +213,48 @@ func (c *TTLController) handleCluster(clusterID uint) error {
 	return nil
 }
 
+// isExpiryWarningDue returns true if the cluster expires within the warning lead time
+func (c *TTLController) isExpiryWarningDue(expiresAt time.Time, now time.Time) bool {
+	if c.warningLeadTime <= 0 {
+		return false
+	}
+
+	return !now.Before(expiresAt.Add(-c.warningLeadTime))
+}
+
+// warnClusterExpiring emits a cluster expiring event unless it has already been emitted for the same expiry
+func (c *TTLController) warnClusterExpiring(cluster CommonCluster, expiresAt time.Time, log logrus.FieldLogger) error {
+	lastWarning, err := c.ttlHistory.FindLatestByAction(cluster.GetID(), intCluster.TTLActionWarn)
+	if err != nil {
+		return err
+	}
+
+	// the TTL may have been extended since the last warning
+	if lastWarning != nil && lastWarning.ExpiresAt != nil && lastWarning.ExpiresAt.Unix() == expiresAt.Unix() {
+		return nil
+	}
+
+	log.WithField("expires_at", expiresAt).Warn("cluster is about to reach end of life")
+
+	ttlMinutes := uint(cluster.GetTTL() / time.Minute)
+
+	err = c.ttlHistory.Record(&intCluster.TTLHistoryModel{
+		ClusterID:      cluster.GetID(),
+		ClusterName:    cluster.GetName(),
+		Action:         intCluster.TTLActionWarn,
+		FromTTLMinutes: ttlMinutes,
+		ToTTLMinutes:   ttlMinutes,
+		ExpiresAt:      &expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	c.manager.events.ClusterExpiring(cluster.GetOrganizationId(), cluster.GetID(), cluster.GetName(), expiresAt)
+
+	return nil
+}
+
 // hasTTL returns true if the cluster has a TTL set
 func (c *TTLController) hasTTL(clusterDetail *pkgCluster.GetClusterStatusResponse) bool {
 	return clusterDetail.TtlMinutes > 0
@@ -213,6 +281,10 @@ func (c *TTLController) isClusterEndOfLife(clusterStarTime *time.Time, ttl time.
 // getClusterStartTime returns the time when cluster status changed from creating -> running|warning
 // if the timestamp when the status changed to running/warning than returns nil
 func (c *TTLController) getClusterStartTime(clusterDetail *pkgCluster.GetClusterStatusResponse) *time.Time {
+	return getClusterStartTime(clusterDetail)
+}
+
+func getClusterStartTime(clusterDetail *pkgCluster.GetClusterStatusResponse) *time.Time {
 	if clusterDetail == nil {
 		return nil
 	}
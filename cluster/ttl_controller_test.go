@@ -51,7 +51,7 @@ var (
 )
 
 func TestTtlController_isClusterEndOfLife(t *testing.T) {
-	controller := NewTTLController(nil, nil, nil, nil)
+	controller := NewTTLController(nil, nil, nil, 0, nil, nil)
 
 	testCases := []struct {
 		name            string
@@ -76,7 +76,7 @@ func TestTtlController_isClusterEndOfLife(t *testing.T) {
 }
 
 func TestTtlController_getClusterStartTime(t *testing.T) {
-	controller := NewTTLController(nil, nil, nil, nil)
+	controller := NewTTLController(nil, nil, nil, 0, nil, nil)
 
 	testCases := []struct {
 		name          string
@@ -99,3 +99,31 @@ func TestTtlController_getClusterStartTime(t *testing.T) {
 		})
 	}
 }
+
+func TestTtlController_isExpiryWarningDue(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name            string
+		warningLeadTime time.Duration
+		expiresAt       time.Time
+		expected        bool
+	}{
+		{"warnings disabled", 0, now.Add(time.Minute), false},
+		{"expiry beyond lead time", time.Hour, now.Add(2 * time.Hour), false},
+		{"expiry within lead time", time.Hour, now.Add(30 * time.Minute), true},
+		{"expiry at lead time", time.Hour, now.Add(time.Hour), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			controller := NewTTLController(nil, nil, nil, tc.warningLeadTime, nil, nil)
+
+			actual := controller.isExpiryWarningDue(tc.expiresAt, now)
+
+			if actual != tc.expected {
+				t.Errorf("isExpiryWarningDue expected return %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
+	pipelineContext "github.com/banzaicloud/pipeline/internal/platform/context"
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+type ttlHistory interface {
+	Record(entry *intCluster.TTLHistoryModel) error
+	FindByCluster(clusterID uint) ([]intCluster.TTLHistoryModel, error)
+	FindLatestByAction(clusterID uint, action string) (*intCluster.TTLHistoryModel, error)
+}
+
+// TTLStatus describes the TTL of a cluster.
+type TTLStatus struct {
+	TTL       time.Duration
+	StartedAt *time.Time
+	ExpiresAt *time.Time
+
+	History []intCluster.TTLHistoryModel
+}
+
+// TTLManager changes the TTL of running clusters and keeps record of who changed it.
+type TTLManager struct {
+	history ttlHistory
+	events  clusterEvents
+
+	logger logrus.FieldLogger
+}
+
+// NewTTLManager returns a new TTLManager instance.
+func NewTTLManager(history ttlHistory, events clusterEvents, logger logrus.FieldLogger) *TTLManager {
+	return &TTLManager{
+		history: history,
+		events:  events,
+		logger:  logger,
+	}
+}
+
+// GetTTL returns the TTL of a cluster along with its TTL history.
+func (m *TTLManager) GetTTL(ctx context.Context, commonCluster CommonCluster) (*TTLStatus, error) {
+	clusterDetail, err := commonCluster.GetStatus()
+	if err != nil {
+		return nil, emperror.WrapWith(err, "failed to retrieve cluster details", "clusterID", commonCluster.GetID())
+	}
+
+	history, err := m.history.FindByCluster(commonCluster.GetID())
+	if err != nil {
+		return nil, err
+	}
+
+	status := newTTLStatus(clusterDetail)
+	status.History = history
+
+	return status, nil
+}
+
+// SetTTL sets the TTL of a running cluster. Zero TTL clears it.
+func (m *TTLManager) SetTTL(ctx context.Context, commonCluster CommonCluster, ttl time.Duration, userID uint) (*TTLStatus, error) {
+	if ttl < 0 {
+		return nil, errors.WithStack(&invalidError{errors.New("TTL must not be negative")})
+	}
+
+	action := intCluster.TTLActionSet
+	if ttl == 0 {
+		action = intCluster.TTLActionClear
+	}
+
+	return m.changeTTL(ctx, commonCluster, userID, action, func(time.Duration) (time.Duration, error) {
+		return ttl, nil
+	})
+}
+
+// ExtendTTL postpones the deletion of a running cluster that has a TTL set.
+func (m *TTLManager) ExtendTTL(ctx context.Context, commonCluster CommonCluster, extension time.Duration, userID uint) (*TTLStatus, error) {
+	if extension < time.Minute {
+		return nil, errors.WithStack(&invalidError{errors.New("TTL extension must be at least one minute")})
+	}
+
+	return m.changeTTL(ctx, commonCluster, userID, intCluster.TTLActionExtend, func(current time.Duration) (time.Duration, error) {
+		if current == 0 {
+			return 0, errors.New("cluster has no TTL set")
+		}
+
+		return current + extension, nil
+	})
+}
+
+func (m *TTLManager) changeTTL(
+	ctx context.Context,
+	commonCluster CommonCluster,
+	userID uint,
+	action string,
+	newTTL func(current time.Duration) (time.Duration, error),
+) (*TTLStatus, error) {
+	logger := pipelineContext.LoggerWithCorrelationID(ctx, m.logger).WithFields(logrus.Fields{
+		"organization": commonCluster.GetOrganizationId(),
+		"user":         userID,
+		"clusterID":    commonCluster.GetID(),
+		"cluster":      commonCluster.GetName(),
+	})
+
+	clusterDetail, err := commonCluster.GetStatus()
+	if err != nil {
+		return nil, emperror.WrapWith(err, "failed to retrieve cluster details", "clusterID", commonCluster.GetID())
+	}
+
+	if clusterDetail.Status != pkgCluster.Running && clusterDetail.Status != pkgCluster.Warning {
+		return nil, errors.WithStack(&invalidError{errors.Errorf("TTL can only be changed on running clusters, cluster is in %s state", clusterDetail.Status)})
+	}
+
+	currentTTL := commonCluster.GetTTL()
+
+	ttl, err := newTTL(currentTTL)
+	if err != nil {
+		return nil, errors.WithStack(&invalidError{err})
+	}
+
+	// TTL is stored in minutes
+	ttl = ttl.Truncate(time.Minute)
+
+	clusterDetail.TtlMinutes = uint(ttl / time.Minute)
+	status := newTTLStatus(clusterDetail)
+
+	if status.ExpiresAt != nil && status.ExpiresAt.Before(time.Now()) {
+		return nil, errors.WithStack(&invalidError{errors.Errorf("cluster would expire immediately, TTL must be longer than %s", time.Since(*status.StartedAt).Round(time.Minute))})
+	}
+
+	commonCluster.SetTTL(ttl)
+
+	if err := commonCluster.Persist(); err != nil {
+		return nil, emperror.WrapWith(err, "failed to persist cluster TTL", "clusterID", commonCluster.GetID())
+	}
+
+	logger.WithFields(logrus.Fields{"from": currentTTL, "to": ttl}).Info("cluster TTL changed")
+
+	err = m.history.Record(&intCluster.TTLHistoryModel{
+		ClusterID:      commonCluster.GetID(),
+		ClusterName:    commonCluster.GetName(),
+		UserID:         userID,
+		Action:         action,
+		FromTTLMinutes: uint(currentTTL / time.Minute),
+		ToTTLMinutes:   clusterDetail.TtlMinutes,
+		ExpiresAt:      status.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// let the TTL controller pick up the change
+	m.events.ClusterUpdated(commonCluster.GetID())
+
+	return status, nil
+}
+
+func newTTLStatus(clusterDetail *pkgCluster.GetClusterStatusResponse) *TTLStatus {
+	status := &TTLStatus{
+		TTL:       time.Duration(clusterDetail.TtlMinutes) * time.Minute,
+		StartedAt: getClusterStartTime(clusterDetail),
+	}
+
+	if status.TTL > 0 && status.StartedAt != nil {
+		expiresAt := status.StartedAt.Add(status.TTL)
+		status.ExpiresAt = &expiresAt
+	}
+
+	return status
+}
@@ -0,0 +1,103 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+
+	"github.com/banzaicloud/pipeline/config"
+	"github.com/banzaicloud/pipeline/internal/audit"
+	"github.com/banzaicloud/pipeline/internal/backoff"
+	"github.com/banzaicloud/pipeline/pkg/objectstore"
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+// newAuditSinks creates the configured external audit event sinks.
+func newAuditSinks() ([]audit.Sink, error) {
+	var sinks []audit.Sink
+
+	if url := viper.GetString(config.AuditSinkWebhookURL); url != "" {
+		sinks = append(sinks, audit.NewWebhookSink(
+			url,
+			viper.GetStringMapString(config.AuditSinkWebhookHeaders),
+			http.DefaultClient,
+			backoff.ConstantBackoffConfig{
+				Delay:      viper.GetDuration(config.AuditSinkWebhookRetryDelay),
+				MaxRetries: viper.GetInt(config.AuditSinkWebhookMaxRetries),
+			},
+		))
+	}
+
+	if path := viper.GetString(config.AuditSinkFilePath); path != "" {
+		sink, err := audit.NewFileSink(path)
+		if err != nil {
+			return nil, err
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	if viper.GetBool(config.AuditSinkSyslogEnabled) {
+		sink, err := audit.NewSyslogSink(
+			viper.GetString(config.AuditSinkSyslogNetwork),
+			viper.GetString(config.AuditSinkSyslogAddress),
+			viper.GetString(config.AuditSinkSyslogTag),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sinks = append(sinks, sink)
+	}
+
+	if bucket := viper.GetString(config.AuditSinkObjectStoreBucket); bucket != "" {
+		secretItem, err := secret.Store.Get(
+			uint(viper.GetInt(config.AuditSinkObjectStoreOrgID)),
+			viper.GetString(config.AuditSinkObjectStoreSecretID),
+		)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get audit object store secret")
+		}
+
+		provider := viper.GetString(config.AuditSinkObjectStoreProvider)
+		if provider == "" {
+			provider = secretItem.Type
+		}
+
+		if secretItem.Type != provider {
+			return nil, errors.Errorf("audit object store secret type %s does not match provider %s", secretItem.Type, provider)
+		}
+
+		objectStore, err := objectstore.New(
+			objectstore.Config{
+				Provider:       provider,
+				Region:         viper.GetString(config.AuditSinkObjectStoreRegion),
+				ResourceGroup:  viper.GetString(config.AuditSinkObjectStoreResourceGroup),
+				StorageAccount: viper.GetString(config.AuditSinkObjectStoreStorageAccount),
+			},
+			secretItem.Values,
+		)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to create audit object store")
+		}
+
+		sinks = append(sinks, audit.NewObjectStoreSink(objectStore, bucket, viper.GetString(config.AuditSinkObjectStorePrefix)))
+	}
+
+	return sinks, nil
+}
@@ -17,10 +17,13 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"path"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/banzaicloud/pipeline/internal/clustergroup/deployment"
@@ -50,6 +53,7 @@ import (
 	arkSync "github.com/banzaicloud/pipeline/internal/ark/sync"
 	"github.com/banzaicloud/pipeline/internal/audit"
 	intAuth "github.com/banzaicloud/pipeline/internal/auth"
+	"github.com/banzaicloud/pipeline/internal/backoff"
 	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	intClusterAuth "github.com/banzaicloud/pipeline/internal/cluster/auth"
 	"github.com/banzaicloud/pipeline/internal/cluster/clustersecret"
@@ -57,6 +61,7 @@ import (
 	prometheusMetrics "github.com/banzaicloud/pipeline/internal/cluster/metrics/adapters/prometheus"
 	cgroupAdapter "github.com/banzaicloud/pipeline/internal/clustergroup/adapter"
 	"github.com/banzaicloud/pipeline/internal/dashboard"
+	"github.com/banzaicloud/pipeline/internal/global"
 	"github.com/banzaicloud/pipeline/internal/monitor"
 	"github.com/banzaicloud/pipeline/internal/notification"
 	ginternal "github.com/banzaicloud/pipeline/internal/platform/gin"
@@ -76,11 +81,15 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/goph/emperror"
 	"github.com/jinzhu/gorm"
+	"github.com/oklog/run"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
+// shutdownTimeout is how long in-flight API requests are waited for when shutting down
+const shutdownTimeout = 10 * time.Second
+
 //Common logger for package
 // nolint: gochecknoglobals
 var log *logrus.Logger
@@ -119,6 +128,11 @@ func main() {
 	}).Info("Pipeline initialization")
 	errorHandler := config.ErrorHandler()
 
+	err := global.ValidateCertSource()
+	if err != nil {
+		logger.Panic(err.Error())
+	}
+
 	// Connect to database
 	db := config.DB()
 	cicdDB, err := config.CICDDB()
@@ -128,12 +142,30 @@ func main() {
 
 	basePath := viper.GetString("pipeline.basepath")
 
-	enforcer := intAuth.NewEnforcer(db)
-	accessManager := intAuth.NewAccessManager(enforcer, basePath)
-	accessManager.AddDefaultPolicies()
+	var enforcerOptions []intAuth.EnforcerOption
+	if viper.GetBool(config.ClusterKubeconfigAdminOnly) {
+		enforcerOptions = append(enforcerOptions, intAuth.RestrictAdminKubeconfig())
+	}
+
+	enforcer := intAuth.NewEnforcer(db, enforcerOptions...)
+	accessManager := intAuth.NewAccessManager(db)
+
+	var groupMappings []auth.GroupMapping
+	err = viper.UnmarshalKey("auth.groupMappings", &groupMappings)
+	if err != nil {
+		logger.Panic(err.Error())
+	}
+
+	var groupMapper *auth.GroupMapper
+	if len(groupMappings) > 0 {
+		groupMapper, err = auth.NewGroupMapper(groupMappings)
+		if err != nil {
+			logger.Panic(err.Error())
+		}
+	}
 
-	orgImporter := auth.NewOrgImporter(db, accessManager, config.EventBus)
-	tokenHandler := auth.NewTokenHandler(accessManager)
+	orgImporter := auth.NewOrgImporter(db, accessManager, config.EventBus, groupMapper)
+	tokenHandler := auth.NewTokenHandler()
 
 	// Initialize auth
 	auth.Init(cicdDB, accessManager, orgImporter)
@@ -211,13 +243,118 @@ func main() {
 	clusterManager := cluster.NewManager(clusters, secretValidator, clusterEvents, statusChangeDurationMetric, clusterTotalMetric, workflowClient, log, errorHandler)
 	clusterGetter := common.NewClusterGetter(clusterManager, logger, errorHandler)
 
-	clusterTTLController := cluster.NewTTLController(clusterManager, clusterEventBus, log.WithField("subsystem", "ttl-controller"), errorHandler)
+	if url := viper.GetString(config.ClusterTTLWarningWebhookURL); url != "" {
+		notification.NewClusterExpiryWebhook(
+			notification.NewClusterEvents(clusterEventBus),
+			url,
+			&http.Client{Timeout: 30 * time.Second},
+			backoff.ConstantBackoffConfig{Delay: 5 * time.Second, MaxRetries: 5},
+			errorHandler,
+		)
+	}
+
+	clusterTTLHistory := intCluster.NewTTLHistory(db)
+	clusterTTLController := cluster.NewTTLController(
+		clusterManager,
+		clusterEventBus,
+		clusterTTLHistory,
+		viper.GetDuration(config.ClusterTTLWarningLeadTime),
+		log.WithField("subsystem", "ttl-controller"),
+		errorHandler,
+	)
 	defer clusterTTLController.Stop()
 	err = clusterTTLController.Start()
 	if err != nil {
 		logger.Panic(err)
 	}
 
+	clusterHibernationSchedules := intCluster.NewHibernationSchedules(db)
+	clusterHibernationManager := cluster.NewHibernationManager(clusterHibernationSchedules, clusterManager, externalBaseURL, log)
+	if viper.GetBool(config.ClusterHibernationEnabled) {
+		clusterHibernationController := cluster.NewHibernationController(
+			clusterHibernationManager,
+			clusterHibernationSchedules,
+			viper.GetDuration(config.ClusterHibernationCheckInterval),
+			log.WithField("subsystem", "hibernation-controller"),
+			errorHandler,
+		)
+		defer clusterHibernationController.Stop()
+		err = clusterHibernationController.Start()
+		if err != nil {
+			logger.Panic(err)
+		}
+	}
+
+	clusterCostSampleInterval := viper.GetDuration(config.ClusterCostSampleInterval)
+	clusterCostManager := cluster.NewCostManager(
+		intCluster.NewClusterCostRecords(db),
+		cluster.NewCloudinfoNodePrices(log),
+		intCluster.NewLabels(db),
+		// a few missed samples (eg. during a restart) still count as continuous running
+		3*clusterCostSampleInterval,
+		log,
+	)
+	if viper.GetBool(config.ClusterCostEnabled) {
+		clusterCostController := cluster.NewCostController(
+			clusterCostManager,
+			clusterManager,
+			clusterCostSampleInterval,
+			log.WithField("subsystem", "cost-controller"),
+			errorHandler,
+		)
+		defer clusterCostController.Stop()
+		err = clusterCostController.Start()
+		if err != nil {
+			logger.Panic(err)
+		}
+	}
+
+	var auditSink audit.Sink
+	if viper.GetBool("audit.enabled") {
+		auditSinks, err := newAuditSinks()
+		if err != nil {
+			logger.Panic(err)
+		}
+
+		if len(auditSinks) > 0 {
+			auditSinkDispatcher := audit.NewSinkDispatcher(
+				auditSinks,
+				viper.GetInt(config.AuditSinkBufferSize),
+				viper.GetDuration(config.AuditSinkFlushInterval),
+				log.WithField("subsystem", "audit-sink"),
+				errorHandler,
+			)
+			defer auditSinkDispatcher.Stop()
+			err = auditSinkDispatcher.Start()
+			if err != nil {
+				logger.Panic(err)
+			}
+
+			auditSink = auditSinkDispatcher
+		}
+	}
+
+	// Audit events are kept in the database unless only the sinks should receive them
+	var auditDB *gorm.DB
+	if viper.GetBool("audit.enabled") && viper.GetBool(config.AuditDatabaseEnabled) {
+		auditDB = db
+
+		if retention := viper.GetDuration(config.AuditDatabaseRetention); retention > 0 {
+			auditRetentionController := audit.NewRetentionController(
+				audit.NewEvents(db),
+				retention,
+				time.Hour,
+				log.WithField("subsystem", "audit-retention"),
+				errorHandler,
+			)
+			defer auditRetentionController.Stop()
+			err = auditRetentionController.Start()
+			if err != nil {
+				logger.Panic(err)
+			}
+		}
+	}
+
 	if viper.GetBool(config.MonitorEnabled) {
 		client, err := k8sclient.NewInClusterClient()
 		if err != nil {
@@ -277,13 +414,56 @@ func main() {
 	clusterGroupManager.RegisterFeatureHandler(federation.FeatureName, federationHandler)
 	clusterGroupManager.RegisterFeatureHandler(deployment.FeatureName, deploymentManager)
 
-	clusterAPI := api.NewClusterAPI(clusterManager, clusterGetter, workflowClient, clusterGroupManager, log, errorHandler, externalBaseURL, clusterCreators, clusterDeleters)
+	clusterLabelManager := cluster.NewLabelManager(intCluster.NewLabels(db))
+	clusterAPI := api.NewClusterAPI(clusterManager, clusterGetter, workflowClient, clusterGroupManager, clusterLabelManager, log, errorHandler, externalBaseURL, clusterCreators, clusterDeleters)
 
 	nplsApi := api.NewNodepoolManagerAPI(clusterGetter, log, errorHandler)
+	clusterTTLAPI := api.NewClusterTTLAPI(clusterGetter, cluster.NewTTLManager(clusterTTLHistory, clusterEvents, log), log, errorHandler)
+	clusterPostHookAPI := api.NewClusterPostHookAPI(clusterGetter, cluster.NewPostHookManager(intCluster.NewPostHookStatuses(db), workflowClient, log), log, errorHandler)
+	organizationPostHookAPI := api.NewOrganizationPostHookAPI(cluster.NewOrganizationPostHookManager(intCluster.NewOrganizationPostHooks(db), log), log, errorHandler)
+	clusterHibernationAPI := api.NewClusterHibernationAPI(clusterGetter, clusterHibernationManager, log, errorHandler)
+	clusterCostAPI := api.NewClusterCostAPI(clusterGetter, clusterCostManager, log, errorHandler)
+	rbacBindingManager := cluster.NewRBACBindingManager(intCluster.NewClusterRBACBindings(db), cluster.NewOrganizationMembers(db), clusterManager, log)
+	clusterRBACAPI := api.NewClusterRBACAPI(clusterGetter, rbacBindingManager, log, errorHandler)
+
+	// Users leaving organizations lose their role bindings in the clusters
+	_ = config.EventBus.SubscribeAsync(auth.OrganizationMemberRemovedTopic, rbacBindingManager.ReconcileMemberClusters, false)
+	serviceAccountAPI := api.NewServiceAccountAPI(db, log, errorHandler)
+	secretInstallationAPI := api.NewSecretInstallationAPI(
+		cluster.NewSecretSyncManager(intCluster.NewSecretInstallations(db), workflowClient, log),
+		log,
+		errorHandler,
+	)
+	secretRotationAPI := api.NewSecretRotationAPI(
+		cluster.NewSecretRotationManager(clusterManager, intCluster.NewSecretInstallations(db), db, workflowClient, log),
+		log,
+		errorHandler,
+	)
+	secretUsageAPI := api.NewSecretUsageAPI(
+		cluster.NewSecretUsageManager(clusterManager, intCluster.NewSecretInstallations(db), deployment.NewCGDeploymentRepository(db, log), db, log),
+		log,
+		errorHandler,
+	)
+	auditAPI := api.NewAuditAPI(audit.NewEvents(db), log, errorHandler)
+	clusterUpgradeAPI := api.NewClusterUpgradeAPI(clusterGetter, cluster.NewKubernetesUpgradeManager(clusterManager, externalBaseURL, log), log, errorHandler)
+	clusterStatusHistoryAPI := api.NewClusterStatusHistoryAPI(clusterGetter, intCluster.NewStatusHistory(db), log, errorHandler)
+
+	trustedProxies, err := ginternal.ParseTrustedProxies(viper.GetStringSlice("pipeline.trustedProxies"))
+	if err != nil {
+		emperror.Panic(emperror.Wrap(err, "failed to parse trusted proxies"))
+	}
+
+	if len(trustedProxies) == 0 {
+		log.Warn("no trusted proxies configured (pipeline.trustedProxies): forwarding headers are ignored and client addresses are the addresses of direct peers")
+	}
 
 	//Initialise Gin router
 	router := gin.New()
 
+	// Forwarding headers are only accepted from trusted proxies, otherwise clients could spoof their address
+	router.ForwardedByClientIP = false
+	router.Use(ginternal.NewTrustedProxyMiddleware(trustedProxies))
+
 	// These two paths can contain sensitive information, so it is advised not to log them out.
 	skipPaths := viper.GetStringSlice("audit.skippaths")
 	router.Use(correlationid.Middleware())
@@ -307,7 +487,7 @@ func main() {
 	router.Use(cors.New(config.GetCORS()))
 	if viper.GetBool("audit.enabled") {
 		log.Infoln("Audit enabled, installing Gin audit middleware")
-		router.Use(audit.LogWriter(skipPaths, viper.GetStringSlice("audit.headers"), db, log))
+		router.Use(audit.LogWriter(skipPaths, viper.GetStringSlice("audit.headers"), auditDB, auditSink, log))
 	}
 
 	router.GET("/", api.RedirectRoot)
@@ -320,16 +500,18 @@ func main() {
 	auth.StartTokenStoreGC()
 
 	authorizationMiddleware := intAuth.NewMiddleware(enforcer, basePath, errorHandler)
+	ipAllowlistMiddleware := intAuth.NewIPAllowlistMiddleware(db, log, errorHandler)
 
 	dgroup := base.Group(path.Join("dashboard", "orgs"))
 	dgroup.Use(auth.Handler)
 	dgroup.Use(api.OrganizationMiddleware)
+	dgroup.Use(ipAllowlistMiddleware)
 	dgroup.Use(authorizationMiddleware)
 	dgroup.GET("/:orgid/clusters", dashboard.GetDashboard)
 
 	domainAPI := api.NewDomainAPI(clusterManager, log, errorHandler)
 	organizationAPI := api.NewOrganizationAPI(orgImporter)
-	userAPI := api.NewUserAPI(accessManager, db, log, errorHandler)
+	userAPI := api.NewUserAPI(accessManager, db, auth.NewEvents(config.EventBus), log, errorHandler)
 	networkAPI := api.NewNetworkAPI(log)
 
 	scmProvider := viper.GetString("cicd.scm")
@@ -382,10 +564,11 @@ func main() {
 		v1.GET("/functions", api.ListFunctions)
 		v1.GET("/securityscan", api.SecurityScanEnabled)
 		v1.GET("/me", userAPI.GetCurrentUser)
-		v1.PATCH("/me", userAPI.UpdateCurrentUser)
+		v1.PATCH("/me", auth.NewFullAccessMiddleware("update the user"), userAPI.UpdateCurrentUser)
 		orgs := v1.Group("/orgs")
 		{
 			orgs.Use(api.OrganizationMiddleware)
+			orgs.Use(ipAllowlistMiddleware)
 			orgs.Use(authorizationMiddleware)
 
 			orgs.GET("/:orgid/spotguides", spotguideAPI.GetSpotguides)
@@ -403,9 +586,32 @@ func main() {
 			orgs.GET("/:orgid/clusters/:id", clusterAPI.GetCluster)
 			orgs.GET("/:orgid/clusters/:id/pods", api.GetPodDetails)
 			orgs.GET("/:orgid/clusters/:id/bootstrap", clusterAPI.GetBootstrapInfo)
+			orgs.GET("/:orgid/clusters/:id/export", clusterAPI.ExportCluster)
+			orgs.GET("/:orgid/clusters/:id/labels", clusterAPI.GetClusterLabels)
+			orgs.PUT("/:orgid/clusters/:id/labels", clusterAPI.SetClusterLabels)
+			orgs.GET("/:orgid/clusters/:id/history", clusterStatusHistoryAPI.GetClusterStatusHistory)
+			orgs.GET("/:orgid/history/clusters", clusterStatusHistoryAPI.GetOrganizationStatusHistory)
+			orgs.GET("/:orgid/costs", clusterCostAPI.GetOrganizationCost)
+			orgs.POST("/:orgid/costs/estimate", clusterCostAPI.EstimateCreateCost)
 			orgs.PUT("/:orgid/clusters/:id", clusterAPI.UpdateCluster)
+			orgs.GET("/:orgid/clusters/:id/ttl", clusterTTLAPI.GetClusterTTL)
+			orgs.PUT("/:orgid/clusters/:id/ttl", clusterTTLAPI.SetClusterTTL)
+			orgs.DELETE("/:orgid/clusters/:id/ttl", clusterTTLAPI.DeleteClusterTTL)
+			orgs.POST("/:orgid/clusters/:id/ttl/extend", clusterTTLAPI.ExtendClusterTTL)
+			orgs.GET("/:orgid/clusters/:id/hibernation", clusterHibernationAPI.GetClusterHibernation)
+			orgs.PUT("/:orgid/clusters/:id/hibernation", clusterHibernationAPI.SetClusterHibernation)
+			orgs.DELETE("/:orgid/clusters/:id/hibernation", clusterHibernationAPI.DeleteClusterHibernation)
+			orgs.POST("/:orgid/clusters/:id/upgrade", clusterUpgradeAPI.UpgradeCluster)
+			orgs.GET("/:orgid/clusters/:id/costs", clusterCostAPI.GetClusterCost)
+			orgs.POST("/:orgid/clusters/:id/costs/estimate", clusterCostAPI.EstimateUpdateCost)
+			orgs.GET("/:orgid/clusters/:id/rbacbindings", clusterRBACAPI.ListBindings)
+			orgs.POST("/:orgid/clusters/:id/rbacbindings", clusterRBACAPI.CreateBinding)
+			orgs.PUT("/:orgid/clusters/:id/rbacbindings", clusterRBACAPI.SyncBindings)
+			orgs.DELETE("/:orgid/clusters/:id/rbacbindings/:bindingId", clusterRBACAPI.DeleteBinding)
 
 			orgs.PUT("/:orgid/clusters/:id/posthooks", clusterAPI.ReRunPostHooks)
+			orgs.GET("/:orgid/clusters/:id/posthooks", clusterPostHookAPI.GetClusterPostHooks)
+			orgs.POST("/:orgid/clusters/:id/posthooks/rerun", clusterPostHookAPI.ReRunClusterPostHooks)
 			orgs.POST("/:orgid/clusters/:id/secrets", api.InstallSecretsToCluster)
 			orgs.POST("/:orgid/clusters/:id/secrets/:secretName", api.InstallSecretToCluster)
 			orgs.PATCH("/:orgid/clusters/:id/secrets/:secretName", api.MergeSecretInCluster)
@@ -465,8 +671,7 @@ func main() {
 
 			pkeGroup := clusters.Group("/pke")
 
-			leaderRepository, err := pke.NewVaultLeaderRepository()
-			emperror.Panic(emperror.Wrap(err, "failed to create Vault leader repository"))
+			leaderRepository := pke.NewVaultLeaderRepository()
 
 			pkeAPI := pke.NewAPI(clusterGetter, errorHandler, tokenHandler, externalBaseURL, workflowClient, leaderRepository)
 			pkeAPI.RegisterRoutes(pkeGroup)
@@ -506,19 +711,49 @@ func main() {
 			orgs.POST("/:orgid/profiles/cluster", api.AddClusterProfile)
 			orgs.PUT("/:orgid/profiles/cluster", api.UpdateClusterProfile)
 			orgs.DELETE("/:orgid/profiles/cluster/:distribution/:name", api.DeleteClusterProfile)
+			orgs.GET("/:orgid/posthooks", organizationPostHookAPI.ListOrganizationPostHooks)
+			orgs.POST("/:orgid/posthooks", organizationPostHookAPI.CreateOrganizationPostHook)
+			orgs.GET("/:orgid/posthooks/:name", organizationPostHookAPI.GetOrganizationPostHook)
+			orgs.PUT("/:orgid/posthooks/:name", organizationPostHookAPI.UpdateOrganizationPostHook)
+			orgs.DELETE("/:orgid/posthooks/:name", organizationPostHookAPI.DeleteOrganizationPostHook)
 			orgs.GET("/:orgid/secrets", api.ListSecrets)
 			orgs.GET("/:orgid/secrets/:id", api.GetSecret)
 			orgs.POST("/:orgid/secrets", api.AddSecrets)
-			orgs.PUT("/:orgid/secrets/:id", api.UpdateSecrets)
-			orgs.DELETE("/:orgid/secrets/:id", api.DeleteSecrets)
+			orgs.PUT("/:orgid/secrets/:id", api.UpdateSecrets, secretInstallationAPI.SyncSecretInstallationsAfterUpdate)
+			orgs.DELETE("/:orgid/secrets/:id", secretUsageAPI.RequireUnusedSecret, api.DeleteSecrets)
 			orgs.GET("/:orgid/secrets/:id/validate", api.ValidateSecret)
+			orgs.GET("/:orgid/secrets/:id/versions", api.ListSecretVersions)
+			orgs.GET("/:orgid/secrets/:id/versions/:version", api.GetSecretVersion)
+			orgs.GET("/:orgid/secrets/:id/versions/:version/diff", api.DiffSecretVersions)
+			orgs.POST("/:orgid/secrets/:id/versions/:version/rollback", api.RollbackSecret, secretInstallationAPI.SyncSecretInstallationsAfterUpdate)
+			orgs.GET("/:orgid/secrets/:id/installations", secretInstallationAPI.ListSecretInstallations)
+			orgs.POST("/:orgid/secrets/:id/installations/sync", secretInstallationAPI.SyncSecretInstallations)
+			orgs.POST("/:orgid/secrets/:id/rotate", secretRotationAPI.RotateSecret)
+			orgs.GET("/:orgid/secrets/:id/usages", secretUsageAPI.GetSecretUsages)
 			orgs.GET("/:orgid/secrets/:id/tags", api.GetSecretTags)
 			orgs.PUT("/:orgid/secrets/:id/tags/*tag", api.AddSecretTag)
 			orgs.DELETE("/:orgid/secrets/:id/tags/*tag", api.DeleteSecretTag)
 			orgs.GET("/:orgid/users", userAPI.GetUsers)
 			orgs.GET("/:orgid/users/:id", userAPI.GetUsers)
 			orgs.POST("/:orgid/users/:id", userAPI.AddUser)
+			orgs.PUT("/:orgid/users/:id", userAPI.UpdateUserRole)
 			orgs.DELETE("/:orgid/users/:id", userAPI.RemoveUser)
+			orgs.GET("/:orgid/invitations", userAPI.GetInvitations)
+			orgs.POST("/:orgid/invitations", userAPI.InviteUser)
+			orgs.DELETE("/:orgid/invitations/:id", userAPI.DeleteInvitation)
+			orgs.GET("/:orgid/tokenpolicy", auth.GetTokenPolicy)
+			orgs.PUT("/:orgid/tokenpolicy", auth.SetTokenPolicy)
+			orgs.GET("/:orgid/ipallowlist", auth.GetIPAllowlist)
+			orgs.PUT("/:orgid/ipallowlist", auth.SetIPAllowlist)
+			orgs.GET("/:orgid/audit", auditAPI.ListEvents)
+			orgs.GET("/:orgid/serviceaccounts", serviceAccountAPI.List)
+			orgs.POST("/:orgid/serviceaccounts", serviceAccountAPI.Create)
+			orgs.GET("/:orgid/serviceaccounts/:id", serviceAccountAPI.Get)
+			orgs.PUT("/:orgid/serviceaccounts/:id", serviceAccountAPI.Update)
+			orgs.DELETE("/:orgid/serviceaccounts/:id", serviceAccountAPI.Delete)
+			orgs.GET("/:orgid/serviceaccounts/:id/tokens", serviceAccountAPI.ListTokens)
+			orgs.POST("/:orgid/serviceaccounts/:id/tokens", serviceAccountAPI.CreateToken)
+			orgs.DELETE("/:orgid/serviceaccounts/:id/tokens/:tokenId", serviceAccountAPI.DeleteToken)
 
 			orgs.GET("/:orgid/buckets", api.ListAllBuckets)
 			orgs.POST("/:orgid/buckets", api.CreateBucket)
@@ -540,12 +775,13 @@ func main() {
 			orgs.DELETE("/:orgid", organizationAPI.DeleteOrganization)
 		}
 		v1.GET("/orgs", organizationAPI.GetOrganizations)
-		v1.PUT("/orgs", organizationAPI.SyncOrganizations)
+		v1.PUT("/orgs", auth.NewFullAccessMiddleware("synchronize organizations"), organizationAPI.SyncOrganizations)
 		v1.GET("/token", tokenHandler.GenerateToken) // TODO Deprecated, should be removed once the UI has support.
 		v1.POST("/tokens", tokenHandler.GenerateToken)
 		v1.GET("/tokens", auth.GetTokens)
 		v1.GET("/tokens/:id", auth.GetTokens)
 		v1.DELETE("/tokens/:id", auth.DeleteToken)
+		v1.POST("/tokens/:id/rotate", auth.RotateToken)
 
 		v1.GET("/allowed/secrets", api.ListAllowedSecretTypes)
 		v1.GET("/allowed/secrets/:type", api.ListAllowedSecretTypes)
@@ -585,7 +821,7 @@ func main() {
 
 	internalBindAddr := viper.GetString("pipeline.internalBindAddr")
 	logger.Infof("Pipeline internal API listening on http://%s", internalBindAddr)
-	go createInternalAPIRouter(skipPaths, db, basePath, clusterAPI).Run(internalBindAddr)
+	go createInternalAPIRouter(skipPaths, auditDB, auditSink, basePath, clusterAPI).Run(internalBindAddr)
 
 	bindAddr := viper.GetString("pipeline.bindaddr")
 	if port := viper.GetInt("pipeline.listenport"); port != 0 {
@@ -594,16 +830,67 @@ func main() {
 		logger.Errorf("pipeline.listenport=%d setting is deprecated! Falling back to pipeline.bindaddr=%s", port, bindAddr)
 	}
 	certFile, keyFile := viper.GetString("pipeline.certfile"), viper.GetString("pipeline.keyfile")
-	if certFile != "" && keyFile != "" {
-		logger.Infof("Pipeline API listening on https://%s", bindAddr)
-		router.RunTLS(bindAddr, certFile, keyFile)
-	} else {
-		logger.Infof("Pipeline API listening on http://%s", bindAddr)
-		router.Run(bindAddr)
+
+	var group run.Group
+
+	// Pipeline API server
+	{
+		server := &http.Server{Addr: bindAddr, Handler: router}
+
+		group.Add(
+			func() error {
+				if certFile != "" && keyFile != "" {
+					logger.Infof("Pipeline API listening on https://%s", bindAddr)
+					return server.ListenAndServeTLS(certFile, keyFile)
+				}
+
+				logger.Infof("Pipeline API listening on http://%s", bindAddr)
+				return server.ListenAndServe()
+			},
+			func(e error) {
+				ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+				defer cancel()
+
+				if err := server.Shutdown(ctx); err != nil {
+					errorHandler.Handle(emperror.Wrap(err, "failed to shut down the API server"))
+				}
+			},
+		)
+	}
+
+	// Setup signal handler, so that the deferred shutdown of the background components (eg. flushing audit sinks) runs
+	{
+		var (
+			cancelInterrupt = make(chan struct{})
+			ch              = make(chan os.Signal, 2)
+		)
+		defer close(ch)
+
+		group.Add(
+			func() error {
+				signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+
+				select {
+				case sig := <-ch:
+					logger.WithField("signal", sig).Info("captured signal")
+				case <-cancelInterrupt:
+				}
+
+				return nil
+			},
+			func(e error) {
+				close(cancelInterrupt)
+				signal.Stop(ch)
+			},
+		)
+	}
+
+	if err := group.Run(); err != nil && err != http.ErrServerClosed {
+		errorHandler.Handle(err)
 	}
 }
 
-func createInternalAPIRouter(skipPaths []string, db *gorm.DB, basePath string, clusterAPI *api.ClusterAPI) *gin.Engine {
+func createInternalAPIRouter(skipPaths []string, auditDB *gorm.DB, auditSink audit.Sink, basePath string, clusterAPI *api.ClusterAPI) *gin.Engine {
 	//Initialise Gin router for Internal API
 	internalRouter := gin.New()
 	internalRouter.Use(correlationid.Middleware())
@@ -611,7 +898,7 @@ func createInternalAPIRouter(skipPaths []string, db *gorm.DB, basePath string, c
 	internalRouter.Use(gin.Recovery())
 	if viper.GetBool("audit.enabled") {
 		log.Infoln("Audit enabled, installing Gin audit middleware to internal router")
-		internalRouter.Use(audit.LogWriter(skipPaths, viper.GetStringSlice("audit.headers"), db, log))
+		internalRouter.Use(audit.LogWriter(skipPaths, viper.GetStringSlice("audit.headers"), auditDB, auditSink, log))
 	}
 	internalGroup := internalRouter.Group(path.Join(basePath, "api", "v1/", "orgs"))
 	internalGroup.Use(auth.InternalUserHandler)
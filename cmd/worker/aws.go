@@ -26,6 +26,7 @@ func registerAwsWorkflows(clusters *pkeworkflowadapter.ClusterManagerAdapter, to
 	workflow.RegisterWithOptions(pkeworkflow.CreateClusterWorkflow, workflow.RegisterOptions{Name: pkeworkflow.CreateClusterWorkflowName})
 	workflow.RegisterWithOptions(pkeworkflow.DeleteClusterWorkflow, workflow.RegisterOptions{Name: pkeworkflow.DeleteClusterWorkflowName})
 	workflow.RegisterWithOptions(pkeworkflow.UpdateClusterWorkflow, workflow.RegisterOptions{Name: pkeworkflow.UpdateClusterWorkflowName})
+	workflow.RegisterWithOptions(pkeworkflow.UpgradeClusterWorkflow, workflow.RegisterOptions{Name: pkeworkflow.UpgradeClusterWorkflowName})
 
 	awsClientFactory := pkeworkflow.NewAWSClientFactory(pkeworkflowadapter.NewSecretStore(secret.Store))
 
@@ -80,4 +81,28 @@ func registerAwsWorkflows(clusters *pkeworkflowadapter.ClusterManagerAdapter, to
 	deleteSshKeyPairActivity := pkeworkflow.NewDeleteSSHKeyPairActivity(clusters)
 	activity.RegisterWithOptions(deleteSshKeyPairActivity.Execute, activity.RegisterOptions{Name: pkeworkflow.DeleteSSHKeyPairActivityName})
 
+	saveKubernetesVersionActivity := pkeworkflow.NewSaveKubernetesVersionActivity(clusters)
+	activity.RegisterWithOptions(saveKubernetesVersionActivity.Execute, activity.RegisterOptions{Name: pkeworkflow.SaveKubernetesVersionActivityName})
+
+	listNodesActivity := pkeworkflow.NewListNodesActivity(clusters)
+	activity.RegisterWithOptions(listNodesActivity.Execute, activity.RegisterOptions{Name: pkeworkflow.ListNodesActivityName})
+
+	cordonNodeActivity := pkeworkflow.NewCordonNodeActivity(clusters)
+	activity.RegisterWithOptions(cordonNodeActivity.Execute, activity.RegisterOptions{Name: pkeworkflow.CordonNodeActivityName})
+
+	drainNodeActivity := pkeworkflow.NewDrainNodeActivity(clusters)
+	activity.RegisterWithOptions(drainNodeActivity.Execute, activity.RegisterOptions{Name: pkeworkflow.DrainNodeActivityName})
+
+	upgradeMasterNodeActivity := pkeworkflow.NewUpgradeMasterNodeActivity(clusters)
+	activity.RegisterWithOptions(upgradeMasterNodeActivity.Execute, activity.RegisterOptions{Name: pkeworkflow.UpgradeMasterNodeActivityName})
+
+	updatePoolVersionActivity := pkeworkflow.NewUpdatePoolVersionActivity(clusters, tokenGenerator)
+	activity.RegisterWithOptions(updatePoolVersionActivity.Execute, activity.RegisterOptions{Name: pkeworkflow.UpdatePoolVersionActivityName})
+
+	replaceNodeActivity := pkeworkflow.NewReplaceNodeActivity(awsClientFactory)
+	activity.RegisterWithOptions(replaceNodeActivity.Execute, activity.RegisterOptions{Name: pkeworkflow.ReplaceNodeActivityName})
+
+	waitForNodesActivity := pkeworkflow.NewWaitForNodesActivity(clusters)
+	activity.RegisterWithOptions(waitForNodesActivity.Execute, activity.RegisterOptions{Name: pkeworkflow.WaitForNodesActivityName})
+
 }
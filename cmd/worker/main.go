@@ -41,6 +41,7 @@ import (
 	intClusterDNS "github.com/banzaicloud/pipeline/internal/cluster/dns"
 	intClusterK8s "github.com/banzaicloud/pipeline/internal/cluster/kubernetes"
 	intClusterWorkflow "github.com/banzaicloud/pipeline/internal/cluster/workflow"
+	"github.com/banzaicloud/pipeline/internal/global"
 	"github.com/banzaicloud/pipeline/internal/platform/buildinfo"
 	"github.com/banzaicloud/pipeline/internal/platform/cadence"
 	"github.com/banzaicloud/pipeline/internal/platform/database"
@@ -96,6 +97,13 @@ func main() {
 		os.Exit(3)
 	}
 
+	err = global.ValidateCertSource()
+	if err != nil {
+		logger.Error(err.Error(), nil)
+
+		os.Exit(3)
+	}
+
 	if viper.GetBool("dump-config") {
 		fmt.Printf("%+v\n", config)
 
@@ -133,9 +141,8 @@ func main() {
 			conf.Logger(),
 			errorHandler,
 		)
-		enforcer := intAuth.NewEnforcer(db)
-		accessManager := intAuth.NewAccessManager(enforcer, config.Pipeline.BasePath)
-		tokenGenerator := pkeworkflowadapter.NewTokenGenerator(auth.NewTokenHandler(accessManager))
+		accessManager := intAuth.NewAccessManager(db)
+		tokenGenerator := pkeworkflowadapter.NewTokenGenerator(auth.NewTokenHandler())
 		auth.Init(nil, accessManager, nil)
 		auth.InitTokenStore()
 
@@ -172,12 +179,36 @@ func main() {
 
 		workflow.RegisterWithOptions(cluster.RunPostHooksWorkflow, workflow.RegisterOptions{Name: cluster.RunPostHooksWorkflowName})
 
-		runPostHookActivity := cluster.NewRunPostHookActivity(clusterManager)
+		postHookStatuses := intCluster.NewPostHookStatuses(db)
+
+		markPostHooksPendingActivity := cluster.NewMarkPostHooksPendingActivity(postHookStatuses)
+		activity.RegisterWithOptions(markPostHooksPendingActivity.Execute, activity.RegisterOptions{Name: cluster.MarkPostHooksPendingActivityName})
+
+		listOrganizationPostHooksActivity := cluster.NewListOrganizationPostHooksActivity(clusterManager, intCluster.NewOrganizationPostHooks(db))
+		activity.RegisterWithOptions(listOrganizationPostHooksActivity.Execute, activity.RegisterOptions{Name: cluster.ListOrganizationPostHooksActivityName})
+
+		runPostHookActivity := cluster.NewRunPostHookActivity(clusterManager, postHookStatuses)
 		activity.RegisterWithOptions(runPostHookActivity.Execute, activity.RegisterOptions{Name: cluster.RunPostHookActivityName})
 
 		updateClusterStatusActivity := cluster.NewUpdateClusterStatusActivity(clusterManager)
 		activity.RegisterWithOptions(updateClusterStatusActivity.Execute, activity.RegisterOptions{Name: cluster.UpdateClusterStatusActivityName})
 
+		workflow.RegisterWithOptions(cluster.SyncSecretInstallationsWorkflow, workflow.RegisterOptions{Name: cluster.SyncSecretInstallationsWorkflowName})
+
+		syncSecretInstallationActivity := cluster.NewSyncSecretInstallationActivity(clusterManager, intCluster.NewSecretInstallations(db))
+		activity.RegisterWithOptions(syncSecretInstallationActivity.Execute, activity.RegisterOptions{Name: cluster.SyncSecretInstallationActivityName})
+
+		workflow.RegisterWithOptions(cluster.RotateSecretWorkflow, workflow.RegisterOptions{Name: cluster.RotateSecretWorkflowName})
+
+		rotateSecretKeyActivity := cluster.NewRotateSecretKeyActivity(secret.Store)
+		activity.RegisterWithOptions(rotateSecretKeyActivity.Execute, activity.RegisterOptions{Name: cluster.RotateSecretKeyActivityName})
+
+		revokeSecretKeyActivity := cluster.NewRevokeSecretKeyActivity(secret.Store)
+		activity.RegisterWithOptions(revokeSecretKeyActivity.Execute, activity.RegisterOptions{Name: cluster.RevokeSecretKeyActivityName})
+
+		updateRotatedSecretConsumerActivity := cluster.NewUpdateRotatedSecretConsumerActivity(clusterManager, secret.Store, db, conf.Logger())
+		activity.RegisterWithOptions(updateRotatedSecretConsumerActivity.Execute, activity.RegisterOptions{Name: cluster.UpdateRotatedSecretConsumerActivityName})
+
 		deleteUnusedClusterSecretsActivity := intClusterWorkflow.MakeDeleteUnusedClusterSecretsActivity(secret.Store)
 		activity.RegisterWithOptions(deleteUnusedClusterSecretsActivity.Execute, activity.RegisterOptions{Name: intClusterWorkflow.DeleteUnusedClusterSecretsActivityName})
 
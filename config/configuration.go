@@ -108,6 +108,51 @@ const (
 	// Database
 	DBAutoMigrateEnabled = "database.autoMigrateEnabled"
 
+	// Secret store
+	SecretStoreBackend       = "secret.backend"                // where secrets are kept: vault or database
+	SecretStoreEncryptionKey = "secret.database.encryptionKey" // base64 encoded 32 bytes long key encrypting secrets kept in the database
+
+	// Cluster TTL
+	ClusterTTLWarningLeadTime   = "cluster.ttl.warningLeadTime"   // how long before deleting an expiring cluster to warn about it
+	ClusterTTLWarningWebhookURL = "cluster.ttl.warningWebhookUrl" // endpoint receiving the expiry warnings (empty disables them)
+
+	// Cluster hibernation
+	ClusterHibernationEnabled       = "cluster.hibernation.enabled"
+	ClusterHibernationCheckInterval = "cluster.hibernation.checkInterval" // how often hibernation schedules are evaluated
+
+	// Cluster cost accounting
+	ClusterCostEnabled        = "cluster.cost.enabled"
+	ClusterCostSampleInterval = "cluster.cost.sampleInterval" // how often node pool sizes are sampled for cost accounting
+
+	// Cluster access
+	ClusterKubeconfigAdminOnly = "cluster.kubeconfig.adminOnly" // only organization admins can download the admin kubeconfig
+
+	// Audit event storage
+	AuditDatabaseEnabled   = "audit.database.enabled"
+	AuditDatabaseRetention = "audit.database.retention" // how long events are kept in the database (zero keeps them forever)
+
+	// Audit event sinks
+	AuditSinkBufferSize          = "audit.sinks.bufferSize"
+	AuditSinkFlushInterval       = "audit.sinks.flushInterval" // how often buffering sinks (eg. object store batches) are flushed
+	AuditSinkWebhookURL          = "audit.sinks.webhook.url"
+	AuditSinkWebhookHeaders      = "audit.sinks.webhook.headers"
+	AuditSinkWebhookMaxRetries   = "audit.sinks.webhook.maxRetries"
+	AuditSinkWebhookRetryDelay   = "audit.sinks.webhook.retryDelay"
+	AuditSinkFilePath            = "audit.sinks.file.path"
+	AuditSinkSyslogEnabled       = "audit.sinks.syslog.enabled"
+	AuditSinkSyslogNetwork       = "audit.sinks.syslog.network" // empty network and address means the local syslog server
+	AuditSinkSyslogAddress       = "audit.sinks.syslog.address"
+	AuditSinkSyslogTag           = "audit.sinks.syslog.tag"
+	AuditSinkObjectStoreProvider = "audit.sinks.objectstore.provider" // defaults to the type of the secret
+	AuditSinkObjectStoreOrgID    = "audit.sinks.objectstore.organizationId"
+	AuditSinkObjectStoreSecretID = "audit.sinks.objectstore.secretId" // cloud provider secret of the organization used to upload the batches
+	AuditSinkObjectStoreRegion   = "audit.sinks.objectstore.region"
+	AuditSinkObjectStoreBucket   = "audit.sinks.objectstore.bucket"
+	AuditSinkObjectStorePrefix   = "audit.sinks.objectstore.prefix"
+
+	AuditSinkObjectStoreResourceGroup  = "audit.sinks.objectstore.resourceGroup"  // Azure only
+	AuditSinkObjectStoreStorageAccount = "audit.sinks.objectstore.storageAccount" // Azure only
+
 	// Monitor config path
 	MonitorEnabled                = "monitor.enabled"
 	MonitorConfigMap              = "monitor.configMap"              // Prometheus config map
@@ -202,6 +247,7 @@ func init() {
 	viper.SetDefault("pipeline.uipath", "/ui")
 	viper.SetDefault("pipeline.basepath", "")
 	viper.SetDefault("pipeline.signupRedirectPath", "/ui")
+	viper.SetDefault("pipeline.trustedProxies", []string{})
 	viper.SetDefault(MetricsEnabled, false)
 	viper.SetDefault(MetricsPort, "9900")
 	viper.SetDefault(MetricsAddress, "127.0.0.1")
@@ -216,9 +262,20 @@ func init() {
 	viper.SetDefault("database.cicddbname", "cicd")
 	viper.SetDefault("database.logging", false)
 	viper.SetDefault(DBAutoMigrateEnabled, false)
+
+	viper.SetDefault(SecretStoreBackend, "vault")
+
 	viper.SetDefault("audit.enabled", true)
 	viper.SetDefault("audit.headers", []string{"secretId"})
 	viper.SetDefault("audit.skippaths", []string{"/auth/github/callback", "/pipeline/api"})
+	viper.SetDefault(AuditDatabaseEnabled, true)
+	viper.SetDefault(AuditDatabaseRetention, time.Duration(0))
+	viper.SetDefault(AuditSinkBufferSize, 1000)
+	viper.SetDefault(AuditSinkFlushInterval, time.Minute)
+	viper.SetDefault(AuditSinkWebhookMaxRetries, 5)
+	viper.SetDefault(AuditSinkWebhookRetryDelay, 5*time.Second)
+	viper.SetDefault(AuditSinkSyslogTag, "pipeline-audit")
+	viper.SetDefault(AuditSinkObjectStorePrefix, "audit")
 	viper.SetDefault("tls.validity", "8760h") // 1 year
 	viper.SetDefault(DNSBaseDomain, "example.org")
 	viper.SetDefault(DNSGcIntervalMinute, 1)
@@ -247,6 +304,15 @@ func init() {
 	viper.SetDefault(ARKBackupSyncInterval, "20s")
 	viper.SetDefault(ARKRestoreWaitTimeout, "5m")
 
+	viper.SetDefault(ClusterTTLWarningLeadTime, time.Hour)
+
+	viper.SetDefault(ClusterHibernationEnabled, true)
+	viper.SetDefault(ClusterHibernationCheckInterval, 5*time.Minute)
+
+	viper.SetDefault(ClusterCostEnabled, true)
+	viper.SetDefault(ClusterKubeconfigAdminOnly, false)
+	viper.SetDefault(ClusterCostSampleInterval, 10*time.Minute)
+
 	viper.SetDefault(SpotMetricsEnabled, false)
 	viper.SetDefault(SpotMetricsCollectionInterval, "30s")
 
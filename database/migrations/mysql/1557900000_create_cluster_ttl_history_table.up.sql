@@ -0,0 +1,13 @@
+CREATE TABLE `cluster_ttl_history` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `cluster_id` int(10) unsigned NOT NULL,
+  `cluster_name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `user_id` int(10) unsigned NOT NULL,
+  `action` varchar(16) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `from_ttl_minutes` int(10) unsigned NOT NULL,
+  `to_ttl_minutes` int(10) unsigned NOT NULL,
+  `expires_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_cluster_ttl_history_cluster_id` (`cluster_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,19 @@
+CREATE TABLE `cluster_hibernation_schedules` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `cluster_id` int(10) unsigned NOT NULL,
+  `created_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  `created_by` int(10) unsigned DEFAULT NULL,
+  `enabled` tinyint(1) DEFAULT NULL,
+  `timezone` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `weekdays` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `wake_up_time` varchar(5) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `sleep_time` varchar(5) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `node_pools` text COLLATE utf8mb4_unicode_ci,
+  `min_count` int(11) NOT NULL,
+  `hibernated` tinyint(1) DEFAULT NULL,
+  `hibernated_at` timestamp NULL DEFAULT NULL,
+  `saved_node_pools` text COLLATE utf8mb4_unicode_ci,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uix_cluster_hibernation_schedules_cluster_id` (`cluster_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,8 @@
+CREATE TABLE `cluster_labels` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `cluster_id` int(10) unsigned NOT NULL,
+  `key` varchar(317) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `value` varchar(63) COLLATE utf8mb4_unicode_ci NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_cluster_labels_cluster_id_key` (`cluster_id`,`key`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
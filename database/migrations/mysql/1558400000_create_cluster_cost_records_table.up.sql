@@ -0,0 +1,16 @@
+CREATE TABLE `cluster_cost_records` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `cluster_id` int(10) unsigned NOT NULL,
+  `organization_id` int(10) unsigned NOT NULL,
+  `cluster_name` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `node_pool` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `instance_type` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `spot` tinyint(1) NOT NULL,
+  `count` int(11) NOT NULL,
+  `hourly_price` double NOT NULL,
+  `started_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `ended_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_cluster_cost_records_cluster_id` (`cluster_id`),
+  KEY `idx_cluster_cost_records_organization_id` (`organization_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1 @@
+UPDATE `user_organizations` SET `role` = 'admin' WHERE `role` = 'owner';
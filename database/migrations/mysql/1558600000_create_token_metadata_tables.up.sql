@@ -0,0 +1,19 @@
+CREATE TABLE `token_metadata` (
+  `token_id` varchar(36) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `user_id` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `scope` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `last_used_at` timestamp NULL DEFAULT NULL,
+  `last_used_ip` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `replaced_by` varchar(255) COLLATE utf8mb4_unicode_ci DEFAULT NULL,
+  `revoke_at` timestamp NULL DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`token_id`),
+  KEY `idx_token_metadata_user_id` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE `organization_token_policies` (
+  `organization_id` int(10) unsigned NOT NULL,
+  `max_token_age_seconds` bigint(20) NOT NULL,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`organization_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,6 @@
+ALTER TABLE `audit_events` ADD COLUMN `organization_id` int(10) unsigned DEFAULT NULL;
+ALTER TABLE `audit_events` ADD INDEX `idx_audit_events_organization_id` (`organization_id`);
+
+UPDATE `audit_events`
+SET `organization_id` = CAST(SUBSTRING_INDEX(SUBSTRING_INDEX(SUBSTRING_INDEX(`path`, '?', 1), '/', 5), '/', -1) AS UNSIGNED)
+WHERE `path` REGEXP '^/api/v1/orgs/[0-9]+(/|[?]|$)';
@@ -0,0 +1 @@
+ALTER TABLE `user_organizations` ADD COLUMN `group_managed` tinyint(1) NOT NULL DEFAULT 0;
@@ -0,0 +1,7 @@
+CREATE TABLE `organization_ip_allowlists` (
+  `organization_id` int(10) unsigned NOT NULL,
+  `api_token_ranges` text COLLATE utf8mb4_unicode_ci,
+  `session_ranges` text COLLATE utf8mb4_unicode_ci,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`organization_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,19 @@
+CREATE TABLE `cluster_secret_installations` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `organization_id` int(10) unsigned NOT NULL,
+  `secret_id` varchar(64) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `cluster_id` int(10) unsigned NOT NULL,
+  `namespace` varchar(253) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `name` varchar(253) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `spec` text COLLATE utf8mb4_unicode_ci,
+  `merge` tinyint(1) NOT NULL DEFAULT 0,
+  `status` varchar(16) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `error` text COLLATE utf8mb4_unicode_ci,
+  `synced_version` int(11) DEFAULT NULL,
+  `synced_at` timestamp NULL DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT NULL,
+  `updated_at` timestamp NULL DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `idx_cluster_secret_installations_target` (`cluster_id`,`namespace`,`name`,`secret_id`),
+  KEY `idx_cluster_secret_installations_secret` (`organization_id`,`secret_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,14 @@
+CREATE TABLE "cluster_ttl_history" (
+  "id" serial,
+  "cluster_id" integer NOT NULL,
+  "cluster_name" text NOT NULL,
+  "created_at" timestamp with time zone NOT NULL,
+  "user_id" integer NOT NULL,
+  "action" varchar(16) NOT NULL,
+  "from_ttl_minutes" integer NOT NULL,
+  "to_ttl_minutes" integer NOT NULL,
+  "expires_at" timestamp with time zone,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX idx_cluster_ttl_history_cluster_id ON "cluster_ttl_history"(cluster_id);
@@ -0,0 +1,20 @@
+CREATE TABLE "cluster_hibernation_schedules" (
+  "id" serial,
+  "cluster_id" integer NOT NULL,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "created_by" integer,
+  "enabled" boolean,
+  "timezone" varchar(64) NOT NULL,
+  "weekdays" text NOT NULL,
+  "wake_up_time" varchar(5) NOT NULL,
+  "sleep_time" varchar(5) NOT NULL,
+  "node_pools" text,
+  "min_count" integer NOT NULL,
+  "hibernated" boolean,
+  "hibernated_at" timestamp with time zone,
+  "saved_node_pools" text,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX uix_cluster_hibernation_schedules_cluster_id ON "cluster_hibernation_schedules"(cluster_id);
@@ -0,0 +1,9 @@
+CREATE TABLE "cluster_labels" (
+  "id" serial,
+  "cluster_id" integer NOT NULL,
+  "key" varchar(317) NOT NULL,
+  "value" varchar(63) NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_cluster_labels_cluster_id_key ON "cluster_labels"(cluster_id, key);
@@ -0,0 +1,15 @@
+CREATE TABLE "cluster_posthook_statuses" (
+  "id" serial,
+  "cluster_id" integer NOT NULL,
+  "name" varchar(64) NOT NULL,
+  "position" integer NOT NULL,
+  "param" text,
+  "status" varchar(16) NOT NULL,
+  "error" text,
+  "started_at" timestamp with time zone,
+  "finished_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_cluster_posthook_statuses_cluster_id_name ON "cluster_posthook_statuses"(cluster_id, name);
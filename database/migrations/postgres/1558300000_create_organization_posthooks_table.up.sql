@@ -0,0 +1,17 @@
+CREATE TABLE "organization_posthooks" (
+  "id" serial,
+  "organization_id" integer NOT NULL,
+  "name" varchar(50) NOT NULL,
+  "chart" varchar(255) NOT NULL,
+  "chart_version" varchar(255),
+  "namespace" varchar(255) NOT NULL,
+  "values" text,
+  "priority" integer NOT NULL,
+  "enabled" boolean NOT NULL,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "created_by" integer,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_organization_posthooks_organization_id_name ON "organization_posthooks"(organization_id, name);
@@ -0,0 +1,17 @@
+CREATE TABLE "cluster_cost_records" (
+  "id" serial,
+  "cluster_id" integer NOT NULL,
+  "organization_id" integer NOT NULL,
+  "cluster_name" varchar(255) NOT NULL,
+  "node_pool" varchar(255) NOT NULL,
+  "instance_type" varchar(255),
+  "spot" boolean NOT NULL,
+  "count" integer NOT NULL,
+  "hourly_price" double precision NOT NULL,
+  "started_at" timestamp with time zone NOT NULL,
+  "ended_at" timestamp with time zone NOT NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX idx_cluster_cost_records_cluster_id ON "cluster_cost_records"(cluster_id);
+CREATE INDEX idx_cluster_cost_records_organization_id ON "cluster_cost_records"(organization_id);
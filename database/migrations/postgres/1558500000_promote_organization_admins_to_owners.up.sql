@@ -0,0 +1 @@
+UPDATE "user_organizations" SET "role" = 'owner' WHERE "role" = 'admin';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "organization_token_policies";
+DROP TABLE IF EXISTS "token_metadata";
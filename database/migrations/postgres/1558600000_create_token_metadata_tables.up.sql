@@ -0,0 +1,20 @@
+CREATE TABLE "token_metadata" (
+  "token_id" varchar(36) NOT NULL,
+  "user_id" varchar(255) NOT NULL,
+  "scope" varchar(255),
+  "last_used_at" timestamp with time zone,
+  "last_used_ip" varchar(255),
+  "replaced_by" varchar(255),
+  "revoke_at" timestamp with time zone,
+  "created_at" timestamp with time zone,
+  PRIMARY KEY ("token_id")
+);
+
+CREATE INDEX idx_token_metadata_user_id ON "token_metadata"(user_id);
+
+CREATE TABLE "organization_token_policies" (
+  "organization_id" integer NOT NULL,
+  "max_token_age_seconds" bigint NOT NULL,
+  "updated_at" timestamp with time zone,
+  PRIMARY KEY ("organization_id")
+);
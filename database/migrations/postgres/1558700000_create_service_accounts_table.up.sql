@@ -0,0 +1,13 @@
+CREATE TABLE "service_accounts" (
+  "id" serial,
+  "organization_id" integer NOT NULL,
+  "name" varchar(50) NOT NULL,
+  "description" varchar(255),
+  "role" varchar(255) NOT NULL,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  "created_by" integer,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_service_accounts_organization_id_name ON "service_accounts"(organization_id, name);
@@ -0,0 +1,7 @@
+ALTER TABLE "audit_events" ADD COLUMN "organization_id" integer;
+
+CREATE INDEX idx_audit_events_organization_id ON "audit_events"(organization_id);
+
+UPDATE "audit_events"
+SET "organization_id" = CAST(split_part(split_part("path", '?', 1), '/', 5) AS integer)
+WHERE "path" ~ '^/api/v1/orgs/[0-9]+(/|\?|$)';
@@ -0,0 +1,15 @@
+CREATE TABLE "organization_invitations" (
+  "id" serial,
+  "organization_id" integer NOT NULL,
+  "login" varchar(255),
+  "email" varchar(255),
+  "role" varchar(255) NOT NULL,
+  "invited_by" integer,
+  "created_at" timestamp with time zone,
+  "expires_at" timestamp with time zone,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX idx_organization_invitations_organization_id ON "organization_invitations"(organization_id);
+CREATE INDEX idx_organization_invitations_login ON "organization_invitations"(login);
+CREATE INDEX idx_organization_invitations_email ON "organization_invitations"(email);
@@ -0,0 +1 @@
+ALTER TABLE "user_organizations" DROP COLUMN "group_managed";
@@ -0,0 +1 @@
+ALTER TABLE "user_organizations" ADD COLUMN "group_managed" boolean NOT NULL DEFAULT false;
@@ -0,0 +1,14 @@
+CREATE TABLE "cluster_rbac_bindings" (
+  "id" serial,
+  "cluster_id" integer NOT NULL,
+  "user_id" integer,
+  "group" varchar(255),
+  "role_kind" varchar(255) NOT NULL,
+  "role_name" varchar(255) NOT NULL,
+  "namespace" varchar(255),
+  "created_at" timestamp with time zone,
+  "created_by" integer,
+  PRIMARY KEY ("id")
+);
+
+CREATE INDEX idx_cluster_rbac_bindings_cluster_id ON "cluster_rbac_bindings"(cluster_id);
@@ -0,0 +1,7 @@
+CREATE TABLE "organization_ip_allowlists" (
+  "organization_id" integer NOT NULL,
+  "api_token_ranges" text,
+  "session_ranges" text,
+  "updated_at" timestamp with time zone,
+  PRIMARY KEY ("organization_id")
+);
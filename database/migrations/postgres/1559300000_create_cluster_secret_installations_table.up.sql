@@ -0,0 +1,20 @@
+CREATE TABLE "cluster_secret_installations" (
+  "id" serial,
+  "organization_id" integer NOT NULL,
+  "secret_id" varchar(64) NOT NULL,
+  "cluster_id" integer NOT NULL,
+  "namespace" varchar(253) NOT NULL,
+  "name" varchar(253) NOT NULL,
+  "spec" text,
+  "merge" boolean NOT NULL DEFAULT false,
+  "status" varchar(16) NOT NULL,
+  "error" text,
+  "synced_version" integer,
+  "synced_at" timestamp with time zone,
+  "created_at" timestamp with time zone,
+  "updated_at" timestamp with time zone,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_cluster_secret_installations_target ON "cluster_secret_installations"(cluster_id, namespace, name, secret_id);
+CREATE INDEX idx_cluster_secret_installations_secret ON "cluster_secret_installations"(organization_id, secret_id);
@@ -0,0 +1,11 @@
+CREATE TABLE "secret_versions" (
+  "id" serial,
+  "organization_id" integer NOT NULL,
+  "secret_id" varchar(64) NOT NULL,
+  "version" integer NOT NULL,
+  "value" text NOT NULL,
+  "created_at" timestamp with time zone,
+  PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX idx_secret_versions_version ON "secret_versions"(organization_id, secret_id, version);
@@ -78,6 +78,11 @@ func newExternalDnsServiceClientInstance() {
 	// vault kv put secret/banzaicloud/aws AWS_REGION=... AWS_ACCESS_KEY_ID=... AWS_SECRET_ACCESS_KEY=...
 	awsCredentialsPath := viper.GetString(config.AwsCredentialPath)
 
+	if secret.Store.Logical == nil {
+		log.Infoln("No AWS credentials for Route53 available, secrets are not kept in Vault")
+		return
+	}
+
 	secret, err := secret.Store.Logical.Read(awsCredentialsPath)
 	if err != nil {
 		log.Errorf("Failed to read AWS credentials from Vault: %s", err.Error())
@@ -75,6 +75,17 @@ func (s *BucketsRepository) FindOneByID(id uint) (*ClusterBackupBucketsModel, er
 	return &bucket, err
 }
 
+// FindBySecretID returns the ClusterBackupBucketsModel instances accessed with the given secret
+func (s *BucketsRepository) FindBySecretID(secretID string) (buckets []*ClusterBackupBucketsModel, err error) {
+
+	err = s.db.Where(&ClusterBackupBucketsModel{
+		OrganizationID: s.org.ID,
+		SecretID:       secretID,
+	}).Preload("Deployment").Find(&buckets).Error
+
+	return
+}
+
 // GetActiveDeploymentModel gets the active ARK deployment, if any
 func (s *BucketsRepository) GetActiveDeploymentModel(bucket *ClusterBackupBucketsModel) (
 	deployment ClusterBackupDeploymentsModel, err error) {
@@ -122,51 +122,9 @@ func (s *DeploymentsService) Deploy(bucket *ClusterBackupBucketsModel, restoreMo
 		}
 	}
 
-	clusterSecret, err := s.cluster.GetSecretWithValidation()
-	if err != nil {
-		return errors.Wrap(err, "error getting cluster secret")
-	}
-
-	bucketSecret, err := GetSecretWithValidation(bucket.SecretID, s.org.ID, bucket.Cloud)
+	config, err := s.getDeploymentConfig(bucket, restoreMode)
 	if err != nil {
-		return errors.Wrap(err, "error getting bucket secret")
-	}
-
-	var resourceGroup string
-	if s.cluster.GetCloud() == providers.Azure {
-		if m, ok := s.cluster.(api.AzureCluster); ok {
-			resourceGroup = m.GetResourceGroupName()
-		}
-	}
-
-	config, err := s.getChartConfig(ConfigRequest{
-		Cluster: clusterConfig{
-			Name:        s.cluster.GetName(),
-			Provider:    s.cluster.GetCloud(),
-			Location:    s.cluster.GetLocation(),
-			RBACEnabled: s.cluster.RbacEnabled(),
-			azureClusterConfig: azureClusterConfig{
-				ResourceGroup: resourceGroup,
-			},
-		},
-		ClusterSecret: clusterSecret,
-
-		Bucket: bucketConfig{
-			Provider: bucket.Cloud,
-			Name:     bucket.BucketName,
-			Location: bucket.Location,
-			azureBucketConfig: azureBucketConfig{
-				StorageAccount: bucket.StorageAccount,
-				ResourceGroup:  bucket.ResourceGroup,
-			},
-		},
-		BucketSecret: bucketSecret,
-
-		RestoreMode: restoreMode,
-	})
-
-	if err != nil {
-		return errors.Wrap(err, "error service getting config")
+		return err
 	}
 
 	deployment, err = s.repository.Persist(&api.PersistDeploymentRequest{
@@ -200,6 +158,49 @@ func (s *DeploymentsService) Deploy(bucket *ClusterBackupBucketsModel, restoreMo
 	return nil
 }
 
+// Redeploy upgrades the active ARK deployment with freshly generated chart values,
+// so that it picks up the current credentials of the cluster and the bucket
+func (s *DeploymentsService) Redeploy() error {
+
+	deployment, err := s.GetActiveDeployment()
+	if err != nil {
+		return errors.Wrap(err, "error getting active deployment")
+	}
+
+	bucket, err := NewBucketsRepository(s.org, s.repository.db, s.logger).FindOneByID(deployment.BucketID)
+	if err != nil {
+		return errors.Wrap(err, "error getting bucket")
+	}
+
+	config, err := s.getDeploymentConfig(bucket, deployment.RestoreMode)
+	if err != nil {
+		return err
+	}
+
+	kubeConfig, err := s.cluster.GetK8sConfig()
+	if err != nil {
+		return errors.Wrap(err, "error getting k8s config")
+	}
+
+	_, err = helm.UpgradeDeployment(
+		deployment.Name,
+		config.Chart,
+		config.Version,
+		nil,
+		config.ValueOverrides,
+		false,
+		kubeConfig,
+		helm.GenerateHelmRepoEnv(s.org.Name),
+	)
+	if err != nil {
+		err = errors.Wrap(err, "error upgrading ark")
+		s.repository.UpdateStatus(deployment, "ERROR", err.Error())
+		return err
+	}
+
+	return s.repository.UpdateStatus(deployment, "DEPLOYED", "")
+}
+
 // Remove deletes an ARK deployment
 func (s *DeploymentsService) Remove() error {
 
@@ -224,6 +225,58 @@ func (s *DeploymentsService) Remove() error {
 	return s.repository.Delete(deployment)
 }
 
+func (s *DeploymentsService) getDeploymentConfig(bucket *ClusterBackupBucketsModel, restoreMode bool) (config ChartConfig, err error) {
+
+	clusterSecret, err := s.cluster.GetSecretWithValidation()
+	if err != nil {
+		return config, errors.Wrap(err, "error getting cluster secret")
+	}
+
+	bucketSecret, err := GetSecretWithValidation(bucket.SecretID, s.org.ID, bucket.Cloud)
+	if err != nil {
+		return config, errors.Wrap(err, "error getting bucket secret")
+	}
+
+	var resourceGroup string
+	if s.cluster.GetCloud() == providers.Azure {
+		if m, ok := s.cluster.(api.AzureCluster); ok {
+			resourceGroup = m.GetResourceGroupName()
+		}
+	}
+
+	config, err = s.getChartConfig(ConfigRequest{
+		Cluster: clusterConfig{
+			Name:        s.cluster.GetName(),
+			Provider:    s.cluster.GetCloud(),
+			Location:    s.cluster.GetLocation(),
+			RBACEnabled: s.cluster.RbacEnabled(),
+			azureClusterConfig: azureClusterConfig{
+				ResourceGroup: resourceGroup,
+			},
+		},
+		ClusterSecret: clusterSecret,
+
+		Bucket: bucketConfig{
+			Provider: bucket.Cloud,
+			Name:     bucket.BucketName,
+			Location: bucket.Location,
+			azureBucketConfig: azureBucketConfig{
+				StorageAccount: bucket.StorageAccount,
+				ResourceGroup:  bucket.ResourceGroup,
+			},
+		},
+		BucketSecret: bucketSecret,
+
+		RestoreMode: restoreMode,
+	})
+
+	if err != nil {
+		return config, errors.Wrap(err, "error service getting config")
+	}
+
+	return config, nil
+}
+
 func (s *DeploymentsService) getChartConfig(req ConfigRequest) (config ChartConfig, err error) {
 
 	config = GetChartConfig()
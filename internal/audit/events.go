@@ -0,0 +1,141 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// EventFilter narrows down the audit events of an organization.
+// Zero values are ignored.
+type EventFilter struct {
+	OrganizationID uint
+	UserID         uint
+	From           time.Time
+	To             time.Time
+	Method         string
+	PathPrefix     string
+	StatusCode     int
+	CorrelationID  string
+}
+
+// Events reads audit events from the database.
+type Events struct {
+	db *gorm.DB
+}
+
+// NewEvents returns a new Events instance.
+func NewEvents(db *gorm.DB) *Events {
+	return &Events{
+		db: db,
+	}
+}
+
+// Find returns at most limit events matching the filter, newest first.
+// Events are paginated by their ID: only events older than the cursor are returned (a zero cursor means the latest events).
+func (e *Events) Find(filter EventFilter, cursor uint, limit int) ([]AuditEvent, error) {
+	query := e.query(filter)
+
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+
+	events := make([]AuditEvent, 0)
+
+	err := query.Order("id DESC").Limit(limit).Find(&events).Error
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query audit events")
+	}
+
+	return events, nil
+}
+
+// Each calls fn for every event matching the filter, newest first.
+// Events are read in batches, so arbitrarily large result sets can be exported.
+func (e *Events) Each(filter EventFilter, batchSize int, fn func(event AuditEvent) error) error {
+	var cursor uint
+
+	for {
+		events, err := e.Find(filter, cursor, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+
+		if len(events) < batchSize {
+			return nil
+		}
+
+		cursor = events[len(events)-1].ID
+	}
+}
+
+func (e *Events) query(filter EventFilter) *gorm.DB {
+	query := e.db.Model(AuditEvent{}).Where("organization_id = ?", filter.OrganizationID)
+
+	if filter.UserID > 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+
+	if !filter.From.IsZero() {
+		query = query.Where("time >= ?", filter.From)
+	}
+
+	if !filter.To.IsZero() {
+		query = query.Where("time < ?", filter.To)
+	}
+
+	if filter.Method != "" {
+		query = query.Where("method = ?", strings.ToUpper(filter.Method))
+	}
+
+	if filter.PathPrefix != "" {
+		query = query.Where("path LIKE ?", escapeLike(filter.PathPrefix)+"%")
+	}
+
+	if filter.StatusCode > 0 {
+		query = query.Where("status_code = ?", filter.StatusCode)
+	}
+
+	if filter.CorrelationID != "" {
+		query = query.Where("correlation_id = ?", filter.CorrelationID)
+	}
+
+	return query
+}
+
+// escapeLike escapes the wildcard characters of a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// DeleteBefore deletes the events older than the given time and returns the number of deleted events.
+func (e *Events) DeleteBefore(before time.Time) (int64, error) {
+	result := e.db.Where("time < ?", before).Delete(AuditEvent{})
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "failed to delete audit events")
+	}
+
+	return result.RowsAffected, nil
+}
@@ -34,10 +34,13 @@ import (
 )
 
 // LogWriter instance is a Gin Middleware which logs all request data into MySQL audit_events table.
+// Completed events are also sent to the sink (if any).
+// Events are not stored in the database if db is nil.
 func LogWriter(
 	skipPaths []string,
 	whitelistedHeaders []string,
 	db *gorm.DB,
+	sink Sink,
 	logger logrus.FieldLogger,
 ) gin.HandlerFunc {
 	skip := map[string]struct{}{}
@@ -164,11 +167,13 @@ func LogWriter(
 			Headers:       string(headers),
 		}
 
-		if err := db.Save(&event).Error; err != nil {
-			c.AbortWithError(http.StatusInternalServerError, err)
-			logger.Errorf("audit: failed to write request to db: %v", err)
+		if db != nil {
+			if err := db.Save(&event).Error; err != nil {
+				c.AbortWithError(http.StatusInternalServerError, err)
+				logger.Errorf("audit: failed to write request to db: %v", err)
 
-			return
+				return
+			}
 		}
 
 		c.Next() // process request
@@ -185,6 +190,11 @@ func LogWriter(
 			ResponseTime: int(time.Since(start).Nanoseconds() / 1000 / 1000), // ms
 		}
 
+		// The organization is only known after the request has been processed by the organization middleware
+		if organization := auth.GetCurrentOrganization(c.Request); organization != nil {
+			responseEvent.OrganizationID = organization.ID
+		}
+
 		if c.IsAborted() {
 			if marshalled, err := json.Marshal(c.Errors); err != nil {
 				logger.Errorf("audit: failed to marshal c.Errors: %v", err)
@@ -194,8 +204,23 @@ func LogWriter(
 			}
 		}
 
-		if err := db.Model(&event).Updates(responseEvent).Error; err != nil {
-			logger.Errorf("audit: failed to write response details: %v", err)
+		if db != nil {
+			if err := db.Model(&event).Updates(responseEvent).Error; err != nil {
+				logger.Errorf("audit: failed to write response details: %v", err)
+			}
+		}
+
+		if sink != nil {
+			event.UserID = responseEvent.UserID
+			event.OrganizationID = responseEvent.OrganizationID
+			event.StatusCode = responseEvent.StatusCode
+			event.ResponseSize = responseEvent.ResponseSize
+			event.ResponseTime = responseEvent.ResponseTime
+			event.Errors = responseEvent.Errors
+
+			if err := sink.Write(event); err != nil {
+				logger.Errorf("audit: failed to send event to sink: %v", err)
+			}
 		}
 	}
 }
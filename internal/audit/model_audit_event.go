@@ -25,20 +25,21 @@ const (
 
 // AuditEvent holds all information related to a user interaction.
 type AuditEvent struct {
-	ID            uint      `gorm:"primary_key"`
-	Time          time.Time `gorm:"index"`
-	CorrelationID string    `gorm:"size:36"`
-	ClientIP      string    `gorm:"size:45"`
-	UserAgent     string
-	Path          string `gorm:"size:8000"`
-	Method        string `gorm:"size:7"`
-	UserID        uint
-	StatusCode    int
-	Body          *string `gorm:"type:json"`
-	Headers       string  `gorm:"type:json"`
-	ResponseTime  int
-	ResponseSize  int
-	Errors        *string `gorm:"type:json"`
+	ID             uint      `gorm:"primary_key" json:"id"`
+	Time           time.Time `gorm:"index" json:"time"`
+	CorrelationID  string    `gorm:"size:36" json:"correlationId"`
+	ClientIP       string    `gorm:"size:45" json:"clientIp"`
+	UserAgent      string    `json:"userAgent"`
+	Path           string    `gorm:"size:8000" json:"path"`
+	Method         string    `gorm:"size:7" json:"method"`
+	UserID         uint      `json:"userId"`
+	OrganizationID uint      `gorm:"index" json:"organizationId,omitempty"`
+	StatusCode     int       `json:"statusCode"`
+	Body           *string   `gorm:"type:json" json:"body,omitempty"`
+	Headers        string    `gorm:"type:json" json:"headers"`
+	ResponseTime   int       `json:"responseTime"`
+	ResponseSize   int       `json:"responseSize"`
+	Errors         *string   `gorm:"type:json" json:"errors,omitempty"`
 }
 
 // TableName specifies a database table name for the model.
@@ -0,0 +1,102 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+)
+
+type eventPruner interface {
+	// DeleteBefore deletes the events older than the given time.
+	DeleteBefore(before time.Time) (int64, error)
+}
+
+// RetentionController periodically deletes the audit events stored in the database longer than the retention period.
+type RetentionController struct {
+	events    eventPruner
+	retention time.Duration
+	interval  time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+// NewRetentionController returns a new RetentionController instance.
+func NewRetentionController(
+	events eventPruner,
+	retention time.Duration,
+	interval time.Duration,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *RetentionController {
+	return &RetentionController{
+		events:       events,
+		retention:    retention,
+		interval:     interval,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+		logger:       logger,
+		errorHandler: errorHandler,
+	}
+}
+
+func (c *RetentionController) Start() error {
+	c.logger.WithField("retention", c.retention).Info("starting audit event retention controller")
+
+	go c.run()
+
+	return nil
+}
+
+func (c *RetentionController) Stop() {
+	c.logger.Info("shutting audit event retention controller")
+	close(c.stop)
+	<-c.done
+}
+
+func (c *RetentionController) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.prune()
+
+		select {
+		case <-ticker.C:
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+func (c *RetentionController) prune() {
+	deleted, err := c.events.DeleteBefore(time.Now().Add(-c.retention))
+	if err != nil {
+		c.errorHandler.Handle(emperror.Wrap(err, "failed to prune audit events"))
+		return
+	}
+
+	if deleted > 0 {
+		c.logger.WithField("deleted", deleted).Info("pruned expired audit events")
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+)
+
+// Sink receives every audit event after the request has been processed.
+type Sink interface {
+	// Write sends an event to the sink.
+	Write(event AuditEvent) error
+}
+
+// flusher is implemented by sinks buffering events.
+type flusher interface {
+	// Flush sends the buffered events which are due.
+	Flush() error
+}
+
+// SinkDispatcher fans out audit events to sinks in the background, so slow sinks do not delay requests.
+// Every sink has its own queue, so a slow sink (eg. a retrying webhook) does not delay the others.
+// Events are dropped when the queue of a sink is full.
+type SinkDispatcher struct {
+	queues []*sinkQueue
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+
+	logger logrus.FieldLogger
+}
+
+// NewSinkDispatcher returns a new SinkDispatcher instance.
+func NewSinkDispatcher(
+	sinks []Sink,
+	bufferSize int,
+	flushInterval time.Duration,
+	logger logrus.FieldLogger,
+	errorHandler emperror.Handler,
+) *SinkDispatcher {
+	queues := make([]*sinkQueue, 0, len(sinks))
+	for _, sink := range sinks {
+		queues = append(queues, &sinkQueue{
+			sink:          sink,
+			events:        make(chan AuditEvent, bufferSize),
+			flushInterval: flushInterval,
+			logger:        logger.WithField("sink", fmt.Sprintf("%T", sink)),
+			errorHandler:  errorHandler,
+		})
+	}
+
+	return &SinkDispatcher{
+		queues: queues,
+		stop:   make(chan struct{}),
+		logger: logger,
+	}
+}
+
+// Write queues an event for the sinks.
+func (d *SinkDispatcher) Write(event AuditEvent) error {
+	for _, queue := range d.queues {
+		queue.write(event)
+	}
+
+	return nil
+}
+
+func (d *SinkDispatcher) Start() error {
+	d.logger.Info("starting audit sink dispatcher")
+
+	for _, queue := range d.queues {
+		d.wg.Add(1)
+
+		go func(queue *sinkQueue) {
+			defer d.wg.Done()
+
+			queue.run(d.stop)
+		}(queue)
+	}
+
+	return nil
+}
+
+// Stop sends the queued events, flushes and closes the sinks.
+func (d *SinkDispatcher) Stop() {
+	d.logger.Info("shutting audit sink dispatcher")
+	close(d.stop)
+	d.wg.Wait()
+}
+
+// sinkQueue sends the queued events to a single sink.
+type sinkQueue struct {
+	sink   Sink
+	events chan AuditEvent
+
+	// flushInterval is how often buffering sinks are flushed
+	flushInterval time.Duration
+
+	logger       logrus.FieldLogger
+	errorHandler emperror.Handler
+}
+
+func (q *sinkQueue) write(event AuditEvent) {
+	select {
+	case q.events <- event:
+	default:
+		q.logger.WithField("event", event.ID).Warn("audit: sink buffer is full, dropping event")
+	}
+}
+
+func (q *sinkQueue) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(q.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case event := <-q.events:
+			q.dispatch(event)
+
+		case <-ticker.C:
+			q.flush()
+
+		case <-stop:
+			for {
+				select {
+				case event := <-q.events:
+					q.dispatch(event)
+
+				default:
+					q.close()
+
+					return
+				}
+			}
+		}
+	}
+}
+
+func (q *sinkQueue) dispatch(event AuditEvent) {
+	if err := q.sink.Write(event); err != nil {
+		q.errorHandler.Handle(emperror.WrapWith(err, "failed to send audit event to sink", "event", event.ID))
+	}
+}
+
+func (q *sinkQueue) flush() {
+	if sink, ok := q.sink.(flusher); ok {
+		if err := sink.Flush(); err != nil {
+			q.errorHandler.Handle(emperror.Wrap(err, "failed to flush audit sink"))
+		}
+	}
+}
+
+func (q *sinkQueue) close() {
+	if sink, ok := q.sink.(io.Closer); ok {
+		if err := sink.Close(); err != nil {
+			q.errorHandler.Handle(emperror.Wrap(err, "failed to close audit sink"))
+		}
+	}
+}
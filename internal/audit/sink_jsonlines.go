@@ -0,0 +1,82 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"log/syslog"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// JSONLinesSink writes audit events to a writer as JSON, one event per line.
+type JSONLinesSink struct {
+	writer io.WriteCloser
+	mu     sync.Mutex
+}
+
+// NewJSONLinesSink returns a new JSONLinesSink instance.
+func NewJSONLinesSink(writer io.WriteCloser) *JSONLinesSink {
+	return &JSONLinesSink{
+		writer: writer,
+	}
+}
+
+// NewFileSink returns a sink appending audit events to a local file.
+func NewFileSink(path string) (*JSONLinesSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open audit log file")
+	}
+
+	return NewJSONLinesSink(file), nil
+}
+
+// NewSyslogSink returns a sink sending audit events to syslog.
+// An empty network and address connects to the local syslog server.
+func NewSyslogSink(network string, address string, tag string) (*JSONLinesSink, error) {
+	writer, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_AUTH, tag)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to connect to syslog")
+	}
+
+	return NewJSONLinesSink(writer), nil
+}
+
+// Write writes an event as a single line.
+func (s *JSONLinesSink) Write(event AuditEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit event")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err = s.writer.Write(append(line, '\n'))
+
+	return errors.Wrap(err, "failed to write audit event")
+}
+
+// Close closes the underlying writer.
+func (s *JSONLinesSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.writer.Close()
+}
@@ -0,0 +1,118 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/banzaicloud/pipeline/pkg/objectstore"
+)
+
+// ObjectStoreSink collects audit events into hourly batches and uploads them to an object store bucket.
+// Batches are stored as newline delimited JSON under <prefix>/<year>/<month>/<day>/<hour>-<upload time>.ndjson keys.
+type ObjectStoreSink struct {
+	store  objectstore.ObjectStore
+	bucket string
+	prefix string
+
+	mu         sync.Mutex
+	batch      bytes.Buffer
+	batchStart time.Time
+}
+
+// NewObjectStoreSink returns a new ObjectStoreSink instance.
+func NewObjectStoreSink(store objectstore.ObjectStore, bucket string, prefix string) *ObjectStoreSink {
+	return &ObjectStoreSink{
+		store:  store,
+		bucket: bucket,
+		prefix: prefix,
+	}
+}
+
+// Write adds an event to the batch of its hour.
+// The previous batch is uploaded when the first event of a new hour arrives.
+func (s *ObjectStoreSink) Write(event AuditEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit event")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hour := event.Time.UTC().Truncate(time.Hour)
+
+	if s.batch.Len() > 0 && !hour.Equal(s.batchStart) {
+		if err := s.upload(); err != nil {
+			return err
+		}
+	}
+
+	if s.batch.Len() == 0 {
+		s.batchStart = hour
+	}
+
+	s.batch.Write(line)
+	s.batch.WriteByte('\n')
+
+	return nil
+}
+
+// Flush uploads the current batch if its hour is over.
+func (s *ObjectStoreSink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.batch.Len() == 0 || time.Now().UTC().Truncate(time.Hour).Equal(s.batchStart) {
+		return nil
+	}
+
+	return s.upload()
+}
+
+// Close uploads the current batch.
+func (s *ObjectStoreSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.batch.Len() == 0 {
+		return nil
+	}
+
+	return s.upload()
+}
+
+func (s *ObjectStoreSink) upload() error {
+	key := path.Join(
+		s.prefix,
+		s.batchStart.Format("2006/01/02"),
+		fmt.Sprintf("%s-%d.ndjson", s.batchStart.Format("15"), time.Now().UnixNano()),
+	)
+
+	if err := s.store.PutObject(s.bucket, key, bytes.NewReader(s.batch.Bytes())); err != nil {
+		return errors.Wrapf(err, "failed to upload audit events to %s/%s", s.bucket, key)
+	}
+
+	s.batch.Reset()
+
+	return nil
+}
@@ -0,0 +1,107 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/pkg/objectstore"
+)
+
+type objectStoreStub struct {
+	objectstore.ObjectStore
+
+	objects map[string]string
+	keys    []string
+}
+
+func (s *objectStoreStub) PutObject(bucketName string, key string, body io.Reader) error {
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if s.objects == nil {
+		s.objects = make(map[string]string)
+	}
+
+	s.objects[bucketName+"/"+key] = string(content)
+	s.keys = append(s.keys, bucketName+"/"+key)
+
+	return nil
+}
+
+func (s *objectStoreStub) eventIDs(t *testing.T, key string) []uint {
+	var ids []uint
+
+	for _, line := range strings.Split(strings.TrimSuffix(s.objects[key], "\n"), "\n") {
+		var event AuditEvent
+		require.NoError(t, json.Unmarshal([]byte(line), &event))
+
+		ids = append(ids, event.ID)
+	}
+
+	return ids
+}
+
+func TestObjectStoreSink_HourlyBatches(t *testing.T) {
+	store := &objectStoreStub{}
+	sink := NewObjectStoreSink(store, "bucket", "audit")
+
+	hour := time.Date(2019, 5, 6, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, sink.Write(AuditEvent{ID: 1, Time: hour.Add(5 * time.Minute)}))
+	require.NoError(t, sink.Write(AuditEvent{ID: 2, Time: hour.Add(55 * time.Minute)}))
+	assert.Empty(t, store.keys, "the batch of the current hour should not be uploaded")
+
+	// the first event of the next hour uploads the previous batch
+	require.NoError(t, sink.Write(AuditEvent{ID: 3, Time: hour.Add(65 * time.Minute)}))
+	require.Len(t, store.keys, 1)
+	assert.True(t, strings.HasPrefix(store.keys[0], "bucket/audit/2019/05/06/10-"), store.keys[0])
+	assert.True(t, strings.HasSuffix(store.keys[0], ".ndjson"), store.keys[0])
+	assert.Equal(t, []uint{1, 2}, store.eventIDs(t, store.keys[0]))
+
+	// the batch of a past hour is uploaded on flush
+	require.NoError(t, sink.Flush())
+	require.Len(t, store.keys, 2)
+	assert.True(t, strings.HasPrefix(store.keys[1], "bucket/audit/2019/05/06/11-"), store.keys[1])
+	assert.Equal(t, []uint{3}, store.eventIDs(t, store.keys[1]))
+
+	// nothing left to upload
+	require.NoError(t, sink.Flush())
+	require.NoError(t, sink.Close())
+	assert.Len(t, store.keys, 2)
+}
+
+func TestObjectStoreSink_FlushKeepsCurrentHour(t *testing.T) {
+	store := &objectStoreStub{}
+	sink := NewObjectStoreSink(store, "bucket", "audit")
+
+	require.NoError(t, sink.Write(AuditEvent{ID: 1, Time: time.Now()}))
+	require.NoError(t, sink.Flush())
+	assert.Empty(t, store.keys, "the batch of the current hour should not be uploaded on flush")
+
+	require.NoError(t, sink.Close())
+	require.Len(t, store.keys, 1)
+	assert.Equal(t, []uint{1}, store.eventIDs(t, store.keys[0]))
+}
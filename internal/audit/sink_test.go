@@ -0,0 +1,91 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type blockingSink struct {
+	release chan struct{}
+}
+
+func (s *blockingSink) Write(event AuditEvent) error {
+	<-s.release
+
+	return nil
+}
+
+type recordingSink struct {
+	mu     sync.Mutex
+	events []AuditEvent
+	closed bool
+}
+
+func (s *recordingSink) Write(event AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+
+	return nil
+}
+
+func (s *recordingSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+
+	return nil
+}
+
+func (s *recordingSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.events)
+}
+
+func TestSinkDispatcher_SlowSinkDoesNotBlockOthers(t *testing.T) {
+	slow := &blockingSink{release: make(chan struct{})}
+	fast := &recordingSink{}
+
+	dispatcher := NewSinkDispatcher([]Sink{slow, fast}, 10, time.Hour, logrus.New(), emperror.NewNoopHandler())
+	require.NoError(t, dispatcher.Start())
+
+	for i := 1; i <= 3; i++ {
+		require.NoError(t, dispatcher.Write(AuditEvent{ID: uint(i)}))
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for fast.count() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Equal(t, 3, fast.count())
+
+	close(slow.release)
+	dispatcher.Stop()
+
+	assert.True(t, fast.closed)
+}
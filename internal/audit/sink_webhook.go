@@ -0,0 +1,90 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+
+	"github.com/banzaicloud/pipeline/internal/backoff"
+)
+
+// WebhookSink posts audit events as JSON to an HTTP endpoint.
+// Failed requests are retried, except when the endpoint rejects the event with a client error.
+type WebhookSink struct {
+	url     string
+	headers map[string]string
+
+	client        *http.Client
+	backoffConfig backoff.ConstantBackoffConfig
+}
+
+// NewWebhookSink returns a new WebhookSink instance.
+func NewWebhookSink(url string, headers map[string]string, client *http.Client, backoffConfig backoff.ConstantBackoffConfig) *WebhookSink {
+	return &WebhookSink{
+		url:           url,
+		headers:       headers,
+		client:        client,
+		backoffConfig: backoffConfig,
+	}
+}
+
+// Write posts an event to the webhook.
+func (s *WebhookSink) Write(event AuditEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit event")
+	}
+
+	return backoff.Retry(func() error {
+		return s.post(body)
+	}, backoff.NewConstantBackoffPolicy(&s.backoffConfig))
+}
+
+func (s *WebhookSink) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return backoff.MarkErrorPermanent(errors.Wrap(err, "failed to create webhook request"))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range s.headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send audit event to webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = emperror.With(fmt.Errorf("unexpected webhook response status: %s", resp.Status), "statusCode", resp.StatusCode)
+
+	// client errors will not go away with retrying
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return backoff.MarkErrorPermanent(err)
+	}
+
+	return err
+}
@@ -0,0 +1,98 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/backoff"
+)
+
+func TestWebhookSink_Write(t *testing.T) {
+	tests := map[string]struct {
+		statuses      []int
+		expectedCalls int32
+		expectedError bool
+	}{
+		"success": {
+			statuses:      []int{http.StatusOK},
+			expectedCalls: 1,
+		},
+		"retried server error": {
+			statuses:      []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK},
+			expectedCalls: 3,
+		},
+		"retried rate limit": {
+			statuses:      []int{http.StatusTooManyRequests, http.StatusNoContent},
+			expectedCalls: 2,
+		},
+		"client error is not retried": {
+			statuses:      []int{http.StatusBadRequest, http.StatusOK},
+			expectedCalls: 1,
+			expectedError: true,
+		},
+		"retries exhausted": {
+			statuses:      []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable},
+			expectedCalls: 3,
+			expectedError: true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			var calls int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := atomic.AddInt32(&calls, 1)
+
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "secret", r.Header.Get("X-Token"))
+
+				var event AuditEvent
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+				assert.Equal(t, uint(1), event.ID)
+
+				w.WriteHeader(test.statuses[call-1])
+			}))
+			defer server.Close()
+
+			sink := NewWebhookSink(
+				server.URL,
+				map[string]string{"X-Token": "secret"},
+				server.Client(),
+				backoff.ConstantBackoffConfig{Delay: time.Millisecond, MaxRetries: 2},
+			)
+
+			err := sink.Write(AuditEvent{ID: 1})
+			if test.expectedError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.expectedCalls, atomic.LoadInt32(&calls))
+		})
+	}
+}
@@ -14,47 +14,84 @@
 
 package auth
 
-// AccessManager is responsible for managing authorization rules.
-// NOTE:
-// Currently this implementation is a dummy one, and is a placeholder for a future implementation.
-// It hasn't been removed to mark the places where it should been called.
+import (
+	"strconv"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+
+	"github.com/banzaicloud/pipeline/auth"
+)
+
+// AccessManager is responsible for managing the organization memberships the Enforcer authorizes requests with.
+// The access level of a member is decided by its role in the organization (see auth.UserOrganization).
 type AccessManager struct {
-	enforcer Enforcer
-	basePath string
+	db *gorm.DB
 }
 
 // NewAccessManager returns a new AccessManager instance.
-func NewAccessManager(enforcer Enforcer, basePath string) *AccessManager {
+func NewAccessManager(db *gorm.DB) *AccessManager {
 	return &AccessManager{
-		enforcer: enforcer,
-		basePath: basePath,
+		db: db,
 	}
 }
 
-// AddDefaultPolicies adds default policy rules to the underlying access manager.
-func (m *AccessManager) AddDefaultPolicies() {
-}
+// GrantOrganizationAccessToUser makes a user member of an organization.
+// Existing memberships keep their role, new ones get the least privileged role.
+// Virtual users (eg. clusters) get access through their tokens, so they are skipped.
+func (m *AccessManager) GrantOrganizationAccessToUser(userID string, orgID uint) error {
+	id, ok := parseUserID(userID)
+	if !ok {
+		return nil
+	}
 
-// GrantDefaultAccessToUser adds all the default non-org-specific role to a user.
-func (m *AccessManager) GrantDefaultAccessToUser(userID string) {
-}
+	err := m.db.
+		Where(auth.UserOrganization{UserID: id, OrganizationID: orgID}).
+		Attrs(auth.UserOrganization{Role: auth.RoleViewer}).
+		FirstOrCreate(&auth.UserOrganization{}).Error
+	if err != nil {
+		return emperror.WrapWith(err, "failed to grant organization access to user", "user", userID, "organization", orgID)
+	}
 
-// GrantDefaultAccessToVirtualUser adds org list role to a virtual user.
-func (m *AccessManager) GrantDefaultAccessToVirtualUser(userID string) {
+	return nil
 }
 
-// AddOrganizationPolicies creates an organization role, by adding the default (*) org policies for the given organization.
-func (m *AccessManager) AddOrganizationPolicies(orgID uint) {
-}
+// RevokeOrganizationAccessFromUser removes a user from an organization.
+func (m *AccessManager) RevokeOrganizationAccessFromUser(userID string, orgID uint) error {
+	id, ok := parseUserID(userID)
+	if !ok {
+		return nil
+	}
 
-// GrantOrganizationAccessToUser adds a user to an organization by adding the associated organization role.
-func (m *AccessManager) GrantOrganizationAccessToUser(userID string, orgID uint) {
+	err := m.db.Where(auth.UserOrganization{UserID: id, OrganizationID: orgID}).Delete(auth.UserOrganization{}).Error
+	if err != nil {
+		return emperror.WrapWith(err, "failed to revoke organization access from user", "user", userID, "organization", orgID)
+	}
+
+	return nil
 }
 
-// RevokeOrganizationAccessFromUser removes a user from an organization by removing the associated organization role.
-func (m *AccessManager) RevokeOrganizationAccessFromUser(userID string, orgID uint) {
+// RevokeAllAccessFromUser removes a user from all of its organizations.
+func (m *AccessManager) RevokeAllAccessFromUser(userID string) error {
+	id, ok := parseUserID(userID)
+	if !ok {
+		return nil
+	}
+
+	err := m.db.Where(auth.UserOrganization{UserID: id}).Delete(auth.UserOrganization{}).Error
+	if err != nil {
+		return emperror.WrapWith(err, "failed to revoke all access from user", "user", userID)
+	}
+
+	return nil
 }
 
-// RevokeAllAccessFromUser removes all roles for a given user.
-func (m *AccessManager) RevokeAllAccessFromUser(userID string) {
+// parseUserID returns the numeric ID of a user, virtual users have none.
+func parseUserID(userID string) (uint, bool) {
+	id, err := strconv.ParseUint(userID, 10, 32)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+
+	return uint(id), true
 }
@@ -17,11 +17,13 @@ package auth
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/banzaicloud/pipeline/auth"
 	"github.com/jinzhu/gorm"
 	_ "github.com/jinzhu/gorm/dialects/sqlite"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func newOrg(t *testing.T, db *gorm.DB, id uint, name string) *auth.Organization {
@@ -49,18 +51,12 @@ func addUserToOrg(t *testing.T, db *gorm.DB, user *auth.User, org *auth.Organiza
 	}
 }
 
-func TestAccessManager_DefaultPolicies(t *testing.T) {
+func TestEnforcer_DefaultPolicies(t *testing.T) {
 	db, err := gorm.Open("sqlite3", "file::memory:")
 	if err != nil {
 		t.Fatal(err)
 	}
 	enforcer := NewEnforcer(db)
-	accessManager := NewAccessManager(enforcer, "")
-
-	accessManager.AddDefaultPolicies()
-
-	accessManager.GrantDefaultAccessToUser("user")
-	accessManager.GrantDefaultAccessToVirtualUser("userVirtual")
 
 	user1 := newUser(t, db, 1, "user1")
 	user2 := newUser(t, db, 2, "user2")
@@ -153,3 +149,106 @@ func TestAccessManager_DefaultPolicies(t *testing.T) {
 		})
 	}
 }
+
+func TestAccessManager_OrganizationAccess(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	accessManager := NewAccessManager(db)
+
+	user := newUser(t, db, 1, "user1")
+	org1 := newOrg(t, db, 1, "org1")
+	org2 := newOrg(t, db, 2, "org2")
+
+	require.NoError(t, db.AutoMigrate(auth.UserOrganization{}).Error)
+	require.NoError(t, db.Create(&auth.UserOrganization{UserID: user.ID, OrganizationID: org1.ID, Role: auth.RoleOwner}).Error)
+
+	require.NoError(t, accessManager.GrantOrganizationAccessToUser(user.IDString(), org1.ID))
+	require.NoError(t, accessManager.GrantOrganizationAccessToUser(user.IDString(), org2.ID))
+
+	// virtual users have no memberships
+	require.NoError(t, accessManager.GrantOrganizationAccessToUser("clusters/1/1", org1.ID))
+
+	role, err := auth.GetUserRole(db, user.ID, org1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleOwner, role, "existing memberships should keep their role")
+
+	role, err = auth.GetUserRole(db, user.ID, org2.ID)
+	require.NoError(t, err)
+	assert.Equal(t, auth.RoleViewer, role, "new memberships should get the least privileged role")
+
+	require.NoError(t, accessManager.RevokeOrganizationAccessFromUser(user.IDString(), org1.ID))
+
+	role, err = auth.GetUserRole(db, user.ID, org1.ID)
+	require.NoError(t, err)
+	assert.Empty(t, role)
+
+	require.NoError(t, accessManager.RevokeAllAccessFromUser(user.IDString()))
+
+	var count int
+	require.NoError(t, db.Model(&auth.UserOrganization{}).Where(auth.UserOrganization{UserID: user.ID}).Count(&count).Error)
+	assert.Equal(t, 0, count)
+}
+
+func TestEnforcer_VirtualUser(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	enforcer := NewEnforcer(db)
+
+	org := &auth.Organization{ID: 1, Name: "org"}
+	hook := &auth.User{Login: "org/hook", Virtual: true}
+
+	tests := map[string]struct {
+		org      *auth.Organization
+		user     *auth.User
+		path     string
+		method   string
+		expected bool
+	}{
+		"reads clusters":          {org, hook, "/api/v1/orgs/1/clusters", http.MethodGet, true},
+		"creates cluster":         {org, hook, "/api/v1/orgs/1/clusters", http.MethodPost, true},
+		"deletes organization":    {org, hook, "/api/v1/orgs/1", http.MethodDelete, false},
+		"adds member":             {org, hook, "/api/v1/orgs/1/users/2", http.MethodPost, false},
+		"creates service account": {org, hook, "/api/v1/orgs/1/serviceaccounts", http.MethodPost, false},
+		"other organization":      {&auth.Organization{ID: 2, Name: "other"}, hook, "/api/v1/orgs/2/clusters", http.MethodGet, false},
+		"not a virtual user":      {org, &auth.User{Login: "org/hook"}, "/api/v1/orgs/1/clusters", http.MethodGet, false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			allowed, err := enforcer.Enforce(test.org, test.user, test.path, test.method)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, allowed)
+		})
+	}
+}
+
+func TestEnforcer_VirtualUserTokenPolicy(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.AutoMigrate(auth.OrganizationTokenPolicy{}).Error)
+	require.NoError(t, db.Create(&auth.OrganizationTokenPolicy{OrganizationID: 1, MaxTokenAgeSeconds: 3600}).Error)
+
+	enforcer := NewEnforcer(db)
+
+	org := &auth.Organization{ID: 1, Name: "org"}
+
+	young := &auth.User{Login: "org/hook", Virtual: true, TokenIssuedAt: time.Now().Add(-time.Minute)}
+	old := &auth.User{Login: "org/hook", Virtual: true, TokenIssuedAt: time.Now().Add(-2 * time.Hour)}
+
+	allowed, err := enforcer.Enforce(org, young, "/api/v1/orgs/1/clusters", http.MethodGet)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = enforcer.Enforce(org, old, "/api/v1/orgs/1/clusters", http.MethodGet)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
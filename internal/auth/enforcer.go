@@ -17,14 +17,29 @@ package auth
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/banzaicloud/pipeline/auth"
 	"github.com/goph/emperror"
 	"github.com/jinzhu/gorm"
 )
 
+// virtualUserRole is the organization role of virtual users (CI/CD hooks).
+const virtualUserRole = auth.RoleMember
+
 type basicEnforcer struct {
-	db *gorm.DB
+	db     *gorm.DB
+	policy []policyRule
+}
+
+// EnforcerOption configures an Enforcer.
+type EnforcerOption func(e *basicEnforcer)
+
+// RestrictAdminKubeconfig allows only organization admins to download the admin kubeconfig of clusters.
+func RestrictAdminKubeconfig() EnforcerOption {
+	return func(e *basicEnforcer) {
+		e.policy = append(adminKubeconfigPolicy, e.policy...)
+	}
 }
 
 func (e *basicEnforcer) Enforce(org *auth.Organization, user *auth.User, path, method string) (bool, error) {
@@ -32,12 +47,20 @@ func (e *basicEnforcer) Enforce(org *auth.Organization, user *auth.User, path, m
 		return false, nil
 	}
 
+	if user.TokenScope != nil && !scopeAllows(user.TokenScope, org, path, method) {
+		return false, nil
+	}
+
+	if serviceAccountID, ok := user.ServiceAccountID(); ok {
+		return e.enforceServiceAccount(serviceAccountID, org, user, path, method)
+	}
+
 	if org == nil {
 		return true, nil
 	}
 
 	if user.ID == 0 {
-		if strings.HasPrefix(user.Login, "clusters/") {
+		if user.TokenType == auth.ClusterTokenType && strings.HasPrefix(user.Login, "clusters/") {
 			segments := strings.Split(user.Login, "/")
 			if len(segments) < 2 {
 				return false, nil
@@ -51,23 +74,85 @@ func (e *basicEnforcer) Enforce(org *auth.Organization, user *auth.User, path, m
 			return org.ID == uint(orgID), nil
 		}
 
-		orgName := auth.GetOrgNameFromVirtualUser(user.Login)
-		return org.Name == orgName, nil
+		if !user.Virtual || org.Name != auth.GetOrgNameFromVirtualUser(user.Login) {
+			return false, nil
+		}
+
+		allowed, err := e.tokenAllowed(org, user)
+		if err != nil || !allowed {
+			return false, err
+		}
+
+		return roleAllows(e.policy, virtualUserRole, path, method), nil
 	}
 
-	err := e.db.Model(user).Where(org).Related(org, "Organizations").Error
+	allowed, err := e.tokenAllowed(org, user)
+	if err != nil || !allowed {
+		return false, err
+	}
 
+	role, err := auth.GetUserRole(e.db, user.ID, org.ID)
 	if err != nil {
-		if gorm.IsRecordNotFoundError(err) {
-			return false, nil
-		}
 		return false, emperror.Wrap(err, "failed to query user's organizations from db")
 	}
 
-	return true, nil
+	// not a member of the organization
+	if role == "" {
+		return false, nil
+	}
+
+	return roleAllows(e.policy, role, path, method), nil
+}
+
+// enforceServiceAccount checks the role of a service account in its organization.
+// Outside of organizations service accounts can only read.
+func (e *basicEnforcer) enforceServiceAccount(id uint, org *auth.Organization, user *auth.User, path, method string) (bool, error) {
+	if org == nil {
+		return isReadMethod(method), nil
+	}
+
+	serviceAccount, err := auth.GetServiceAccount(e.db, id)
+	if err != nil {
+		return false, emperror.Wrap(err, "failed to query service account from db")
+	}
+
+	if serviceAccount == nil || serviceAccount.OrganizationID != org.ID {
+		return false, nil
+	}
+
+	allowed, err := e.tokenAllowed(org, user)
+	if err != nil || !allowed {
+		return false, err
+	}
+
+	return roleAllows(e.policy, serviceAccount.Role, path, method), nil
+}
+
+// tokenAllowed checks the token of the user against the token policy of the organization.
+// Cluster technical users are not checked: their tokens are reused for provisioning nodes for the lifetime of the cluster.
+func (e *basicEnforcer) tokenAllowed(org *auth.Organization, user *auth.User) (bool, error) {
+	if user.TokenIssuedAt.IsZero() {
+		return true, nil
+	}
+
+	policy, err := auth.GetOrganizationTokenPolicy(e.db, org.ID)
+	if err != nil {
+		return false, emperror.Wrap(err, "failed to query organization token policy")
+	}
+
+	return policy.Allows(user.TokenIssuedAt, time.Now()), nil
 }
 
 // NewEnforcer returns a new enforcer.
-func NewEnforcer(db *gorm.DB) Enforcer {
-	return &basicEnforcer{db: db}
+func NewEnforcer(db *gorm.DB, options ...EnforcerOption) Enforcer {
+	enforcer := &basicEnforcer{
+		db:     db,
+		policy: organizationPolicy,
+	}
+
+	for _, option := range options {
+		option(enforcer)
+	}
+
+	return enforcer
 }
@@ -0,0 +1,81 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/banzaicloud/pipeline/auth"
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// NewIPAllowlistMiddleware returns a new gin middleware that rejects requests coming from outside
+// the network ranges allowed by the current organization.
+// Rejected requests are aborted with an error, so that they are recorded in the audit log.
+// Cluster technical users are not restricted,
+// service accounts and virtual users (CI/CD hooks) are checked against the API token ranges.
+func NewIPAllowlistMiddleware(db *gorm.DB, logger logrus.FieldLogger, errorHandler emperror.Handler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		org := auth.GetCurrentOrganization(c.Request)
+		user := auth.GetCurrentUser(c.Request)
+
+		if org == nil || user == nil {
+			return
+		}
+
+		if user.ID == 0 && user.TokenType == auth.ClusterTokenType {
+			return
+		}
+
+		allowlist, err := auth.GetOrganizationIPAllowlist(db, org.ID)
+		if err != nil {
+			err = emperror.Wrap(err, "failed to check IP allowlist for request")
+			errorHandler.Handle(err)
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		clientIP := c.ClientIP()
+		session := user.ID != 0 && auth.IsSessionRequest(c.Request)
+
+		if allowlist.Allows(net.ParseIP(clientIP), session) {
+			return
+		}
+
+		logger.WithFields(logrus.Fields{
+			"organization": org.ID,
+			"user":         user.ID,
+			"login":        user.Login,
+			"clientIP":     clientIP,
+			"session":      session,
+		}).Warn("request rejected by organization IP allowlist")
+
+		message := fmt.Sprintf("client address %s is not allowed by the organization", clientIP)
+
+		_ = c.Error(errors.New(message))
+		c.AbortWithStatusJSON(http.StatusForbidden, pkgCommon.ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: message,
+			Error:   "IP address not allowed",
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/banzaicloud/pipeline/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	qorauth "github.com/qor/auth"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIPAllowlistMiddleware(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.AutoMigrate(auth.OrganizationIPAllowlist{}).Error)
+	require.NoError(t, db.Create(&auth.OrganizationIPAllowlist{OrganizationID: 1, APITokenRanges: "10.0.0.0/8"}).Error)
+
+	tests := map[string]struct {
+		user         *auth.User
+		remoteAddr   string
+		expectedCode int
+	}{
+		"user inside range":             {&auth.User{ID: 1}, "10.1.2.3:1234", http.StatusOK},
+		"user outside range":            {&auth.User{ID: 1}, "172.16.0.1:1234", http.StatusForbidden},
+		"service account inside range":  {&auth.User{Login: "serviceaccounts/12", TokenType: auth.ServiceAccountTokenType}, "10.1.2.3:1234", http.StatusOK},
+		"service account outside range": {&auth.User{Login: "serviceaccounts/12", TokenType: auth.ServiceAccountTokenType}, "172.16.0.1:1234", http.StatusForbidden},
+		"cluster user outside range":    {&auth.User{Login: "clusters/1/2", TokenType: auth.ClusterTokenType}, "172.16.0.1:1234", http.StatusOK},
+		"virtual user inside range":     {&auth.User{Login: "org/hook", Virtual: true, TokenType: auth.CICDHookTokenType}, "10.1.2.3:1234", http.StatusOK},
+		"virtual user outside range":    {&auth.User{Login: "org/hook", Virtual: true, TokenType: auth.CICDHookTokenType}, "172.16.0.1:1234", http.StatusForbidden},
+		"spoofed forwarded address":     {&auth.User{ID: 1}, "172.16.0.1:1234", http.StatusForbidden},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.New()
+			router.ForwardedByClientIP = false
+			router.Use(NewIPAllowlistMiddleware(db, logrus.New(), emperror.NewNoopHandler()))
+			router.GET("/path", func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/path", nil)
+			req.RemoteAddr = test.remoteAddr
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("X-Forwarded-For", "10.1.2.3")
+
+			ctx := context.WithValue(req.Context(), qorauth.CurrentUser, test.user)
+			ctx = context.WithValue(ctx, auth.CurrentOrganization, &auth.Organization{ID: 1})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req.WithContext(ctx))
+
+			assert.Equal(t, test.expectedCode, w.Code)
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/banzaicloud/pipeline/auth"
+)
+
+// policyRule describes the minimum organization role required for requests matching a path pattern.
+// Patterns are relative to the organization (/orgs/:orgid) and are matched segment by segment:
+// "*" matches a single segment, a trailing "**" matches any number of remaining segments (including none).
+type policyRule struct {
+	methods []string // nil matches any method
+	pattern string
+	role    string
+}
+
+var writeMethods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// organizationPolicy is evaluated in order, the first matching rule decides the required role.
+// Requests matching no rule require the viewer role for reads and the member role for writes.
+var organizationPolicy = []policyRule{
+	{methods: []string{http.MethodDelete}, pattern: "", role: auth.RoleOwner},
+	{methods: writeMethods, pattern: "users/**", role: auth.RoleAdmin},
+	{pattern: "invitations/**", role: auth.RoleAdmin},
+	{methods: writeMethods, pattern: "posthooks/**", role: auth.RoleAdmin},
+	{methods: writeMethods, pattern: "tokenpolicy", role: auth.RoleAdmin},
+	{methods: writeMethods, pattern: "ipallowlist", role: auth.RoleAdmin},
+	{methods: writeMethods, pattern: "serviceaccounts/**", role: auth.RoleAdmin},
+	{pattern: "audit/**", role: auth.RoleAdmin},
+	{methods: []string{http.MethodDelete}, pattern: "clusters/*", role: auth.RoleAdmin},
+	{methods: writeMethods, pattern: "clusters/*/rbacbindings/**", role: auth.RoleAdmin},
+	{pattern: "secrets/**", role: auth.RoleMember},
+	{pattern: "clusters/*/config", role: auth.RoleMember},
+	{pattern: "clusters/*/secrets/**", role: auth.RoleMember},
+	{pattern: "clusters/*/proxy/**", role: auth.RoleMember},
+}
+
+// adminKubeconfigPolicy restricts the admin kubeconfig download (and the cluster proxy using the same credentials)
+// to organization admins, members can still get an OIDC kubeconfig bound to their own Kubernetes permissions.
+var adminKubeconfigPolicy = []policyRule{
+	{pattern: "clusters/*/config", role: auth.RoleAdmin},
+	{pattern: "clusters/*/proxy/**", role: auth.RoleAdmin},
+}
+
+// roleAllows checks whether an organization role is sufficient for a request.
+func roleAllows(policy []policyRule, role string, path string, method string) bool {
+	orgPath, ok := organizationPath(path)
+	if !ok {
+		return auth.RoleIncludes(role, auth.RoleViewer)
+	}
+
+	return auth.RoleIncludes(role, requiredRole(policy, orgPath, method))
+}
+
+// requiredRole returns the minimum organization role required for a request.
+// The path is expected to be relative to the organization.
+func requiredRole(policy []policyRule, orgPath string, method string) string {
+	for _, rule := range policy {
+		if rule.matches(orgPath, method) {
+			return rule.role
+		}
+	}
+
+	if isReadMethod(method) {
+		return auth.RoleViewer
+	}
+
+	return auth.RoleMember
+}
+
+func isReadMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// scopeAllows checks whether a scoped token can be used for a request.
+// Outside of organizations scoped tokens can only read (eg. the current user or the list of organizations).
+// Tokens limited to clusters can only access the routes of those clusters.
+// Clusters looked up by name are checked again once they are resolved (see api/common.ClusterGetter).
+func scopeAllows(scope *auth.TokenScope, org *auth.Organization, path string, method string) bool {
+	if (scope.ReadOnly || org == nil) && !isReadMethod(method) {
+		return false
+	}
+
+	if org == nil {
+		return true
+	}
+
+	if org.ID != scope.OrganizationID {
+		return false
+	}
+
+	if len(scope.ClusterIDs) == 0 {
+		return true
+	}
+
+	orgPath, ok := organizationPath(path)
+	if !ok {
+		return false
+	}
+
+	segments := splitPath(orgPath)
+	if len(segments) < 2 || segments[0] != "clusters" {
+		return false
+	}
+
+	clusterID, err := strconv.ParseUint(segments[1], 10, 32)
+	if err != nil {
+		return false
+	}
+
+	return scope.AllowsCluster(uint(clusterID))
+}
+
+func (r policyRule) matches(orgPath string, method string) bool {
+	if r.methods != nil {
+		var ok bool
+		for _, m := range r.methods {
+			if m == method {
+				ok = true
+				break
+			}
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return matchSegments(splitPath(r.pattern), splitPath(orgPath))
+}
+
+func matchSegments(pattern []string, segments []string) bool {
+	for i, p := range pattern {
+		if p == "**" && i == len(pattern)-1 {
+			return true
+		}
+
+		if i >= len(segments) || (p != "*" && p != segments[i]) {
+			return false
+		}
+	}
+
+	return len(pattern) == len(segments)
+}
+
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+
+	return strings.Split(path, "/")
+}
+
+// organizationPath returns the part of a request path following the organization ID (/orgs/:orgid).
+func organizationPath(path string) (string, bool) {
+	segments := splitPath(path)
+
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "orgs" {
+			return strings.Join(segments[i+2:], "/"), true
+		}
+	}
+
+	return "", false
+}
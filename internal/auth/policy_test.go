@@ -0,0 +1,149 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/banzaicloud/pipeline/auth"
+)
+
+func TestRequiredRole(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		path     string
+		method   string
+		expected string
+	}{
+		"list clusters":       {"/api/v1/orgs/1/clusters", http.MethodGet, auth.RoleViewer},
+		"create cluster":      {"/api/v1/orgs/1/clusters", http.MethodPost, auth.RoleMember},
+		"update cluster":      {"/api/v1/orgs/1/clusters/2", http.MethodPut, auth.RoleMember},
+		"delete cluster":      {"/api/v1/orgs/1/clusters/2", http.MethodDelete, auth.RoleAdmin},
+		"delete deployment":   {"/api/v1/orgs/1/clusters/2/deployments/foo", http.MethodDelete, auth.RoleMember},
+		"get kubeconfig":      {"/api/v1/orgs/1/clusters/2/config", http.MethodGet, auth.RoleMember},
+		"cluster proxy":       {"/api/v1/orgs/1/clusters/2/proxy/api/v1/pods", http.MethodGet, auth.RoleMember},
+		"list secrets":        {"/api/v1/orgs/1/secrets", http.MethodGet, auth.RoleMember},
+		"get secret":          {"/api/v1/orgs/1/secrets/abc", http.MethodGet, auth.RoleMember},
+		"list users":          {"/api/v1/orgs/1/users", http.MethodGet, auth.RoleViewer},
+		"add user":            {"/api/v1/orgs/1/users/3", http.MethodPost, auth.RoleAdmin},
+		"change user role":    {"/api/v1/orgs/1/users/3", http.MethodPut, auth.RoleAdmin},
+		"get organization":    {"/api/v1/orgs/1", http.MethodGet, auth.RoleViewer},
+		"delete organization": {"/api/v1/orgs/1", http.MethodDelete, auth.RoleOwner},
+		"without base path":   {"/orgs/1/clusters/2", http.MethodDelete, auth.RoleAdmin},
+		"dashboard":           {"/dashboard/orgs/1/clusters", http.MethodGet, auth.RoleViewer},
+		"bind cluster role":   {"/api/v1/orgs/1/clusters/2/rbacbindings", http.MethodPost, auth.RoleAdmin},
+		"set IP allowlist":    {"/api/v1/orgs/1/ipallowlist", http.MethodPut, auth.RoleAdmin},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			orgPath, ok := organizationPath(test.path)
+			assert.True(t, ok)
+
+			assert.Equal(t, test.expected, requiredRole(organizationPolicy, orgPath, test.method))
+		})
+	}
+}
+
+func TestScopeAllows(t *testing.T) {
+	t.Parallel()
+
+	org := &auth.Organization{ID: 1}
+	otherOrg := &auth.Organization{ID: 2}
+
+	orgScope := &auth.TokenScope{OrganizationID: 1}
+	clusterScope := &auth.TokenScope{OrganizationID: 1, ClusterIDs: []uint{3}, ReadOnly: true}
+
+	tests := map[string]struct {
+		scope    *auth.TokenScope
+		org      *auth.Organization
+		path     string
+		method   string
+		expected bool
+	}{
+		"organization":            {orgScope, org, "/api/v1/orgs/1/clusters", http.MethodPost, true},
+		"other organization":      {orgScope, otherOrg, "/api/v1/orgs/2/clusters", http.MethodGet, false},
+		"read outside org":        {orgScope, nil, "/api/v1/orgs", http.MethodGet, true},
+		"write outside org":       {orgScope, nil, "/api/v1/tokens/abc", http.MethodDelete, false},
+		"cluster kubeconfig":      {clusterScope, org, "/api/v1/orgs/1/clusters/3/config", http.MethodGet, true},
+		"other cluster":           {clusterScope, org, "/api/v1/orgs/1/clusters/4/config", http.MethodGet, false},
+		"list clusters":           {clusterScope, org, "/api/v1/orgs/1/clusters", http.MethodGet, false},
+		"organization secrets":    {clusterScope, org, "/api/v1/orgs/1/secrets", http.MethodGet, false},
+		"read only cluster write": {clusterScope, org, "/api/v1/orgs/1/clusters/3", http.MethodDelete, false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, scopeAllows(test.scope, test.org, test.path, test.method))
+		})
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		role     string
+		path     string
+		method   string
+		expected bool
+	}{
+		"viewer reads clusters":            {auth.RoleViewer, "/api/v1/orgs/1/clusters", http.MethodGet, true},
+		"viewer outside org":               {auth.RoleViewer, "/api/v1/orgs", http.MethodGet, true},
+		"member creates service account":   {auth.RoleMember, "/api/v1/orgs/1/serviceaccounts", http.MethodPost, false},
+		"admin creates service account":    {auth.RoleAdmin, "/api/v1/orgs/1/serviceaccounts", http.MethodPost, true},
+		"member lists service accounts":    {auth.RoleMember, "/api/v1/orgs/1/serviceaccounts", http.MethodGet, true},
+		"member creates cluster":           {auth.RoleMember, "/api/v1/orgs/1/clusters", http.MethodPost, true},
+		"admin deletes organization":       {auth.RoleAdmin, "/api/v1/orgs/1", http.MethodDelete, false},
+		"member revokes service acc token": {auth.RoleMember, "/api/v1/orgs/1/serviceaccounts/2/tokens/abc", http.MethodDelete, false},
+		"member reads audit events":        {auth.RoleMember, "/api/v1/orgs/1/audit", http.MethodGet, false},
+		"admin reads audit events":         {auth.RoleAdmin, "/api/v1/orgs/1/audit", http.MethodGet, true},
+		"member lists invitations":         {auth.RoleMember, "/api/v1/orgs/1/invitations", http.MethodGet, false},
+		"admin invites user":               {auth.RoleAdmin, "/api/v1/orgs/1/invitations", http.MethodPost, true},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, roleAllows(organizationPolicy, test.role, test.path, test.method))
+		})
+	}
+}
+
+func TestAdminKubeconfigPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := append(adminKubeconfigPolicy, organizationPolicy...)
+
+	assert.Equal(t, auth.RoleAdmin, requiredRole(policy, "clusters/2/config", http.MethodGet))
+	assert.Equal(t, auth.RoleAdmin, requiredRole(policy, "clusters/2/proxy/api/v1/pods", http.MethodGet))
+	assert.Equal(t, auth.RoleViewer, requiredRole(policy, "clusters/2/login", http.MethodGet))
+	assert.Equal(t, auth.RoleMember, requiredRole(organizationPolicy, "clusters/2/config", http.MethodGet))
+}
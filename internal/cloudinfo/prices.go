@@ -0,0 +1,46 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudinfo
+
+import (
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// GetNodePrice returns the hourly price of a single VM instance.
+// The spot price is the average of the current spot prices across the zones of the region,
+// or the on-demand price if there is no spot price available.
+func GetNodePrice(logger logrus.FieldLogger, cloud string, service string, region string, instanceType string, spot bool) (float64, error) {
+	machineDetails, err := GetMachineDetails(logger, cloud, service, region, instanceType)
+	if err != nil {
+		return 0, err
+	}
+
+	if machineDetails == nil {
+		return 0, emperror.With(errors.New("no machine info found for VM instance"), "cloud", cloud, "region", region, "service", service, "instanceType", instanceType)
+	}
+
+	if !spot || len(machineDetails.SpotPrice) == 0 {
+		return machineDetails.OnDemandPrice, nil
+	}
+
+	var sum float64
+	for _, zonePrice := range machineDetails.SpotPrice {
+		sum += zonePrice.Price
+	}
+
+	return sum / float64(len(machineDetails.SpotPrice)), nil
+}
@@ -0,0 +1,69 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+)
+
+const (
+	clusterCostRecordTableName = "cluster_cost_records"
+)
+
+// ClusterCostRecordModel describes a period of time while a node pool of a cluster ran with the same size and price.
+type ClusterCostRecordModel struct {
+	ID uint `gorm:"primary_key"`
+
+	ClusterID      uint `gorm:"not null;index:idx_cluster_cost_records_cluster_id"`
+	OrganizationID uint `gorm:"not null;index:idx_cluster_cost_records_organization_id"`
+
+	// ClusterName is kept so that the cost of deleted clusters can be reported as well.
+	ClusterName string `gorm:"not null"`
+
+	NodePool     string `gorm:"not null"`
+	InstanceType string
+	Spot         bool `gorm:"not null"`
+	Count        int  `gorm:"not null"`
+
+	// HourlyPrice is the price of a single node of the node pool per hour.
+	HourlyPrice float64 `gorm:"not null"`
+
+	StartedAt time.Time `gorm:"not null"`
+	EndedAt   time.Time `gorm:"not null"`
+}
+
+// TableName changes the default table name.
+func (ClusterCostRecordModel) TableName() string {
+	return clusterCostRecordTableName
+}
+
+// CostBetween returns the cost of the node pool accumulated in the overlap of the record and the given time range.
+func (m ClusterCostRecordModel) CostBetween(from time.Time, to time.Time) float64 {
+	start := m.StartedAt
+	if from.After(start) {
+		start = from
+	}
+
+	end := m.EndedAt
+	if to.Before(end) {
+		end = to
+	}
+
+	if !end.After(start) {
+		return 0
+	}
+
+	return end.Sub(start).Hours() * m.HourlyPrice * float64(m.Count)
+}
@@ -0,0 +1,93 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+)
+
+// ClusterCostRecords stores and reads back the node pool cost records of clusters.
+type ClusterCostRecords struct {
+	db *gorm.DB
+}
+
+// NewClusterCostRecords returns a new ClusterCostRecords instance.
+func NewClusterCostRecords(db *gorm.DB) *ClusterCostRecords {
+	return &ClusterCostRecords{db: db}
+}
+
+// FindLatestByCluster returns the latest cost record of every node pool of a cluster.
+func (r *ClusterCostRecords) FindLatestByCluster(clusterID uint) ([]ClusterCostRecordModel, error) {
+	var records []ClusterCostRecordModel
+
+	latestIDs := r.db.
+		Model(&ClusterCostRecordModel{}).
+		Select("MAX(id)").
+		Where("cluster_id = ?", clusterID).
+		Group("node_pool").
+		SubQuery()
+
+	err := r.db.Where("id IN ?", latestIDs).Find(&records).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch latest cost records", "clusterID", clusterID)
+	}
+
+	return records, nil
+}
+
+// FindByCluster returns the cost records of a cluster overlapping with the given time range.
+func (r *ClusterCostRecords) FindByCluster(clusterID uint, from time.Time, to time.Time) ([]ClusterCostRecordModel, error) {
+	var records []ClusterCostRecordModel
+
+	err := r.db.
+		Where("cluster_id = ?", clusterID).
+		Where("started_at < ? AND ended_at > ?", to, from).
+		Order("started_at ASC").
+		Find(&records).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch cost records", "clusterID", clusterID)
+	}
+
+	return records, nil
+}
+
+// FindByOrganization returns the cost records of the clusters of an organization overlapping with the given time range.
+func (r *ClusterCostRecords) FindByOrganization(organizationID uint, from time.Time, to time.Time) ([]ClusterCostRecordModel, error) {
+	var records []ClusterCostRecordModel
+
+	err := r.db.
+		Where("organization_id = ?", organizationID).
+		Where("started_at < ? AND ended_at > ?", to, from).
+		Order("started_at ASC").
+		Find(&records).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch cost records", "organizationID", organizationID)
+	}
+
+	return records, nil
+}
+
+// Save persists a cost record.
+func (r *ClusterCostRecords) Save(record *ClusterCostRecordModel) error {
+	err := r.db.Save(record).Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not save cost record", "clusterID", record.ClusterID, "nodePool", record.NodePool)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	clusterHibernationScheduleTableName = "cluster_hibernation_schedules"
+)
+
+// HibernationScheduleModel describes when the node pools of a cluster should be scaled down outside working hours.
+type HibernationScheduleModel struct {
+	ID uint `gorm:"primary_key"`
+
+	ClusterID uint `gorm:"unique_index;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CreatedBy uint
+
+	Enabled bool
+
+	// Timezone is the IANA time zone name the wake up and sleep times are interpreted in.
+	Timezone string `gorm:"not null;size:64"`
+
+	// Weekdays is the comma separated list of working days (eg. mon,tue,wed,thu,fri).
+	Weekdays string `gorm:"not null"`
+
+	// WakeUpTime and SleepTime mark the beginning and the end of working hours in HH:MM format.
+	WakeUpTime string `gorm:"not null;size:5"`
+	SleepTime  string `gorm:"not null;size:5"`
+
+	// NodePools is the list of node pools to hibernate (all node pools if empty).
+	NodePools HibernationNodePools `gorm:"type:text"`
+
+	// MinCount is the node count hibernated node pools are scaled down to.
+	MinCount int `gorm:"not null"`
+
+	Hibernated   bool
+	HibernatedAt *time.Time
+
+	// SavedNodePools holds the node pool sizes to be restored when the cluster wakes up.
+	SavedNodePools HibernationNodePoolSizes `gorm:"type:text"`
+}
+
+// TableName changes the default table name.
+func (HibernationScheduleModel) TableName() string {
+	return clusterHibernationScheduleTableName
+}
+
+// HibernationNodePools is a list of node pool names.
+type HibernationNodePools []string
+
+var _ driver.Valuer = (*HibernationNodePools)(nil)
+
+// Value implements the driver.Valuer interface
+func (n HibernationNodePools) Value() (driver.Value, error) {
+	return jsonValue(n)
+}
+
+var _ sql.Scanner = (*HibernationNodePools)(nil)
+
+// Scan implements the sql.Scanner interface
+func (n *HibernationNodePools) Scan(src interface{}) error {
+	return jsonScan(src, n)
+}
+
+// HibernationNodePoolSize describes the size of a node pool before hibernation.
+type HibernationNodePoolSize struct {
+	Autoscaling bool `json:"autoscaling"`
+	MinCount    int  `json:"minCount"`
+	MaxCount    int  `json:"maxCount"`
+	Count       int  `json:"count"`
+}
+
+// HibernationNodePoolSizes maps node pool names to their sizes.
+type HibernationNodePoolSizes map[string]HibernationNodePoolSize
+
+var _ driver.Valuer = (*HibernationNodePoolSizes)(nil)
+
+// Value implements the driver.Valuer interface
+func (n HibernationNodePoolSizes) Value() (driver.Value, error) {
+	return jsonValue(n)
+}
+
+var _ sql.Scanner = (*HibernationNodePoolSizes)(nil)
+
+// Scan implements the sql.Scanner interface
+func (n *HibernationNodePoolSizes) Scan(src interface{}) error {
+	return jsonScan(src, n)
+}
+
+func jsonValue(v interface{}) (driver.Value, error) {
+	r, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(r), nil
+}
+
+func jsonScan(src interface{}, v interface{}) error {
+	switch src := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(src) == 0 {
+			return nil
+		}
+		return json.Unmarshal(src, v)
+	case string:
+		if src == "" {
+			return nil
+		}
+		return json.Unmarshal([]byte(src), v)
+	default:
+		return errors.Errorf("unsupported column type: %T", src)
+	}
+}
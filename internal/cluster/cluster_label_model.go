@@ -0,0 +1,33 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+const (
+	clusterLabelTableName = "cluster_labels"
+)
+
+// ClusterLabelModel is a user defined key/value label of a cluster.
+type ClusterLabelModel struct {
+	ID uint `gorm:"primary_key"`
+
+	ClusterID uint   `gorm:"not null;unique_index:idx_cluster_labels_cluster_id_key"`
+	Key       string `gorm:"not null;size:317;unique_index:idx_cluster_labels_cluster_id_key"`
+	Value     string `gorm:"not null;size:63"`
+}
+
+// TableName changes the default table name.
+func (ClusterLabelModel) TableName() string {
+	return clusterLabelTableName
+}
@@ -0,0 +1,69 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+)
+
+const (
+	clusterPostHookStatusTableName = "cluster_posthook_statuses"
+)
+
+// Post hook execution states
+const (
+	PostHookStatusPending   = "pending"
+	PostHookStatusRunning   = "running"
+	PostHookStatusSucceeded = "succeeded"
+	PostHookStatusFailed    = "failed"
+)
+
+// PostHookStatusModel records the state of the last execution of a post hook on a cluster.
+type PostHookStatusModel struct {
+	ID uint `gorm:"primary_key"`
+
+	ClusterID uint   `gorm:"not null;unique_index:idx_cluster_posthook_statuses_cluster_id_name"`
+	Name      string `gorm:"not null;size:64;unique_index:idx_cluster_posthook_statuses_cluster_id_name"`
+
+	// Position is the place of the post hook in the last workflow it was part of.
+	Position int `gorm:"not null"`
+
+	// Param is the JSON encoded parameter of the post hook, used when it is re-run.
+	Param string `sql:"type:text"`
+
+	Status     string `gorm:"not null;size:16"`
+	Error      string `sql:"type:text"`
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	UpdatedAt  time.Time
+}
+
+// TableName changes the default table name.
+func (PostHookStatusModel) TableName() string {
+	return clusterPostHookStatusTableName
+}
+
+// Duration returns how long the last execution of the post hook took (so far).
+func (m PostHookStatusModel) Duration() time.Duration {
+	if m.StartedAt == nil {
+		return 0
+	}
+
+	if m.FinishedAt == nil {
+		return time.Since(*m.StartedAt)
+	}
+
+	return m.FinishedAt.Sub(*m.StartedAt)
+}
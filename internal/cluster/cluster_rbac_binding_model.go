@@ -0,0 +1,50 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+)
+
+const (
+	clusterRBACBindingTableName = "cluster_rbac_bindings"
+)
+
+// ClusterRBACBindingModel binds a Pipeline user or an identity provider group to a Kubernetes role in a cluster.
+// Users are bound by the email, groups by the groups claim of their OIDC tokens.
+type ClusterRBACBindingModel struct {
+	ID uint `gorm:"primary_key"`
+
+	ClusterID uint `gorm:"not null;index:idx_cluster_rbac_bindings_cluster_id"`
+
+	// Either UserID or Group is set.
+	UserID uint
+	Group  string
+
+	// RoleKind is either ClusterRole or Role.
+	RoleKind string `gorm:"not null"`
+	RoleName string `gorm:"not null"`
+
+	// Namespace is empty for cluster wide bindings.
+	Namespace string
+
+	CreatedAt time.Time
+	CreatedBy uint
+}
+
+// TableName changes the default table name.
+func (ClusterRBACBindingModel) TableName() string {
+	return clusterRBACBindingTableName
+}
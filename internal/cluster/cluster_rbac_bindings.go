@@ -0,0 +1,89 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+)
+
+// ClusterRBACBindings stores the RBAC bindings of clusters.
+type ClusterRBACBindings struct {
+	db *gorm.DB
+}
+
+// NewClusterRBACBindings returns a new ClusterRBACBindings instance.
+func NewClusterRBACBindings(db *gorm.DB) *ClusterRBACBindings {
+	return &ClusterRBACBindings{db: db}
+}
+
+// FindByCluster returns the RBAC bindings of a cluster.
+func (r *ClusterRBACBindings) FindByCluster(clusterID uint) ([]ClusterRBACBindingModel, error) {
+	bindings := make([]ClusterRBACBindingModel, 0)
+
+	err := r.db.Where(ClusterRBACBindingModel{ClusterID: clusterID}).Order("id ASC").Find(&bindings).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch rbac bindings", "clusterID", clusterID)
+	}
+
+	return bindings, nil
+}
+
+// FindByUser returns the RBAC bindings of a user in every cluster.
+func (r *ClusterRBACBindings) FindByUser(userID uint) ([]ClusterRBACBindingModel, error) {
+	bindings := make([]ClusterRBACBindingModel, 0)
+
+	err := r.db.Where(ClusterRBACBindingModel{UserID: userID}).Order("id ASC").Find(&bindings).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch rbac bindings", "userID", userID)
+	}
+
+	return bindings, nil
+}
+
+// FindOne returns an RBAC binding of a cluster.
+// If the binding cannot be found nil is returned.
+func (r *ClusterRBACBindings) FindOne(clusterID uint, id uint) (*ClusterRBACBindingModel, error) {
+	var binding ClusterRBACBindingModel
+
+	err := r.db.Where(ClusterRBACBindingModel{ClusterID: clusterID, ID: id}).First(&binding).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch rbac binding", "clusterID", clusterID, "bindingID", id)
+	}
+
+	return &binding, nil
+}
+
+// Save saves an RBAC binding.
+func (r *ClusterRBACBindings) Save(binding *ClusterRBACBindingModel) error {
+	err := r.db.Save(binding).Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not save rbac binding", "clusterID", binding.ClusterID)
+	}
+
+	return nil
+}
+
+// Delete deletes an RBAC binding.
+func (r *ClusterRBACBindings) Delete(binding *ClusterRBACBindingModel) error {
+	err := r.db.Delete(binding).Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not delete rbac binding", "clusterID", binding.ClusterID, "bindingID", binding.ID)
+	}
+
+	return nil
+}
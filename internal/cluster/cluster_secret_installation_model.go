@@ -0,0 +1,64 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+)
+
+const (
+	clusterSecretInstallationTableName = "cluster_secret_installations"
+)
+
+// Secret installation sync states
+const (
+	SecretInstallationStatusPending = "pending"
+	SecretInstallationStatusRunning = "running"
+	SecretInstallationStatusSynced  = "synced"
+	SecretInstallationStatusFailed  = "failed"
+)
+
+// SecretInstallationModel records a copy of a Pipeline secret installed into a Kubernetes cluster,
+// so that it can be updated when the secret changes.
+type SecretInstallationModel struct {
+	ID uint `gorm:"primary_key"`
+
+	OrganizationID uint   `gorm:"not null;index:idx_cluster_secret_installations_secret"`
+	SecretID       string `gorm:"not null;size:64;index:idx_cluster_secret_installations_secret;unique_index:idx_cluster_secret_installations_target"`
+
+	ClusterID uint   `gorm:"not null;unique_index:idx_cluster_secret_installations_target"`
+	Namespace string `gorm:"not null;size:253;unique_index:idx_cluster_secret_installations_target"`
+	Name      string `gorm:"not null;size:253;unique_index:idx_cluster_secret_installations_target"`
+
+	// Spec is the JSON encoded key mapping of the installation, empty if the secret was copied as is.
+	Spec string `sql:"type:text"`
+
+	// Merge is set if the secret was merged into an existing Kubernetes secret.
+	// Several Pipeline secrets can be merged into the same Kubernetes secret, each of them has its own record.
+	Merge bool `gorm:"not null;default:false"`
+
+	Status        string `gorm:"not null;size:16"`
+	Error         string `sql:"type:text"`
+	SyncedVersion int
+	SyncedAt      *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName changes the default table name.
+func (SecretInstallationModel) TableName() string {
+	return clusterSecretInstallationTableName
+}
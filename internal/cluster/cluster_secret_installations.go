@@ -0,0 +1,182 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+)
+
+// SecretInstallations stores the secrets installed into clusters and the state of their synchronization.
+type SecretInstallations struct {
+	db *gorm.DB
+}
+
+// NewSecretInstallations returns a new SecretInstallations instance.
+func NewSecretInstallations(db *gorm.DB) *SecretInstallations {
+	return &SecretInstallations{db: db}
+}
+
+// FindBySecret returns the installations of a secret.
+func (s *SecretInstallations) FindBySecret(organizationID uint, secretID string) ([]SecretInstallationModel, error) {
+	var installations []SecretInstallationModel
+
+	err := s.db.
+		Where(SecretInstallationModel{OrganizationID: organizationID, SecretID: secretID}).
+		Order("cluster_id ASC").
+		Order("id ASC").
+		Find(&installations).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch secret installations", "organizationID", organizationID, "secret", secretID)
+	}
+
+	return installations, nil
+}
+
+// FindOne returns a secret installation or nil if it does not exist.
+func (s *SecretInstallations) FindOne(id uint) (*SecretInstallationModel, error) {
+	var installation SecretInstallationModel
+
+	err := s.db.Where(SecretInstallationModel{ID: id}).First(&installation).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch secret installation", "installationID", id)
+	}
+
+	return &installation, nil
+}
+
+// Save records a secret installation as synced.
+// Installing the same secret under the same name and namespace of a cluster replaces the previous record.
+// Unless the secret was merged into the Kubernetes secret, the records of other secrets installed there are removed,
+// because they have been overwritten.
+func (s *SecretInstallations) Save(installation *SecretInstallationModel) error {
+	now := time.Now()
+	target := SecretInstallationModel{
+		ClusterID: installation.ClusterID,
+		Namespace: installation.Namespace,
+		Name:      installation.Name,
+	}
+
+	tx := s.db.Begin()
+
+	if !installation.Merge {
+		err := tx.Where(target).Where("secret_id <> ?", installation.SecretID).Delete(SecretInstallationModel{}).Error
+		if err != nil {
+			tx.Rollback()
+
+			return emperror.WrapWith(err, "could not delete overwritten secret installations", "clusterID", installation.ClusterID, "secret", installation.SecretID)
+		}
+	}
+
+	target.SecretID = installation.SecretID
+
+	err := tx.
+		Where(target).
+		Assign(map[string]interface{}{
+			"organization_id": installation.OrganizationID,
+			"spec":            installation.Spec,
+			"merge":           installation.Merge,
+			"status":          SecretInstallationStatusSynced,
+			"error":           "",
+			"synced_version":  installation.SyncedVersion,
+			"synced_at":       &now,
+		}).
+		FirstOrCreate(installation).Error
+	if err != nil {
+		tx.Rollback()
+
+		return emperror.WrapWith(err, "could not save secret installation", "clusterID", installation.ClusterID, "secret", installation.SecretID)
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not save secret installation", "clusterID", installation.ClusterID, "secret", installation.SecretID)
+	}
+
+	return nil
+}
+
+// Delete removes the record of a secret installation.
+func (s *SecretInstallations) Delete(id uint) error {
+	err := s.db.Delete(SecretInstallationModel{ID: id}).Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not delete secret installation", "installationID", id)
+	}
+
+	return nil
+}
+
+// MarkPending resets the state of the given installations before they are synced.
+func (s *SecretInstallations) MarkPending(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := s.db.
+		Model(&SecretInstallationModel{}).
+		Where("id IN (?)", ids).
+		Updates(map[string]interface{}{
+			"status": SecretInstallationStatusPending,
+			"error":  "",
+		}).Error
+	if err != nil {
+		return emperror.Wrap(err, "could not update secret installation statuses")
+	}
+
+	return nil
+}
+
+// MarkRunning records that the synchronization of an installation has been started.
+func (s *SecretInstallations) MarkRunning(id uint) error {
+	return s.update(id, map[string]interface{}{
+		"status": SecretInstallationStatusRunning,
+		"error":  "",
+	})
+}
+
+// MarkFinished records the result of the synchronization of an installation.
+func (s *SecretInstallations) MarkFinished(id uint, version int, syncErr error) error {
+	if syncErr != nil {
+		return s.update(id, map[string]interface{}{
+			"status": SecretInstallationStatusFailed,
+			"error":  syncErr.Error(),
+		})
+	}
+
+	now := time.Now()
+
+	return s.update(id, map[string]interface{}{
+		"status":         SecretInstallationStatusSynced,
+		"error":          "",
+		"synced_version": version,
+		"synced_at":      &now,
+	})
+}
+
+func (s *SecretInstallations) update(id uint, fields map[string]interface{}) error {
+	err := s.db.
+		Model(&SecretInstallationModel{}).
+		Where(SecretInstallationModel{ID: id}).
+		Updates(fields).Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not update secret installation status", "installationID", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretInstallations_Save(t *testing.T) {
+	db, err := gorm.Open("sqlite3", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	require.NoError(t, db.AutoMigrate(SecretInstallationModel{}).Error)
+
+	installations := NewSecretInstallations(db)
+
+	install := func(secretID string, version int, merge bool) {
+		require.NoError(t, installations.Save(&SecretInstallationModel{
+			ClusterID:      1,
+			OrganizationID: 1,
+			SecretID:       secretID,
+			Namespace:      "default",
+			Name:           "app-secret",
+			Merge:          merge,
+			SyncedVersion:  version,
+		}))
+	}
+
+	install("first", 1, false)
+	install("second", 1, true)
+	install("second", 2, true)
+
+	first, err := installations.FindBySecret(1, "first")
+	require.NoError(t, err)
+	assert.Len(t, first, 1)
+
+	second, err := installations.FindBySecret(1, "second")
+	require.NoError(t, err)
+	require.Len(t, second, 1)
+	assert.Equal(t, 2, second[0].SyncedVersion)
+
+	install("third", 1, false)
+
+	first, err = installations.FindBySecret(1, "first")
+	require.NoError(t, err)
+	assert.Empty(t, first)
+
+	second, err = installations.FindBySecret(1, "second")
+	require.NoError(t, err)
+	assert.Empty(t, second)
+}
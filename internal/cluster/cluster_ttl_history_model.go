@@ -0,0 +1,55 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+)
+
+const (
+	clusterTTLHistoryTableName = "cluster_ttl_history"
+)
+
+// TTL history actions
+const (
+	TTLActionSet    = "set"
+	TTLActionExtend = "extend"
+	TTLActionClear  = "clear"
+	TTLActionWarn   = "warn"
+)
+
+// TTLHistoryModel records the TTL changes and expiry warnings of a cluster.
+type TTLHistoryModel struct {
+	ID uint `gorm:"primary_key"`
+
+	ClusterID   uint      `gorm:"not null;index"`
+	ClusterName string    `gorm:"not null"`
+	CreatedAt   time.Time `gorm:"not null"`
+
+	// UserID is the user who changed the TTL (zero for warnings issued by Pipeline).
+	UserID uint   `gorm:"not null"`
+	Action string `gorm:"not null;size:16"`
+
+	FromTTLMinutes uint `gorm:"column:from_ttl_minutes;not null"`
+	ToTTLMinutes   uint `gorm:"column:to_ttl_minutes;not null"`
+
+	// ExpiresAt is the time when the cluster is deleted after the change (if any).
+	ExpiresAt *time.Time
+}
+
+// TableName changes the default table name.
+func (TTLHistoryModel) TableName() string {
+	return clusterTTLHistoryTableName
+}
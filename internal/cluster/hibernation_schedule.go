@@ -0,0 +1,115 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultHibernationWeekdays are the working days used when a schedule does not specify any.
+const DefaultHibernationWeekdays = "mon,tue,wed,thu,fri"
+
+var hibernationWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// HibernationSchedule is the parsed form of a hibernation schedule.
+type HibernationSchedule struct {
+	Location *time.Location
+	Weekdays map[time.Weekday]bool
+
+	// WakeUp and Sleep are offsets from local midnight.
+	WakeUp time.Duration
+	Sleep  time.Duration
+}
+
+// ParseHibernationSchedule parses and validates the schedule of a hibernation schedule model.
+func ParseHibernationSchedule(model HibernationScheduleModel) (*HibernationSchedule, error) {
+	location, err := time.LoadLocation(model.Timezone)
+	if err != nil {
+		return nil, errors.Errorf("invalid timezone: %q", model.Timezone)
+	}
+
+	schedule := &HibernationSchedule{
+		Location: location,
+		Weekdays: make(map[time.Weekday]bool),
+	}
+
+	weekdays := model.Weekdays
+	if weekdays == "" {
+		weekdays = DefaultHibernationWeekdays
+	}
+
+	for _, day := range strings.Split(weekdays, ",") {
+		weekday, ok := hibernationWeekdays[strings.ToLower(strings.TrimSpace(day))]
+		if !ok {
+			return nil, errors.Errorf("invalid weekday: %q", day)
+		}
+
+		schedule.Weekdays[weekday] = true
+	}
+
+	schedule.WakeUp, err = parseTimeOfDay(model.WakeUpTime)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid wake up time")
+	}
+
+	schedule.Sleep, err = parseTimeOfDay(model.SleepTime)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid sleep time")
+	}
+
+	if schedule.WakeUp >= schedule.Sleep {
+		return nil, errors.New("wake up time must be earlier than sleep time")
+	}
+
+	if model.MinCount < 0 {
+		return nil, errors.New("minimum node count must not be negative")
+	}
+
+	return schedule, nil
+}
+
+// IsSleepTime returns true if the given time falls outside of the working hours.
+func (s *HibernationSchedule) IsSleepTime(now time.Time) bool {
+	local := now.In(s.Location)
+
+	if !s.Weekdays[local.Weekday()] {
+		return true
+	}
+
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, s.Location)
+	sinceMidnight := local.Sub(midnight)
+
+	return sinceMidnight < s.WakeUp || sinceMidnight >= s.Sleep
+}
+
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, errors.Errorf("%q is not in HH:MM format", value)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,83 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHibernationSchedule_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]HibernationScheduleModel{
+		"timezone":  {Timezone: "Nowhere/City", WakeUpTime: "08:00", SleepTime: "20:00"},
+		"weekday":   {Timezone: "UTC", Weekdays: "mon,funday", WakeUpTime: "08:00", SleepTime: "20:00"},
+		"wake up":   {Timezone: "UTC", WakeUpTime: "8am", SleepTime: "20:00"},
+		"sleep":     {Timezone: "UTC", WakeUpTime: "08:00", SleepTime: "25:00"},
+		"order":     {Timezone: "UTC", WakeUpTime: "20:00", SleepTime: "08:00"},
+		"min count": {Timezone: "UTC", WakeUpTime: "08:00", SleepTime: "20:00", MinCount: -1},
+	}
+
+	for name, model := range tests {
+		name, model := name, model
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseHibernationSchedule(model)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestHibernationSchedule_IsSleepTime(t *testing.T) {
+	t.Parallel()
+
+	schedule, err := ParseHibernationSchedule(HibernationScheduleModel{
+		Timezone:   "Europe/Budapest",
+		WakeUpTime: "08:00",
+		SleepTime:  "20:00",
+	})
+	require.NoError(t, err)
+
+	location, err := time.LoadLocation("Europe/Budapest")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		now      time.Time
+		expected bool
+	}{
+		"before working hours":   {time.Date(2019, 5, 13, 7, 59, 0, 0, location), true},
+		"start of working hours": {time.Date(2019, 5, 13, 8, 0, 0, 0, location), false},
+		"during working hours":   {time.Date(2019, 5, 15, 12, 0, 0, 0, location), false},
+		"end of working hours":   {time.Date(2019, 5, 17, 20, 0, 0, 0, location), true},
+		"weekend":                {time.Date(2019, 5, 18, 12, 0, 0, 0, location), true},
+		"other timezone":         {time.Date(2019, 5, 13, 6, 30, 0, 0, time.UTC), false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, schedule.IsSleepTime(test.now))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+)
+
+// HibernationSchedules stores and reads back the hibernation schedules of clusters.
+type HibernationSchedules struct {
+	db *gorm.DB
+}
+
+// NewHibernationSchedules returns a new HibernationSchedules instance.
+func NewHibernationSchedules(db *gorm.DB) *HibernationSchedules {
+	return &HibernationSchedules{db: db}
+}
+
+// FindByCluster returns the hibernation schedule of a cluster or nil if there is none.
+func (s *HibernationSchedules) FindByCluster(clusterID uint) (*HibernationScheduleModel, error) {
+	var schedule HibernationScheduleModel
+
+	err := s.db.Where(HibernationScheduleModel{ClusterID: clusterID}).First(&schedule).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch cluster hibernation schedule", "clusterID", clusterID)
+	}
+
+	return &schedule, nil
+}
+
+// FindActive returns the schedules that are either enabled or currently keep a cluster hibernated.
+func (s *HibernationSchedules) FindActive() ([]HibernationScheduleModel, error) {
+	var schedules []HibernationScheduleModel
+
+	err := s.db.Where("enabled = ? OR hibernated = ?", true, true).Find(&schedules).Error
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not fetch cluster hibernation schedules")
+	}
+
+	return schedules, nil
+}
+
+// Save creates or updates a hibernation schedule.
+func (s *HibernationSchedules) Save(schedule *HibernationScheduleModel) error {
+	err := s.db.Save(schedule).Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not save cluster hibernation schedule", "clusterID", schedule.ClusterID)
+	}
+
+	return nil
+}
+
+// Delete removes the hibernation schedule of a cluster.
+func (s *HibernationSchedules) Delete(clusterID uint) error {
+	err := s.db.Where(HibernationScheduleModel{ClusterID: clusterID}).Delete(HibernationScheduleModel{}).Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not delete cluster hibernation schedule", "clusterID", clusterID)
+	}
+
+	return nil
+}
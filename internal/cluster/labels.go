@@ -0,0 +1,98 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+)
+
+// Labels stores and reads back the user defined labels of clusters.
+type Labels struct {
+	db *gorm.DB
+}
+
+// NewLabels returns a new Labels instance.
+func NewLabels(db *gorm.DB) *Labels {
+	return &Labels{db: db}
+}
+
+// FindByCluster returns the labels of a cluster.
+func (l *Labels) FindByCluster(clusterID uint) (map[string]string, error) {
+	labels, err := l.FindByClusters([]uint{clusterID})
+	if err != nil {
+		return nil, err
+	}
+
+	if labels[clusterID] == nil {
+		return map[string]string{}, nil
+	}
+
+	return labels[clusterID], nil
+}
+
+// FindByClusters returns the labels of the given clusters indexed by cluster ID.
+func (l *Labels) FindByClusters(clusterIDs []uint) (map[uint]map[string]string, error) {
+	result := make(map[uint]map[string]string, len(clusterIDs))
+
+	if len(clusterIDs) == 0 {
+		return result, nil
+	}
+
+	var labels []ClusterLabelModel
+
+	err := l.db.Where("cluster_id IN (?)", clusterIDs).Find(&labels).Error
+	if err != nil {
+		return nil, emperror.Wrap(err, "could not fetch cluster labels")
+	}
+
+	for _, label := range labels {
+		if result[label.ClusterID] == nil {
+			result[label.ClusterID] = make(map[string]string)
+		}
+
+		result[label.ClusterID][label.Key] = label.Value
+	}
+
+	return result, nil
+}
+
+// Replace replaces all labels of a cluster with the given ones.
+func (l *Labels) Replace(clusterID uint, labels map[string]string) error {
+	tx := l.db.Begin()
+
+	err := tx.Where(ClusterLabelModel{ClusterID: clusterID}).Delete(ClusterLabelModel{}).Error
+	if err != nil {
+		tx.Rollback()
+
+		return emperror.WrapWith(err, "could not delete cluster labels", "clusterID", clusterID)
+	}
+
+	for key, value := range labels {
+		err := tx.Create(&ClusterLabelModel{ClusterID: clusterID, Key: key, Value: value}).Error
+		if err != nil {
+			tx.Rollback()
+
+			return emperror.WrapWith(err, "could not save cluster label", "clusterID", clusterID, "label", key)
+		}
+	}
+
+	err = tx.Commit().Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not save cluster labels", "clusterID", clusterID)
+	}
+
+	return nil
+}
@@ -27,6 +27,14 @@ func Migrate(db *gorm.DB, logger logrus.FieldLogger) error {
 	tables := []interface{}{
 		&ClusterModel{},
 		&StatusHistoryModel{},
+		&TTLHistoryModel{},
+		&HibernationScheduleModel{},
+		&ClusterLabelModel{},
+		&PostHookStatusModel{},
+		&OrganizationPostHookModel{},
+		&ClusterCostRecordModel{},
+		&ClusterRBACBindingModel{},
+		&SecretInstallationModel{},
 	}
 
 	var tableNames string
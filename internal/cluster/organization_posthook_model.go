@@ -0,0 +1,52 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+)
+
+const (
+	organizationPostHookTableName = "organization_posthooks"
+)
+
+// OrganizationPostHookModel describes a user defined post hook which installs a Helm chart
+// on every new cluster of an organization.
+type OrganizationPostHookModel struct {
+	ID uint `gorm:"primary_key"`
+
+	OrganizationID uint   `gorm:"not null;unique_index:idx_organization_posthooks_organization_id_name"`
+	Name           string `gorm:"not null;size:50;unique_index:idx_organization_posthooks_organization_id_name"`
+
+	Chart        string `gorm:"not null"`
+	ChartVersion string
+	Namespace    string `gorm:"not null"`
+
+	// Values is the YAML encoded value overrides of the chart.
+	Values string `sql:"type:text"`
+
+	// Priority orders the organization post hooks: the lower the value, the sooner the post hook runs.
+	Priority int  `gorm:"not null"`
+	Enabled  bool `gorm:"not null"`
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CreatedBy uint
+}
+
+// TableName changes the default table name.
+func (OrganizationPostHookModel) TableName() string {
+	return organizationPostHookTableName
+}
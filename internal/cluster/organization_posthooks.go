@@ -0,0 +1,81 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+)
+
+// OrganizationPostHooks stores and reads back the user defined post hooks of organizations.
+type OrganizationPostHooks struct {
+	db *gorm.DB
+}
+
+// NewOrganizationPostHooks returns a new OrganizationPostHooks instance.
+func NewOrganizationPostHooks(db *gorm.DB) *OrganizationPostHooks {
+	return &OrganizationPostHooks{db: db}
+}
+
+// FindByOrganization returns the post hooks of an organization in execution order.
+func (p *OrganizationPostHooks) FindByOrganization(organizationID uint) ([]OrganizationPostHookModel, error) {
+	var postHooks []OrganizationPostHookModel
+
+	err := p.db.
+		Where(OrganizationPostHookModel{OrganizationID: organizationID}).
+		Order("priority ASC").
+		Order("name ASC").
+		Find(&postHooks).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch organization post hooks", "organizationID", organizationID)
+	}
+
+	return postHooks, nil
+}
+
+// FindOne returns a single post hook of an organization.
+// If the post hook cannot be found nil is returned.
+func (p *OrganizationPostHooks) FindOne(organizationID uint, name string) (*OrganizationPostHookModel, error) {
+	var postHook OrganizationPostHookModel
+
+	err := p.db.Where(OrganizationPostHookModel{OrganizationID: organizationID, Name: name}).First(&postHook).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch organization post hook", "organizationID", organizationID, "postHook", name)
+	}
+
+	return &postHook, nil
+}
+
+// Save persists an organization post hook.
+func (p *OrganizationPostHooks) Save(postHook *OrganizationPostHookModel) error {
+	err := p.db.Save(postHook).Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not save organization post hook", "organizationID", postHook.OrganizationID, "postHook", postHook.Name)
+	}
+
+	return nil
+}
+
+// Delete removes an organization post hook.
+func (p *OrganizationPostHooks) Delete(postHook *OrganizationPostHookModel) error {
+	err := p.db.Delete(postHook).Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not delete organization post hook", "organizationID", postHook.OrganizationID, "postHook", postHook.Name)
+	}
+
+	return nil
+}
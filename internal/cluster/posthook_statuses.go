@@ -0,0 +1,128 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+)
+
+// PostHookStatuses stores and reads back the execution state of cluster post hooks.
+type PostHookStatuses struct {
+	db *gorm.DB
+}
+
+// NewPostHookStatuses returns a new PostHookStatuses instance.
+func NewPostHookStatuses(db *gorm.DB) *PostHookStatuses {
+	return &PostHookStatuses{db: db}
+}
+
+// FindByCluster returns the post hook states of a cluster in execution order.
+func (s *PostHookStatuses) FindByCluster(clusterID uint) ([]PostHookStatusModel, error) {
+	var statuses []PostHookStatusModel
+
+	err := s.db.
+		Where(PostHookStatusModel{ClusterID: clusterID}).
+		Order("position ASC").
+		Order("id ASC").
+		Find(&statuses).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch cluster post hook statuses", "clusterID", clusterID)
+	}
+
+	return statuses, nil
+}
+
+// MarkPending resets the state of the given post hooks before they are (re-)run.
+// The position and parameter of each post hook is taken from the passed models.
+func (s *PostHookStatuses) MarkPending(clusterID uint, postHooks []PostHookStatusModel) error {
+	tx := s.db.Begin()
+
+	for _, postHook := range postHooks {
+		var status PostHookStatusModel
+
+		err := tx.Where(PostHookStatusModel{ClusterID: clusterID, Name: postHook.Name}).FirstOrInit(&status).Error
+		if err != nil {
+			tx.Rollback()
+
+			return emperror.WrapWith(err, "could not fetch cluster post hook status", "clusterID", clusterID, "postHook", postHook.Name)
+		}
+
+		status.Position = postHook.Position
+		status.Param = postHook.Param
+		status.Status = PostHookStatusPending
+		status.Error = ""
+		status.StartedAt = nil
+		status.FinishedAt = nil
+
+		err = tx.Save(&status).Error
+		if err != nil {
+			tx.Rollback()
+
+			return emperror.WrapWith(err, "could not save cluster post hook status", "clusterID", clusterID, "postHook", postHook.Name)
+		}
+	}
+
+	err := tx.Commit().Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not save cluster post hook statuses", "clusterID", clusterID)
+	}
+
+	return nil
+}
+
+// MarkRunning records that a post hook has been started.
+func (s *PostHookStatuses) MarkRunning(clusterID uint, name string) error {
+	now := time.Now()
+
+	return s.update(clusterID, name, map[string]interface{}{
+		"status":      PostHookStatusRunning,
+		"error":       "",
+		"started_at":  &now,
+		"finished_at": nil,
+	})
+}
+
+// MarkFinished records the result of a post hook.
+func (s *PostHookStatuses) MarkFinished(clusterID uint, name string, hookErr error) error {
+	status := PostHookStatusSucceeded
+	errorMessage := ""
+	if hookErr != nil {
+		status = PostHookStatusFailed
+		errorMessage = hookErr.Error()
+	}
+
+	now := time.Now()
+
+	return s.update(clusterID, name, map[string]interface{}{
+		"status":      status,
+		"error":       errorMessage,
+		"finished_at": &now,
+	})
+}
+
+func (s *PostHookStatuses) update(clusterID uint, name string, fields map[string]interface{}) error {
+	err := s.db.
+		Model(&PostHookStatusModel{}).
+		Where(PostHookStatusModel{ClusterID: clusterID, Name: name}).
+		Updates(fields).Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not update cluster post hook status", "clusterID", clusterID, "postHook", name)
+	}
+
+	return nil
+}
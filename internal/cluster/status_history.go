@@ -0,0 +1,152 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+)
+
+// StatusHistoryQuery contains the filters of a status history query.
+// Zero values are ignored.
+type StatusHistoryQuery struct {
+	ClusterID uint
+	From      time.Time
+	To        time.Time
+	Statuses  []string
+}
+
+// StatusTransition describes a single status transition of a cluster along with
+// the time the cluster spent in the new status.
+type StatusTransition struct {
+	ClusterID   uint
+	ClusterName string
+
+	FromStatus        string
+	FromStatusMessage string
+	ToStatus          string
+	ToStatusMessage   string
+
+	StartedAt time.Time
+	EndedAt   *time.Time
+
+	// Duration is the time spent in ToStatus. For the current status it is measured until now.
+	Duration time.Duration
+}
+
+// StatusHistory reads back the status transitions of clusters.
+type StatusHistory struct {
+	db *gorm.DB
+}
+
+// NewStatusHistory returns a new StatusHistory instance.
+func NewStatusHistory(db *gorm.DB) *StatusHistory {
+	return &StatusHistory{db: db}
+}
+
+// FindByOrganization returns the status transitions of the clusters (including deleted ones) of an organization.
+func (h *StatusHistory) FindByOrganization(organizationID uint, query StatusHistoryQuery) ([]StatusTransition, error) {
+	db := h.db.
+		Joins("JOIN "+clustersTableName+" ON "+clustersTableName+".id = "+clusterStatusHistoryTableName+".cluster_id").
+		Where(clustersTableName+".organization_id = ?", organizationID)
+
+	if query.ClusterID != 0 {
+		db = db.Where(clusterStatusHistoryTableName+".cluster_id = ?", query.ClusterID)
+	}
+
+	// The upper bound is applied after building the timeline,
+	// so that the duration of the last transition in range can be calculated.
+	if !query.From.IsZero() {
+		db = db.Where(clusterStatusHistoryTableName+".created_at >= ?", query.From)
+	}
+
+	var entries []StatusHistoryModel
+
+	err := db.
+		Order(clusterStatusHistoryTableName + ".created_at ASC").
+		Order(clusterStatusHistoryTableName + ".id ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch cluster status history", "organizationID", organizationID, "clusterID", query.ClusterID)
+	}
+
+	transitions := buildStatusTimeline(entries, time.Now())
+
+	return filterStatusTransitions(transitions, query.To, query.Statuses), nil
+}
+
+// buildStatusTimeline calculates how long each cluster spent in a status.
+// Entries are expected to be in chronological order.
+func buildStatusTimeline(entries []StatusHistoryModel, now time.Time) []StatusTransition {
+	transitions := make([]StatusTransition, 0, len(entries))
+	lastTransition := make(map[uint]int)
+
+	for _, entry := range entries {
+		if i, ok := lastTransition[entry.ClusterID]; ok {
+			endedAt := entry.CreatedAt
+			transitions[i].EndedAt = &endedAt
+			transitions[i].Duration = endedAt.Sub(transitions[i].StartedAt)
+		}
+
+		lastTransition[entry.ClusterID] = len(transitions)
+
+		transitions = append(transitions, StatusTransition{
+			ClusterID:         entry.ClusterID,
+			ClusterName:       entry.ClusterName,
+			FromStatus:        entry.FromStatus,
+			FromStatusMessage: entry.FromStatusMessage,
+			ToStatus:          entry.ToStatus,
+			ToStatusMessage:   entry.ToStatusMessage,
+			StartedAt:         entry.CreatedAt,
+		})
+	}
+
+	for _, i := range lastTransition {
+		transitions[i].Duration = now.Sub(transitions[i].StartedAt)
+	}
+
+	return transitions
+}
+
+// filterStatusTransitions drops transitions after the given time
+// and those that do not lead to one of the given statuses.
+func filterStatusTransitions(transitions []StatusTransition, to time.Time, statuses []string) []StatusTransition {
+	if to.IsZero() && len(statuses) == 0 {
+		return transitions
+	}
+
+	statusSet := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		statusSet[status] = true
+	}
+
+	filtered := make([]StatusTransition, 0, len(transitions))
+
+	for _, transition := range transitions {
+		if !to.IsZero() && transition.StartedAt.After(to) {
+			continue
+		}
+
+		if len(statusSet) > 0 && !statusSet[transition.ToStatus] {
+			continue
+		}
+
+		filtered = append(filtered, transition)
+	}
+
+	return filtered
+}
@@ -0,0 +1,103 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildStatusTimeline(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2019, time.May, 1, 10, 0, 0, 0, time.UTC)
+	now := start.Add(2 * time.Hour)
+
+	entries := []StatusHistoryModel{
+		{ClusterID: 1, FromStatus: "", ToStatus: "CREATING", CreatedAt: start},
+		{ClusterID: 2, FromStatus: "", ToStatus: "CREATING", CreatedAt: start.Add(5 * time.Minute)},
+		{ClusterID: 1, FromStatus: "CREATING", ToStatus: "ERROR", CreatedAt: start.Add(10 * time.Minute)},
+		{ClusterID: 1, FromStatus: "ERROR", ToStatus: "RUNNING", CreatedAt: start.Add(40 * time.Minute)},
+	}
+
+	transitions := buildStatusTimeline(entries, now)
+	require.Len(t, transitions, 4)
+
+	assert.Equal(t, 10*time.Minute, transitions[0].Duration)
+	require.NotNil(t, transitions[0].EndedAt)
+	assert.Equal(t, start.Add(10*time.Minute), *transitions[0].EndedAt)
+
+	// cluster 2 is still creating
+	assert.Equal(t, 115*time.Minute, transitions[1].Duration)
+	assert.Nil(t, transitions[1].EndedAt)
+
+	assert.Equal(t, 30*time.Minute, transitions[2].Duration)
+
+	assert.Equal(t, 80*time.Minute, transitions[3].Duration)
+	assert.Nil(t, transitions[3].EndedAt)
+}
+
+func TestFilterStatusTransitions(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2019, time.May, 1, 10, 0, 0, 0, time.UTC)
+
+	transitions := []StatusTransition{
+		{ClusterID: 1, ToStatus: "CREATING", StartedAt: start},
+		{ClusterID: 1, ToStatus: "ERROR", StartedAt: start.Add(10 * time.Minute)},
+		{ClusterID: 1, ToStatus: "RUNNING", StartedAt: start.Add(40 * time.Minute)},
+	}
+
+	tests := map[string]struct {
+		to       time.Time
+		statuses []string
+		expected []string
+	}{
+		"no filter": {
+			expected: []string{"CREATING", "ERROR", "RUNNING"},
+		},
+		"until": {
+			to:       start.Add(10 * time.Minute),
+			expected: []string{"CREATING", "ERROR"},
+		},
+		"statuses": {
+			statuses: []string{"ERROR", "RUNNING"},
+			expected: []string{"ERROR", "RUNNING"},
+		},
+		"until and statuses": {
+			to:       start.Add(30 * time.Minute),
+			statuses: []string{"RUNNING"},
+			expected: []string{},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			filtered := filterStatusTransitions(transitions, test.to, test.statuses)
+
+			statuses := make([]string, 0, len(filtered))
+			for _, transition := range filtered {
+				statuses = append(statuses, transition.ToStatus)
+			}
+
+			assert.Equal(t, test.expected, statuses)
+		})
+	}
+}
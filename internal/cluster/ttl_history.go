@@ -0,0 +1,74 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/goph/emperror"
+	"github.com/jinzhu/gorm"
+)
+
+// TTLHistory stores and reads back the TTL changes of clusters.
+type TTLHistory struct {
+	db *gorm.DB
+}
+
+// NewTTLHistory returns a new TTLHistory instance.
+func NewTTLHistory(db *gorm.DB) *TTLHistory {
+	return &TTLHistory{db: db}
+}
+
+// Record saves a TTL history entry.
+func (h *TTLHistory) Record(entry *TTLHistoryModel) error {
+	err := h.db.Save(entry).Error
+	if err != nil {
+		return emperror.WrapWith(err, "could not record cluster TTL change", "clusterID", entry.ClusterID, "action", entry.Action)
+	}
+
+	return nil
+}
+
+// FindByCluster returns the TTL history of a cluster in chronological order.
+func (h *TTLHistory) FindByCluster(clusterID uint) ([]TTLHistoryModel, error) {
+	var entries []TTLHistoryModel
+
+	err := h.db.
+		Where(TTLHistoryModel{ClusterID: clusterID}).
+		Order("created_at ASC").
+		Order("id ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch cluster TTL history", "clusterID", clusterID)
+	}
+
+	return entries, nil
+}
+
+// FindLatestByAction returns the latest TTL history entry of a cluster with the given action or nil if there is none.
+func (h *TTLHistory) FindLatestByAction(clusterID uint, action string) (*TTLHistoryModel, error) {
+	var entry TTLHistoryModel
+
+	err := h.db.
+		Where(TTLHistoryModel{ClusterID: clusterID, Action: action}).
+		Order("created_at DESC").
+		Order("id DESC").
+		First(&entry).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, emperror.WrapWith(err, "could not fetch cluster TTL history", "clusterID", clusterID, "action", action)
+	}
+
+	return &entry, nil
+}
@@ -63,10 +63,7 @@ func NewCGDeploymentManager(
 	errorHandler emperror.Handler,
 ) *CGDeploymentManager {
 	return &CGDeploymentManager{
-		repository: &CGDeploymentRepository{
-			db:     db,
-			logger: logger,
-		},
+		repository:    NewCGDeploymentRepository(db, logger),
 		clusterGetter: clusterGetter,
 		logger:        logger,
 		errorHandler:  errorHandler,
@@ -27,6 +27,14 @@ type CGDeploymentRepository struct {
 	logger logrus.FieldLogger
 }
 
+// NewCGDeploymentRepository returns a new CGDeploymentRepository instance.
+func NewCGDeploymentRepository(db *gorm.DB, logger logrus.FieldLogger) *CGDeploymentRepository {
+	return &CGDeploymentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
 // FindByName returns a cluster group deployment by name.
 func (g *CGDeploymentRepository) FindByName(clusterGroupID uint, deploymentName string) (*ClusterGroupDeploymentModel, error) {
 	if len(deploymentName) == 0 {
@@ -71,6 +79,23 @@ func (g *CGDeploymentRepository) FindAll(clusterGroupID uint) ([]*ClusterGroupDe
 	return deployments, nil
 }
 
+// FindAllByOrganization returns all cluster group deployments of an organization
+func (g *CGDeploymentRepository) FindAllByOrganization(organizationName string) ([]*ClusterGroupDeploymentModel, error) {
+	var deployments []*ClusterGroupDeploymentModel
+
+	err := g.db.Preload("TargetClusters").Where(&ClusterGroupDeploymentModel{
+		OrganizationName: organizationName,
+	}).Find(&deployments).Error
+
+	if err != nil && !gorm.IsRecordNotFoundError(err) {
+		return nil, emperror.With(errors.Wrap(err, "could not fetch cluster group deployments"),
+			"organizationName", organizationName,
+		)
+	}
+
+	return deployments, nil
+}
+
 func (g *CGDeploymentRepository) Save(model *ClusterGroupDeploymentModel) error {
 	return g.db.Save(model).Error
 }
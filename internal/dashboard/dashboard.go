@@ -27,6 +27,7 @@ import (
 	"github.com/banzaicloud/pipeline/config"
 	intCluster "github.com/banzaicloud/pipeline/internal/cluster"
 	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
 	"github.com/banzaicloud/pipeline/pkg/k8sclient"
 	"github.com/banzaicloud/pipeline/pkg/k8sutil"
 	"github.com/banzaicloud/pipeline/pkg/providers"
@@ -90,6 +91,8 @@ type Cluster struct {
 	CpuUsagePercent     float64   `json:"cpuUsagePercent"`
 	StorageUsagePercent float64   `json:"storageUsagePercent"`
 	MemoryUsagePercent  float64   `json:"memoryUsagePercent"`
+
+	Labels map[string]string `json:"labels,omitempty"`
 }
 
 // GetDashboardResponse Api object to be mapped to Get dashboard request
@@ -103,6 +106,9 @@ type GetDashboardResponse struct {
 type GetDashboardPathParams struct {
 	// in:path
 	OrgId string `json:"orgid"`
+
+	// in:query
+	LabelSelector string `json:"labelSelector"`
 }
 
 // swagger:route GET /dashboard/{orgid}/clusters orgid GetDashboard
@@ -143,6 +149,26 @@ func GetDashboard(c *gin.Context) {
 		return
 	}
 
+	labelManager := cluster.NewLabelManager(intCluster.NewLabels(config.DB()))
+
+	clusters, clusterLabels, err := labelManager.SelectClusters(context.Background(), clusters, c.Query("labelSelector"))
+	if pkgErrors.IsInvalid(err) {
+		c.JSON(http.StatusBadRequest, pkgCommon.ErrorResponse{
+			Code:    http.StatusBadRequest,
+			Message: "invalid label selector",
+			Error:   err.Error(),
+		})
+		return
+	} else if err != nil {
+		errorHandler.Handle(err)
+		c.JSON(http.StatusInternalServerError, pkgCommon.ErrorResponse{
+			Code:    http.StatusInternalServerError,
+			Message: "error selecting clusters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
 	clusterResponseChan := make(chan Cluster, len(clusters))
 	defer close(clusterResponseChan)
 
@@ -152,7 +178,7 @@ func GetDashboard(c *gin.Context) {
 		if err == nil {
 			if strings.ToUpper(status.Status) == "RUNNING" {
 				logger := logger.WithField("cluster", c.GetName())
-				go getClusterDashboard(logger, c, clusterResponseChan)
+				go getClusterDashboard(logger, c, clusterLabels[c.GetID()], clusterResponseChan)
 				i++
 			}
 		}
@@ -183,7 +209,7 @@ func createNodeInfoMap(pods []v1.Pod, nodes []v1.Node) map[string]*cache.NodeInf
 	return nodeInfoMap
 }
 
-func getClusterDashboard(logger *logrus.Entry, commonCluster cluster.CommonCluster, clusterResponseChan chan Cluster) {
+func getClusterDashboard(logger *logrus.Entry, commonCluster cluster.CommonCluster, clusterLabels map[string]string, clusterResponseChan chan Cluster) {
 	nodeStates := make([]Node, 0)
 	cluster := Cluster{
 		Name:         commonCluster.GetName(),
@@ -191,6 +217,7 @@ func getClusterDashboard(logger *logrus.Entry, commonCluster cluster.CommonClust
 		Distribution: commonCluster.GetDistribution(),
 		Cloud:        commonCluster.GetCloud(),
 		Nodes:        nodeStates,
+		Labels:       clusterLabels,
 	}
 	kubeConfig, err := commonCluster.GetK8sConfig()
 	if err != nil {
@@ -18,8 +18,10 @@ import (
 	"path/filepath"
 	"sync"
 
+	"github.com/banzaicloud/pipeline/config"
 	"github.com/banzaicloud/pipeline/pkg/crypto/cert"
 	"github.com/banzaicloud/pipeline/secret"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
@@ -48,6 +50,16 @@ func newCertGenerator() *cert.Generator {
 	return generator
 }
 
+// ValidateCertSource checks whether the configured CA source can be used with the secret store backend.
+// Loading the CA from Vault requires the Vault secret store.
+func ValidateCertSource() error {
+	if viper.GetString("cert.source") == "vault" && viper.GetString(config.SecretStoreBackend) != "vault" {
+		return errors.New("cert.source=vault requires the vault secret store backend")
+	}
+
+	return nil
+}
+
 // GetCertGenerator returns the global cert generator instance.
 func GetCertGenerator() *cert.Generator {
 	certGeneratorOnce.Do(func() { certGenerator = newCertGenerator() })
@@ -0,0 +1,121 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+
+	"github.com/banzaicloud/pipeline/internal/backoff"
+)
+
+// ClusterExpiringNotification is posted to the webhook when a cluster is about to be deleted because its TTL expires.
+type ClusterExpiringNotification struct {
+	OrganizationID uint      `json:"organizationId"`
+	ClusterID      uint      `json:"clusterId"`
+	ClusterName    string    `json:"clusterName"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+	Message        string    `json:"message"`
+}
+
+type clusterExpiringEvents interface {
+	NotifyClusterExpiring(fn interface{})
+}
+
+// ClusterExpiryWebhook posts cluster expiry warnings to an HTTP endpoint.
+type ClusterExpiryWebhook struct {
+	url           string
+	client        *http.Client
+	backoffConfig backoff.ConstantBackoffConfig
+
+	errorHandler emperror.Handler
+}
+
+// NewClusterExpiryWebhook returns a new ClusterExpiryWebhook instance subscribed to the cluster expiring events.
+func NewClusterExpiryWebhook(
+	events clusterExpiringEvents,
+	url string,
+	client *http.Client,
+	backoffConfig backoff.ConstantBackoffConfig,
+	errorHandler emperror.Handler,
+) *ClusterExpiryWebhook {
+	w := &ClusterExpiryWebhook{
+		url:           url,
+		client:        client,
+		backoffConfig: backoffConfig,
+		errorHandler:  errorHandler,
+	}
+
+	events.NotifyClusterExpiring(func(orgID uint, clusterID uint, clusterName string, expiresAt time.Time) {
+		err := w.ClusterExpiring(orgID, clusterID, clusterName, expiresAt)
+		if err != nil {
+			w.errorHandler.Handle(emperror.With(err, "clusterID", clusterID))
+		}
+	})
+
+	return w
+}
+
+// ClusterExpiring posts a warning about an expiring cluster to the webhook.
+func (w *ClusterExpiryWebhook) ClusterExpiring(orgID uint, clusterID uint, clusterName string, expiresAt time.Time) error {
+	body, err := json.Marshal(ClusterExpiringNotification{
+		OrganizationID: orgID,
+		ClusterID:      clusterID,
+		ClusterName:    clusterName,
+		ExpiresAt:      expiresAt,
+		Message:        fmt.Sprintf("Cluster %s will be deleted at %s when its TTL expires", clusterName, expiresAt.UTC().Format(time.RFC3339)),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal cluster expiry notification")
+	}
+
+	return backoff.Retry(func() error {
+		return w.post(body)
+	}, backoff.NewConstantBackoffPolicy(&w.backoffConfig))
+}
+
+func (w *ClusterExpiryWebhook) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return backoff.MarkErrorPermanent(errors.Wrap(err, "failed to create webhook request"))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send cluster expiry notification")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = emperror.With(fmt.Errorf("unexpected webhook response status: %s", resp.Status), "statusCode", resp.StatusCode)
+
+	// client errors will not go away with retrying
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return backoff.MarkErrorPermanent(err)
+	}
+
+	return err
+}
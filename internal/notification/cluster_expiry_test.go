@@ -0,0 +1,78 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asaskevich/EventBus"
+	"github.com/goph/emperror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/banzaicloud/pipeline/internal/backoff"
+)
+
+func TestClusterExpiryWebhook(t *testing.T) {
+	received := make(chan ClusterExpiringNotification, 1)
+	calls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		// the first attempt fails to check that the notification is retried
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		var notification ClusterExpiringNotification
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&notification))
+
+		received <- notification
+	}))
+	defer server.Close()
+
+	eb := EventBus.New()
+
+	NewClusterExpiryWebhook(
+		NewClusterEvents(eb),
+		server.URL,
+		server.Client(),
+		backoff.ConstantBackoffConfig{Delay: time.Millisecond, MaxRetries: 3},
+		emperror.NewNoopHandler(),
+	)
+
+	expiresAt := time.Date(2019, 5, 6, 10, 0, 0, 0, time.UTC)
+
+	eb.Publish(clusterExpiringTopic, uint(1), uint(2), "my-cluster", expiresAt)
+
+	select {
+	case notification := <-received:
+		assert.Equal(t, uint(1), notification.OrganizationID)
+		assert.Equal(t, uint(2), notification.ClusterID)
+		assert.Equal(t, "my-cluster", notification.ClusterName)
+		assert.True(t, expiresAt.Equal(notification.ExpiresAt))
+		assert.Equal(t, "Cluster my-cluster will be deleted at 2019-05-06T10:00:00Z when its TTL expires", notification.Message)
+
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "no notification received")
+	}
+}
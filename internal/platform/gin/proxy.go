@@ -0,0 +1,103 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gin
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// ParseTrustedProxies parses a list of proxy addresses or CIDR ranges.
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(proxies))
+
+	for _, proxy := range proxies {
+		proxy = strings.TrimSpace(proxy)
+
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, errors.Errorf("invalid trusted proxy address: %s", proxy)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid trusted proxy range: %s", proxy)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// NewTrustedProxyMiddleware returns a middleware that replaces the remote address of requests
+// forwarded by one of the trusted proxies with the original client address.
+//
+// The router should not trust forwarding headers itself (ForwardedByClientIP),
+// otherwise any client can set its address by sending an X-Forwarded-For header.
+func NewTrustedProxyMiddleware(trustedProxies []*net.IPNet) gin.HandlerFunc {
+	trusted := func(ip net.IP) bool {
+		for _, network := range trustedProxies {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return func(c *gin.Context) {
+		host, port, err := net.SplitHostPort(strings.TrimSpace(c.Request.RemoteAddr))
+		if err != nil || !trusted(net.ParseIP(host)) {
+			return
+		}
+
+		var addresses []string
+		if forwardedFor := c.GetHeader("X-Forwarded-For"); forwardedFor != "" {
+			addresses = strings.Split(forwardedFor, ",")
+		} else if realIP := c.GetHeader("X-Real-Ip"); realIP != "" {
+			addresses = []string{realIP}
+		}
+
+		// Walk the chain backwards: the first address not added by a trusted proxy is the client
+		for i := len(addresses) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(addresses[i]))
+			if ip == nil {
+				return
+			}
+
+			if i == 0 || !trusted(ip) {
+				c.Request.RemoteAddr = net.JoinHostPort(ip.String(), port)
+
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxyMiddleware(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		remoteAddr string
+		headers    map[string]string
+		expectedIP string
+	}{
+		"direct request":          {"172.16.0.1:1234", nil, "172.16.0.1"},
+		"untrusted forwarder":     {"172.16.0.1:1234", map[string]string{"X-Forwarded-For": "10.1.2.3"}, "172.16.0.1"},
+		"trusted proxy":           {"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "172.16.0.2"}, "172.16.0.2"},
+		"proxy chain":             {"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "172.16.0.2, 192.168.1.1"}, "172.16.0.2"},
+		"spoofed chain":           {"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "10.1.2.3, 172.16.0.2"}, "172.16.0.2"},
+		"real ip header":          {"192.168.1.1:1234", map[string]string{"X-Real-Ip": "172.16.0.2"}, "172.16.0.2"},
+		"invalid forwarded value": {"10.0.0.1:1234", map[string]string{"X-Forwarded-For": "unknown"}, "10.0.0.1"},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			gin.SetMode(gin.ReleaseMode)
+			router := gin.New()
+			router.ForwardedByClientIP = false
+			router.Use(NewTrustedProxyMiddleware(trustedProxies))
+
+			var clientIP string
+			router.GET("/path", func(c *gin.Context) {
+				clientIP = c.ClientIP()
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/path", nil)
+			req.RemoteAddr = test.remoteAddr
+			for key, value := range test.headers {
+				req.Header.Set(key, value)
+			}
+
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, test.expectedIP, clientIP)
+		})
+	}
+}
+
+func TestParseTrustedProxies_Invalid(t *testing.T) {
+	_, err := ParseTrustedProxies([]string{"proxy.example.com"})
+
+	assert.Error(t, err)
+}
@@ -107,6 +107,8 @@ func CreateClusterWorkflow(ctx workflow.Context, input CreateClusterWorkflowInpu
 	postHookWorkflowInput := cluster.RunPostHooksWorkflowInput{
 		ClusterID: input.ClusterID,
 		PostHooks: cluster.BuildWorkflowPostHookFunctions(input.PostHooks, true),
+
+		OrganizationPostHooks: true,
 	}
 
 	err = workflow.ExecuteChildWorkflow(ctx, cluster.RunPostHooksWorkflowName, postHookWorkflowInput).Get(ctx, nil)
@@ -40,6 +40,7 @@ type AWSCluster interface {
 	GetAWSClient() (*session.Session, error)
 	GetBootstrapCommand(string, string, string) (string, error)
 	GetKubernetesVersion() (string, error)
+	SaveKubernetesVersion(string) error
 	SaveNetworkCloudProvider(string, string, []string) error
 	SaveNetworkApiServerAddress(string, string) error
 }
@@ -0,0 +1,76 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"context"
+
+	"github.com/goph/emperror"
+	"go.uber.org/cadence/activity"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const CordonNodeActivityName = "pke-cordon-node-activity"
+
+type CordonNodeActivity struct {
+	clusters Clusters
+}
+
+func NewCordonNodeActivity(clusters Clusters) *CordonNodeActivity {
+	return &CordonNodeActivity{
+		clusters: clusters,
+	}
+}
+
+type CordonNodeActivityInput struct {
+	ClusterID uint
+	NodeName  string
+
+	// Unschedulable cordons the node when true and uncordons it otherwise.
+	Unschedulable bool
+}
+
+func (a *CordonNodeActivity) Execute(ctx context.Context, input CordonNodeActivityInput) error {
+	logger := activity.GetLogger(ctx).Sugar().With("clusterID", input.ClusterID, "node", input.NodeName)
+
+	client, err := getClusterClient(ctx, a.clusters, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	node, err := client.CoreV1().Nodes().Get(input.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return emperror.Wrapf(err, "failed to get node %q", input.NodeName)
+	}
+
+	if node.Spec.Unschedulable == input.Unschedulable {
+		return nil
+	}
+
+	node.Spec.Unschedulable = input.Unschedulable
+
+	_, err = client.CoreV1().Nodes().Update(node)
+	if err != nil {
+		return emperror.Wrapf(err, "failed to update node %q", input.NodeName)
+	}
+
+	if input.Unschedulable {
+		logger.Info("cordoned node")
+	} else {
+		logger.Info("uncordoned node")
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/goph/emperror"
+	"go.uber.org/cadence/activity"
+	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+const DrainNodeActivityName = "pke-drain-node-activity"
+
+const drainNodePollInterval = 5 * time.Second
+
+type DrainNodeActivity struct {
+	clusters Clusters
+}
+
+func NewDrainNodeActivity(clusters Clusters) *DrainNodeActivity {
+	return &DrainNodeActivity{
+		clusters: clusters,
+	}
+}
+
+type DrainNodeActivityInput struct {
+	ClusterID uint
+	NodeName  string
+}
+
+// Execute evicts every pod from a node except the ones managed by a DaemonSet and static (mirror) pods.
+// Evictions blocked by a PodDisruptionBudget are retried until the activity times out.
+func (a *DrainNodeActivity) Execute(ctx context.Context, input DrainNodeActivityInput) error {
+	logger := activity.GetLogger(ctx).Sugar().With("clusterID", input.ClusterID, "node", input.NodeName)
+
+	client, err := getClusterClient(ctx, a.clusters, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	listOptions := metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", input.NodeName).String(),
+	}
+
+	for {
+		podList, err := client.CoreV1().Pods(metav1.NamespaceAll).List(listOptions)
+		if err != nil {
+			return emperror.Wrapf(err, "failed to list pods of node %q", input.NodeName)
+		}
+
+		pods := evictablePods(podList.Items)
+		if len(pods) == 0 {
+			logger.Info("drained node")
+
+			return nil
+		}
+
+		for _, pod := range pods {
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+
+			err := client.CoreV1().Pods(pod.Namespace).Evict(&policyv1beta1.Eviction{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      pod.Name,
+					Namespace: pod.Namespace,
+				},
+			})
+			if k8sErrors.IsTooManyRequests(err) {
+				logger.Debugw("pod eviction blocked by disruption budget", "pod", pod.Namespace+"/"+pod.Name)
+
+				continue
+			} else if err != nil && !k8sErrors.IsNotFound(err) {
+				return emperror.Wrapf(err, "failed to evict pod %s/%s", pod.Namespace, pod.Name)
+			}
+		}
+
+		activity.RecordHeartbeat(ctx, len(pods))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(drainNodePollInterval):
+		}
+	}
+}
+
+// evictablePods filters out the pods that would be recreated on the same node anyway.
+func evictablePods(pods []v1.Pod) []v1.Pod {
+	var result []v1.Pod
+
+	for _, pod := range pods {
+		if _, ok := pod.Annotations[v1.MirrorPodAnnotationKey]; ok {
+			continue
+		}
+
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+
+		if controller := metav1.GetControllerOf(&pod); controller != nil && controller.Kind == "DaemonSet" {
+			continue
+		}
+
+		result = append(result, pod)
+	}
+
+	return result
+}
@@ -0,0 +1,123 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"context"
+	"strings"
+
+	pkgCommon "github.com/banzaicloud/pipeline/pkg/common"
+	"github.com/banzaicloud/pipeline/pkg/k8sclient"
+	"github.com/goph/emperror"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const ListNodesActivityName = "pke-list-nodes-activity"
+
+// Node describes a Kubernetes node of a PKE node pool.
+type Node struct {
+	Name           string
+	InstanceID     string
+	KubeletVersion string
+	Ready          bool
+	Unschedulable  bool
+}
+
+// HasVersion checks whether the kubelet of the node runs the given Kubernetes version.
+func (n Node) HasVersion(version string) bool {
+	return strings.TrimPrefix(n.KubeletVersion, "v") == strings.TrimPrefix(version, "v")
+}
+
+type ListNodesActivity struct {
+	clusters Clusters
+}
+
+func NewListNodesActivity(clusters Clusters) *ListNodesActivity {
+	return &ListNodesActivity{
+		clusters: clusters,
+	}
+}
+
+type ListNodesActivityInput struct {
+	ClusterID    uint
+	NodePoolName string
+}
+
+func (a *ListNodesActivity) Execute(ctx context.Context, input ListNodesActivityInput) ([]Node, error) {
+	client, err := getClusterClient(ctx, a.clusters, input.ClusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	return listNodes(client.CoreV1().Nodes(), input.NodePoolName)
+}
+
+type nodeLister interface {
+	List(opts metav1.ListOptions) (*v1.NodeList, error)
+}
+
+func listNodes(nodes nodeLister, nodePoolName string) ([]Node, error) {
+	nodeList, err := nodes.List(metav1.ListOptions{LabelSelector: pkgCommon.LabelKey + "=" + nodePoolName})
+	if err != nil {
+		return nil, emperror.WrapWith(err, "failed to list nodes", "nodePool", nodePoolName)
+	}
+
+	result := make([]Node, 0, len(nodeList.Items))
+	for _, node := range nodeList.Items {
+		result = append(result, newNode(node))
+	}
+
+	return result, nil
+}
+
+func newNode(node v1.Node) Node {
+	ready := false
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			ready = condition.Status == v1.ConditionTrue
+			break
+		}
+	}
+
+	// AWS provider IDs look like aws:///<availability zone>/<instance ID>
+	instanceID := ""
+	if strings.HasPrefix(node.Spec.ProviderID, "aws://") {
+		instanceID = node.Spec.ProviderID[strings.LastIndex(node.Spec.ProviderID, "/")+1:]
+	}
+
+	return Node{
+		Name:           node.Name,
+		InstanceID:     instanceID,
+		KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+		Ready:          ready,
+		Unschedulable:  node.Spec.Unschedulable,
+	}
+}
+
+func getClusterClient(ctx context.Context, clusters Clusters, clusterID uint) (*kubernetes.Clientset, error) {
+	cluster, err := clusters.GetCluster(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	kubeConfig, err := cluster.GetK8sConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return k8sclient.NewClientFromKubeConfig(kubeConfig)
+}
@@ -105,6 +105,13 @@ func (c *Cluster) GetKubernetesVersion() (string, error) {
 	return "", errors.New(fmt.Sprintf("failed to cast cluster to AWSCluster, got type: %T", c.CommonCluster))
 }
 
+func (c *Cluster) SaveKubernetesVersion(version string) error {
+	if awscluster, ok := c.CommonCluster.(pkeworkflow.AWSCluster); ok {
+		return awscluster.SaveKubernetesVersion(version)
+	}
+	return errors.New(fmt.Sprintf("failed to cast cluster to AWSCluster, got type: %T", c.CommonCluster))
+}
+
 func (c *Cluster) SaveNetworkCloudProvider(cloudProvider, vpcID string, subnets []string) error {
 	if awscluster, ok := c.CommonCluster.(pkeworkflow.AWSCluster); ok {
 		return awscluster.SaveNetworkCloudProvider(cloudProvider, vpcID, subnets)
@@ -0,0 +1,61 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/autoscaling"
+	"github.com/goph/emperror"
+)
+
+const ReplaceNodeActivityName = "pke-replace-node-activity"
+
+// ReplaceNodeActivity terminates the instance of a worker node, so that its auto scaling group launches a new one
+// with the current launch configuration of the node pool.
+type ReplaceNodeActivity struct {
+	awsClientFactory *AWSClientFactory
+}
+
+func NewReplaceNodeActivity(awsClientFactory *AWSClientFactory) *ReplaceNodeActivity {
+	return &ReplaceNodeActivity{
+		awsClientFactory: awsClientFactory,
+	}
+}
+
+type ReplaceNodeActivityInput struct {
+	AWSActivityInput
+	InstanceID string
+}
+
+func (a *ReplaceNodeActivity) Execute(ctx context.Context, input ReplaceNodeActivityInput) error {
+	client, err := a.awsClientFactory.New(input.OrganizationID, input.SecretID, input.Region)
+	if err != nil {
+		return err
+	}
+
+	autoscalingSrv := autoscaling.New(client)
+
+	_, err = autoscalingSrv.TerminateInstanceInAutoScalingGroupWithContext(ctx, &autoscaling.TerminateInstanceInAutoScalingGroupInput{
+		InstanceId:                     aws.String(input.InstanceID),
+		ShouldDecrementDesiredCapacity: aws.Bool(false),
+	})
+	if err != nil {
+		return emperror.Wrapf(err, "terminating instance %q", input.InstanceID)
+	}
+
+	return nil
+}
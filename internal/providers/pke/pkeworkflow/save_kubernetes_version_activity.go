@@ -0,0 +1,55 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+const SaveKubernetesVersionActivityName = "pke-save-kubernetes-version-activity"
+
+// SaveKubernetesVersionActivity records the Kubernetes version of a cluster,
+// which is used by the bootstrap command of new nodes.
+type SaveKubernetesVersionActivity struct {
+	clusters Clusters
+}
+
+func NewSaveKubernetesVersionActivity(clusters Clusters) *SaveKubernetesVersionActivity {
+	return &SaveKubernetesVersionActivity{
+		clusters: clusters,
+	}
+}
+
+type SaveKubernetesVersionActivityInput struct {
+	ClusterID         uint
+	KubernetesVersion string
+}
+
+func (a *SaveKubernetesVersionActivity) Execute(ctx context.Context, input SaveKubernetesVersionActivityInput) error {
+	cluster, err := a.clusters.GetCluster(ctx, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	awsCluster, ok := cluster.(AWSCluster)
+	if !ok {
+		return errors.New(fmt.Sprintf("can't save Kubernetes version of cluster type %t", cluster))
+	}
+
+	return awsCluster.SaveKubernetesVersion(input.KubernetesVersion)
+}
@@ -0,0 +1,149 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"go.uber.org/cadence/activity"
+)
+
+const UpdatePoolVersionActivityName = "pke-update-pool-version-activity"
+
+// UpdatePoolVersionActivity updates the launch configuration of a worker node pool,
+// so that new nodes join the cluster with its current Kubernetes version.
+// Running nodes are left untouched.
+type UpdatePoolVersionActivity struct {
+	clusters       Clusters
+	tokenGenerator TokenGenerator
+}
+
+func NewUpdatePoolVersionActivity(clusters Clusters, tokenGenerator TokenGenerator) *UpdatePoolVersionActivity {
+	return &UpdatePoolVersionActivity{
+		clusters:       clusters,
+		tokenGenerator: tokenGenerator,
+	}
+}
+
+type UpdatePoolVersionActivityInput struct {
+	ClusterID       uint
+	Pool            NodePool
+	ExternalBaseUrl string
+}
+
+func (a *UpdatePoolVersionActivity) Execute(ctx context.Context, input UpdatePoolVersionActivityInput) error {
+	log := activity.GetLogger(ctx).Sugar().With("clusterID", input.ClusterID, "nodePool", input.Pool.Name)
+
+	cluster, err := a.clusters.GetCluster(ctx, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	awsCluster, ok := cluster.(AWSCluster)
+	if !ok {
+		return errors.New(fmt.Sprintf("can't update node pool of cluster type %t", cluster))
+	}
+
+	ver, err := awsCluster.GetKubernetesVersion()
+	if err != nil {
+		return emperror.Wrap(err, "can't get Kubernetes version")
+	}
+
+	_, signedToken, err := a.tokenGenerator.GenerateClusterToken(cluster.GetOrganizationId(), cluster.GetID())
+	if err != nil {
+		return emperror.Wrap(err, "can't generate Pipeline token")
+	}
+
+	bootstrapCommand, err := awsCluster.GetBootstrapCommand(input.Pool.Name, input.ExternalBaseUrl, signedToken)
+	if err != nil {
+		return emperror.Wrap(err, "failed to fetch bootstrap command")
+	}
+
+	imageID := getDefaultImageID(cluster.GetLocation(), ver)
+	if input.Pool.ImageID != "" {
+		imageID = input.Pool.ImageID
+	}
+
+	client, err := awsCluster.GetAWSClient()
+	if err != nil {
+		return emperror.Wrap(err, "failed to connect to AWS")
+	}
+
+	cfClient := cloudformation.New(client)
+
+	stackName := fmt.Sprintf("pke-pool-%s-worker-%s", cluster.GetName(), input.Pool.Name)
+
+	stacks, err := cfClient.DescribeStacksWithContext(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+	if err != nil {
+		return emperror.Wrapf(err, "describing stack %q", stackName)
+	}
+
+	var params []*cloudformation.Parameter
+	for _, param := range stacks.Stacks[0].Parameters {
+		switch aws.StringValue(param.ParameterKey) {
+		case "PkeCommand":
+			params = append(params, &cloudformation.Parameter{
+				ParameterKey:   param.ParameterKey,
+				ParameterValue: aws.String(bootstrapCommand),
+			})
+
+		case "ImageId":
+			if imageID == "" {
+				// keep the image if there is no default one for the version
+				imageID = aws.StringValue(param.ParameterValue)
+			}
+
+			params = append(params, &cloudformation.Parameter{
+				ParameterKey:   param.ParameterKey,
+				ParameterValue: aws.String(imageID),
+			})
+
+		default:
+			params = append(params, &cloudformation.Parameter{
+				ParameterKey:     param.ParameterKey,
+				UsePreviousValue: aws.Bool(true),
+			})
+		}
+	}
+
+	_, err = cfClient.UpdateStackWithContext(ctx, &cloudformation.UpdateStackInput{
+		StackName:           aws.String(stackName),
+		UsePreviousTemplate: aws.Bool(true),
+		Parameters:          params,
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "ValidationError" && strings.Contains(awsErr.Message(), "No updates are to be performed") {
+		log.Info("node pool launch configuration is up to date")
+
+		return nil
+	} else if err != nil {
+		return emperror.Wrapf(err, "updating stack %q", stackName)
+	}
+
+	err = cfClient.WaitUntilStackUpdateCompleteWithContext(ctx, &cloudformation.DescribeStacksInput{StackName: aws.String(stackName)})
+	if err != nil {
+		return emperror.Wrapf(err, "waiting for stack %q", stackName)
+	}
+
+	log.Infow("updated node pool launch configuration", "kubernetesVersion", ver)
+
+	return nil
+}
@@ -0,0 +1,347 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"fmt"
+	"time"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"go.uber.org/cadence/workflow"
+)
+
+const UpgradeClusterWorkflowName = "pke-upgrade-cluster"
+
+// Longest time an activity of the upgrade can take, including the time to schedule it.
+// Long running activities (draining, upgrading and waiting for nodes) report their progress through heartbeats.
+const (
+	upgradeActivityTimeout     = 15 * time.Minute
+	upgradeLongActivityTimeout = 35 * time.Minute
+)
+
+// UpgradeClusterWorkflowTimeout returns the execution timeout of the upgrade of a cluster with the given number of nodes.
+// Every step of the upgrade times out on its own, the workflow has to outlive all of them,
+// so that a failing step pauses (or rolls back) the upgrade instead of the whole workflow timing out with a cordoned node.
+func UpgradeClusterWorkflowTimeout(nodePools int, nodes int) time.Duration {
+	// saving the version, listing the node pools, uncordoning a failed node and rolling back
+	const clusterTimeout = 5 * upgradeActivityTimeout
+
+	// updating the launch configuration, listing the nodes and restoring the launch configuration on rollback
+	const nodePoolTimeout = upgradeLongActivityTimeout + 2*upgradeActivityTimeout
+
+	// setting the status, cordoning, draining, upgrading (or replacing), waiting for the nodes and uncordoning
+	const nodeTimeout = 3*upgradeActivityTimeout + 3*upgradeLongActivityTimeout
+
+	return clusterTimeout + time.Duration(nodePools)*nodePoolTimeout + time.Duration(nodes)*nodeTimeout
+}
+
+// UpgradeClusterWorkflowInput describes a Kubernetes version upgrade of a PKE cluster.
+//
+// The master nodes are upgraded first (in place), then the nodes of each worker pool are replaced one at a time.
+// Nodes already running the requested version are skipped, so a failed (paused) upgrade can be resumed
+// by starting the workflow again.
+type UpgradeClusterWorkflowInput struct {
+	OrganizationID      uint
+	ClusterID           uint
+	ClusterName         string
+	SecretID            string
+	Region              string
+	PipelineExternalURL string
+
+	KubernetesVersion         string
+	PreviousKubernetesVersion string
+
+	// RollbackOnFailure restores the previous Kubernetes version for new nodes if the upgrade fails.
+	// Nodes that are already upgraded keep running the new version.
+	RollbackOnFailure bool
+}
+
+// upgradeClusterState tracks the progress of an upgrade for cleaning up after a failure.
+type upgradeClusterState struct {
+	cordonedNode  string
+	upgradedPools []NodePool
+}
+
+func UpgradeClusterWorkflow(ctx workflow.Context, input UpgradeClusterWorkflowInput) error {
+	ao := workflow.ActivityOptions{
+		ScheduleToStartTimeout: 5 * time.Minute,
+		StartToCloseTimeout:    10 * time.Minute,
+		ScheduleToCloseTimeout: upgradeActivityTimeout,
+		WaitForCancellation:    true,
+	}
+
+	ctx = workflow.WithActivityOptions(ctx, ao)
+
+	var state upgradeClusterState
+
+	err := upgradeCluster(ctx, input, &state)
+	if err == nil {
+		return nil
+	}
+
+	// do not leave the failed node out of scheduling
+	if state.cordonedNode != "" {
+		uncordonErr := workflow.ExecuteActivity(ctx, CordonNodeActivityName, CordonNodeActivityInput{
+			ClusterID:     input.ClusterID,
+			NodeName:      state.cordonedNode,
+			Unschedulable: false,
+		}).Get(ctx, nil)
+		if uncordonErr != nil {
+			workflow.GetLogger(ctx).Sugar().Errorw("failed to uncordon node", "node", state.cordonedNode, "error", uncordonErr.Error())
+		}
+	}
+
+	if !input.RollbackOnFailure {
+		return emperror.Wrap(err, "Kubernetes upgrade paused")
+	}
+
+	if rollbackErr := rollbackUpgradeCluster(ctx, input, state); rollbackErr != nil {
+		return emperror.Wrapf(err, "Kubernetes upgrade failed, rollback failed: %s", rollbackErr.Error())
+	}
+
+	return emperror.Wrap(err, "Kubernetes upgrade rolled back")
+}
+
+func upgradeCluster(ctx workflow.Context, input UpgradeClusterWorkflowInput, state *upgradeClusterState) error {
+	awsActivityInput := AWSActivityInput{
+		OrganizationID: input.OrganizationID,
+		SecretID:       input.SecretID,
+		Region:         input.Region,
+	}
+
+	// long running activities report their progress through heartbeats
+	longCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		ScheduleToStartTimeout: 5 * time.Minute,
+		StartToCloseTimeout:    30 * time.Minute,
+		ScheduleToCloseTimeout: upgradeLongActivityTimeout,
+		HeartbeatTimeout:       2 * time.Minute,
+		WaitForCancellation:    true,
+	})
+
+	err := workflow.ExecuteActivity(ctx, SaveKubernetesVersionActivityName, SaveKubernetesVersionActivityInput{
+		ClusterID:         input.ClusterID,
+		KubernetesVersion: input.KubernetesVersion,
+	}).Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	var nodePools []NodePool
+	err = workflow.ExecuteActivity(ctx, ListNodePoolsActivityName, ListNodePoolsActivityInput{
+		ClusterID: input.ClusterID,
+	}).Get(ctx, &nodePools)
+	if err != nil {
+		return err
+	}
+
+	// masters first, workers after
+	var masterPools, workerPools []NodePool
+	for _, np := range nodePools {
+		if np.Master || np.Name == "master" {
+			masterPools = append(masterPools, np)
+		} else if np.Worker {
+			workerPools = append(workerPools, np)
+		}
+	}
+
+	for _, np := range masterPools {
+		var nodes []Node
+		err := workflow.ExecuteActivity(ctx, ListNodesActivityName, ListNodesActivityInput{
+			ClusterID:    input.ClusterID,
+			NodePoolName: np.Name,
+		}).Get(ctx, &nodes)
+		if err != nil {
+			return err
+		}
+
+		upgraded := countReadyNodes(nodes, input.KubernetesVersion)
+
+		for i, node := range nodes {
+			if node.HasVersion(input.KubernetesVersion) {
+				continue
+			}
+
+			setUpgradeStatus(ctx, input, fmt.Sprintf("upgrading master node %s (%d/%d)", node.Name, i+1, len(nodes)))
+
+			err := cordonAndDrainNode(ctx, longCtx, input.ClusterID, node.Name, state)
+			if err != nil {
+				return err
+			}
+
+			err = workflow.ExecuteActivity(longCtx, UpgradeMasterNodeActivityName, UpgradeMasterNodeActivityInput{
+				ClusterID:         input.ClusterID,
+				NodeName:          node.Name,
+				KubernetesVersion: input.KubernetesVersion,
+			}).Get(ctx, nil)
+			if err != nil {
+				return emperror.Wrapf(err, "upgrading master node %q", node.Name)
+			}
+
+			upgraded++
+
+			err = waitForNodes(longCtx, input, np.Name, upgraded)
+			if err != nil {
+				return err
+			}
+
+			err = workflow.ExecuteActivity(ctx, CordonNodeActivityName, CordonNodeActivityInput{
+				ClusterID:     input.ClusterID,
+				NodeName:      node.Name,
+				Unschedulable: false,
+			}).Get(ctx, nil)
+			if err != nil {
+				return err
+			}
+
+			state.cordonedNode = ""
+		}
+	}
+
+	for _, np := range workerPools {
+		err := workflow.ExecuteActivity(longCtx, UpdatePoolVersionActivityName, UpdatePoolVersionActivityInput{
+			ClusterID:       input.ClusterID,
+			Pool:            np,
+			ExternalBaseUrl: input.PipelineExternalURL,
+		}).Get(ctx, nil)
+		if err != nil {
+			return emperror.Wrapf(err, "updating launch configuration of node pool %q", np.Name)
+		}
+
+		state.upgradedPools = append(state.upgradedPools, np)
+
+		var nodes []Node
+		err = workflow.ExecuteActivity(ctx, ListNodesActivityName, ListNodesActivityInput{
+			ClusterID:    input.ClusterID,
+			NodePoolName: np.Name,
+		}).Get(ctx, &nodes)
+		if err != nil {
+			return err
+		}
+
+		upgraded := countReadyNodes(nodes, input.KubernetesVersion)
+
+		for i, node := range nodes {
+			if node.HasVersion(input.KubernetesVersion) {
+				continue
+			}
+
+			if node.InstanceID == "" {
+				return errors.Errorf("cannot find the instance of node %q", node.Name)
+			}
+
+			setUpgradeStatus(ctx, input, fmt.Sprintf("replacing node %s of node pool %s (%d/%d)", node.Name, np.Name, i+1, len(nodes)))
+
+			err := cordonAndDrainNode(ctx, longCtx, input.ClusterID, node.Name, state)
+			if err != nil {
+				return err
+			}
+
+			err = workflow.ExecuteActivity(ctx, ReplaceNodeActivityName, ReplaceNodeActivityInput{
+				AWSActivityInput: awsActivityInput,
+				InstanceID:       node.InstanceID,
+			}).Get(ctx, nil)
+			if err != nil {
+				return emperror.Wrapf(err, "replacing node %q", node.Name)
+			}
+
+			// the node is gone, nothing to uncordon
+			state.cordonedNode = ""
+			upgraded++
+
+			err = waitForNodes(longCtx, input, np.Name, upgraded)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func rollbackUpgradeCluster(ctx workflow.Context, input UpgradeClusterWorkflowInput, state upgradeClusterState) error {
+	setUpgradeStatus(ctx, input, fmt.Sprintf("rolling back Kubernetes version to %s", input.PreviousKubernetesVersion))
+
+	err := workflow.ExecuteActivity(ctx, SaveKubernetesVersionActivityName, SaveKubernetesVersionActivityInput{
+		ClusterID:         input.ClusterID,
+		KubernetesVersion: input.PreviousKubernetesVersion,
+	}).Get(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, np := range state.upgradedPools {
+		err := workflow.ExecuteActivity(ctx, UpdatePoolVersionActivityName, UpdatePoolVersionActivityInput{
+			ClusterID:       input.ClusterID,
+			Pool:            np,
+			ExternalBaseUrl: input.PipelineExternalURL,
+		}).Get(ctx, nil)
+		if err != nil {
+			return emperror.Wrapf(err, "restoring launch configuration of node pool %q", np.Name)
+		}
+	}
+
+	return nil
+}
+
+func cordonAndDrainNode(ctx workflow.Context, longCtx workflow.Context, clusterID uint, nodeName string, state *upgradeClusterState) error {
+	err := workflow.ExecuteActivity(ctx, CordonNodeActivityName, CordonNodeActivityInput{
+		ClusterID:     clusterID,
+		NodeName:      nodeName,
+		Unschedulable: true,
+	}).Get(ctx, nil)
+	if err != nil {
+		return emperror.Wrapf(err, "cordoning node %q", nodeName)
+	}
+
+	state.cordonedNode = nodeName
+
+	err = workflow.ExecuteActivity(longCtx, DrainNodeActivityName, DrainNodeActivityInput{
+		ClusterID: clusterID,
+		NodeName:  nodeName,
+	}).Get(ctx, nil)
+	if err != nil {
+		return emperror.Wrapf(err, "draining node %q", nodeName)
+	}
+
+	return nil
+}
+
+// waitForNodes is the health gate between upgrade steps.
+func waitForNodes(ctx workflow.Context, input UpgradeClusterWorkflowInput, nodePoolName string, count int) error {
+	err := workflow.ExecuteActivity(ctx, WaitForNodesActivityName, WaitForNodesActivityInput{
+		ClusterID:         input.ClusterID,
+		NodePoolName:      nodePoolName,
+		KubernetesVersion: input.KubernetesVersion,
+		Count:             count,
+	}).Get(ctx, nil)
+	if err != nil {
+		return emperror.Wrapf(err, "waiting for %d upgraded nodes in node pool %q", count, nodePoolName)
+	}
+
+	return nil
+}
+
+func setUpgradeStatus(ctx workflow.Context, input UpgradeClusterWorkflowInput, message string) {
+	err := workflow.ExecuteActivity(ctx, UpdateClusterStatusActivityName, UpdateClusterStatusActivityInput{
+		ClusterID:     input.ClusterID,
+		Status:        pkgCluster.Updating,
+		StatusMessage: fmt.Sprintf("Kubernetes upgrade to %s: %s", input.KubernetesVersion, message),
+	}).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Sugar().Errorw("failed to update cluster status", "error", err.Error())
+	}
+}
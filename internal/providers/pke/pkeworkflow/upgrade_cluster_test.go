@@ -0,0 +1,34 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpgradeClusterWorkflowTimeout(t *testing.T) {
+	t.Parallel()
+
+	small := UpgradeClusterWorkflowTimeout(2, 4)
+	large := UpgradeClusterWorkflowTimeout(2, 100)
+
+	// a single node can take every activity timeout of its steps
+	assert.True(t, small >= 4*(3*upgradeActivityTimeout+3*upgradeLongActivityTimeout))
+	assert.Equal(t, 96*(3*upgradeActivityTimeout+3*upgradeLongActivityTimeout), large-small)
+	assert.True(t, large > 6*time.Hour)
+}
@@ -0,0 +1,163 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+	"go.uber.org/cadence/activity"
+	v1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const UpgradeMasterNodeActivityName = "pke-upgrade-master-node-activity"
+
+const (
+	upgradeMasterNodeNamespace    = "kube-system"
+	upgradeMasterNodePollInterval = 10 * time.Second
+)
+
+// UpgradeMasterNodeActivity upgrades the control plane components and the kubelet of a master node in place.
+// Master nodes are not replaced, because they hold the etcd data of the cluster.
+type UpgradeMasterNodeActivity struct {
+	clusters Clusters
+}
+
+func NewUpgradeMasterNodeActivity(clusters Clusters) *UpgradeMasterNodeActivity {
+	return &UpgradeMasterNodeActivity{
+		clusters: clusters,
+	}
+}
+
+type UpgradeMasterNodeActivityInput struct {
+	ClusterID         uint
+	NodeName          string
+	KubernetesVersion string
+}
+
+// Execute runs "pke upgrade master" on the host of the node from a privileged pod and waits for it to complete.
+func (a *UpgradeMasterNodeActivity) Execute(ctx context.Context, input UpgradeMasterNodeActivityInput) error {
+	logger := activity.GetLogger(ctx).Sugar().With("clusterID", input.ClusterID, "node", input.NodeName)
+
+	client, err := getClusterClient(ctx, a.clusters, input.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	pods := client.CoreV1().Pods(upgradeMasterNodeNamespace)
+	pod := newUpgradeMasterNodePod(input.NodeName, input.KubernetesVersion)
+
+	// remove the leftover of a previous attempt
+	err = pods.Delete(pod.Name, &metav1.DeleteOptions{})
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return emperror.Wrapf(err, "failed to delete pod %q", pod.Name)
+	}
+
+	for {
+		_, err = pods.Create(pod)
+		if !k8sErrors.IsAlreadyExists(err) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(upgradeMasterNodePollInterval):
+		}
+	}
+	if err != nil {
+		return emperror.Wrapf(err, "failed to create pod %q", pod.Name)
+	}
+
+	logger.Infow("upgrading master node", "kubernetesVersion", input.KubernetesVersion)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(upgradeMasterNodePollInterval):
+		}
+
+		current, err := pods.Get(pod.Name, metav1.GetOptions{})
+		if err != nil {
+			// the API server is restarted during the upgrade
+			logger.Debugw("failed to get upgrade pod", "error", err.Error())
+			activity.RecordHeartbeat(ctx)
+
+			continue
+		}
+
+		activity.RecordHeartbeat(ctx, current.Status.Phase)
+
+		switch current.Status.Phase {
+		case v1.PodSucceeded:
+			logger.Info("master node upgraded")
+
+			_ = pods.Delete(pod.Name, &metav1.DeleteOptions{})
+
+			return nil
+
+		case v1.PodFailed:
+			return errors.Errorf("upgrading master node %q failed, see the logs of pod %s/%s", input.NodeName, upgradeMasterNodeNamespace, pod.Name)
+		}
+	}
+}
+
+func newUpgradeMasterNodePod(nodeName string, kubernetesVersion string) *v1.Pod {
+	name := "pke-upgrade-" + nodeName
+	if len(name) > 63 {
+		name = name[:63]
+	}
+
+	privileged := true
+
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: upgradeMasterNodeNamespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/name":       "pke-upgrade",
+				"app.kubernetes.io/managed-by": "pipeline",
+			},
+		},
+		Spec: v1.PodSpec{
+			NodeName:      nodeName,
+			HostPID:       true,
+			HostNetwork:   true,
+			RestartPolicy: v1.RestartPolicyNever,
+			Tolerations: []v1.Toleration{
+				{Operator: v1.TolerationOpExists},
+			},
+			Containers: []v1.Container{
+				{
+					Name:  "pke-upgrade",
+					Image: "alpine:3.9",
+					Command: []string{
+						"nsenter", "--target", "1", "--mount", "--uts", "--ipc", "--net", "--pid", "--",
+						"/usr/local/bin/pke", "upgrade", "master", fmt.Sprintf("--kubernetes-version=%s", kubernetesVersion),
+					},
+					SecurityContext: &v1.SecurityContext{
+						Privileged: &privileged,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pkeworkflow
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/cadence/activity"
+)
+
+const WaitForNodesActivityName = "pke-wait-for-nodes-activity"
+
+const waitForNodesPollInterval = 15 * time.Second
+
+// WaitForNodesActivity is the health gate between upgrade steps:
+// it waits until enough nodes of a node pool are ready with the given Kubernetes version.
+type WaitForNodesActivity struct {
+	clusters Clusters
+}
+
+func NewWaitForNodesActivity(clusters Clusters) *WaitForNodesActivity {
+	return &WaitForNodesActivity{
+		clusters: clusters,
+	}
+}
+
+type WaitForNodesActivityInput struct {
+	ClusterID         uint
+	NodePoolName      string
+	KubernetesVersion string
+
+	// Count is the number of ready nodes expected with the given Kubernetes version.
+	Count int
+}
+
+func (a *WaitForNodesActivity) Execute(ctx context.Context, input WaitForNodesActivityInput) error {
+	logger := activity.GetLogger(ctx).Sugar().With("clusterID", input.ClusterID, "nodePool", input.NodePoolName)
+
+	for {
+		// the API server may be unavailable for a while (eg. during a control plane upgrade)
+		ready, err := a.countReadyNodes(ctx, input)
+		if err != nil {
+			logger.Debugw("failed to list nodes", "error", err.Error())
+		} else if ready >= input.Count {
+			logger.Infow("nodes are ready", "kubernetesVersion", input.KubernetesVersion, "count", ready)
+
+			return nil
+		}
+
+		activity.RecordHeartbeat(ctx, ready)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(waitForNodesPollInterval):
+		}
+	}
+}
+
+func (a *WaitForNodesActivity) countReadyNodes(ctx context.Context, input WaitForNodesActivityInput) (int, error) {
+	client, err := getClusterClient(ctx, a.clusters, input.ClusterID)
+	if err != nil {
+		return 0, err
+	}
+
+	nodes, err := listNodes(client.CoreV1().Nodes(), input.NodePoolName)
+	if err != nil {
+		return 0, err
+	}
+
+	return countReadyNodes(nodes, input.KubernetesVersion), nil
+}
+
+func countReadyNodes(nodes []Node, kubernetesVersion string) int {
+	ready := 0
+	for _, node := range nodes {
+		if node.Ready && node.HasVersion(kubernetesVersion) {
+			ready++
+		}
+	}
+
+	return ready
+}
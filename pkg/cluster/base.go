@@ -96,6 +96,9 @@ const (
 	CreateClusterRoles                     = "CreateClusterRoles"
 )
 
+// OrganizationPostHookPrefix is the name prefix of user defined organization posthooks
+const OrganizationPostHookPrefix = "org:"
+
 // Provider name regexp
 const (
 	RegexpAWSName = `^[A-z0-9-_]{1,255}$`
@@ -124,6 +127,7 @@ type CreateClusterRequest struct {
 	Properties   *CreateClusterProperties `json:"properties" yaml:"properties" binding:"required"`
 	ScaleOptions *ScaleOptions            `json:"scaleOptions,omitempty" yaml:"scaleOptions,omitempty"`
 	TtlMinutes   uint                     `json:"ttlMinutes,omitempty" yaml:"ttlMinutes,omitempty"`
+	Labels       map[string]string        `json:"labels,omitempty" yaml:"labels,omitempty"`
 }
 
 // CreateClusterProperties contains the cluster flavor specific properties.
@@ -179,6 +183,15 @@ type AnchoreParam struct {
 	AllowAll string `json:"allowAll"`
 }
 
+// OrganizationPostHookParam describes the params of a user defined posthook installing a Helm chart
+type OrganizationPostHookParam struct {
+	Chart        string `json:"chart"`
+	ChartVersion string `json:"chartVersion"`
+	Namespace    string `json:"namespace"`
+	ReleaseName  string `json:"releaseName"`
+	Values       string `json:"values"`
+}
+
 func (p LoggingParam) String() string {
 	return fmt.Sprintf("bucketName: %s, region: %s, secretId: %s", p.BucketName, p.Region, p.SecretId)
 }
@@ -205,9 +218,10 @@ type GetClusterStatusResponse struct {
 	pkgCommon.CreatorBaseFields
 
 	// If region not available fall back to Location
-	Region     string     `json:"region,omitempty"`
-	TtlMinutes uint       `json:"ttlMinutes,omitempty"`
-	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	Region     string            `json:"region,omitempty"`
+	TtlMinutes uint              `json:"ttlMinutes,omitempty"`
+	StartedAt  *time.Time        `json:"startedAt,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
 }
 
 // NodePoolStatus describes cluster's node status
@@ -411,6 +425,9 @@ func (r *CreateClusterRequest) validateMainFields() error {
 			return pkgErrors.ErrorLocationEmpty
 		}
 	}
+	if err := ValidateLabels(r.Labels); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,204 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// CostNodePool describes the size of a node pool for cost calculation.
+type CostNodePool struct {
+	InstanceType string `json:"instanceType"`
+	Count        int    `json:"count"`
+	Spot         bool   `json:"spot"`
+}
+
+// CostEstimateResponse describes Pipeline's cluster cost estimate API response
+type CostEstimateResponse struct {
+	Cloud        string                          `json:"cloud"`
+	Distribution string                          `json:"distribution"`
+	Location     string                          `json:"location"`
+	HourlyCost   float64                         `json:"hourlyCost"`
+	MonthlyCost  float64                         `json:"monthlyCost"`
+	NodePools    map[string]NodePoolCostEstimate `json:"nodePools"`
+}
+
+// NodePoolCostEstimate describes the estimated cost of a node pool
+type NodePoolCostEstimate struct {
+	CostNodePool
+	UnitPrice   float64 `json:"unitPrice"`
+	HourlyCost  float64 `json:"hourlyCost"`
+	MonthlyCost float64 `json:"monthlyCost"`
+}
+
+// CostResponse describes the accumulated running cost of clusters in a time range
+type CostResponse struct {
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	OnDemandCost float64   `json:"onDemandCost"`
+	SpotCost     float64   `json:"spotCost"`
+	TotalCost    float64   `json:"totalCost"`
+
+	// Items contains the cost per cluster, node pool or label value (depending on the query)
+	Items []CostItem `json:"items,omitempty"`
+}
+
+// CostItem describes the accumulated running cost of a cluster, node pool or group of clusters
+type CostItem struct {
+	Name         string  `json:"name"`
+	ClusterID    uint    `json:"clusterId,omitempty"`
+	OnDemandCost float64 `json:"onDemandCost"`
+	SpotCost     float64 `json:"spotCost"`
+	TotalCost    float64 `json:"totalCost"`
+}
+
+// IsSpotPrice checks whether a node pool spot price setting requests spot instances
+func IsSpotPrice(spotPrice string) bool {
+	price, err := strconv.ParseFloat(spotPrice, 64)
+
+	return err == nil && price > 0
+}
+
+// GetCostDistribution returns the distribution of the cluster described by a create request
+func (r *CreateClusterRequest) GetCostDistribution() string {
+	switch {
+	case r.Properties == nil:
+		return ""
+	case r.Properties.CreateClusterEKS != nil:
+		return EKS
+	case r.Properties.CreateClusterAKS != nil:
+		return AKS
+	case r.Properties.CreateClusterGKE != nil:
+		return GKE
+	case r.Properties.CreateClusterPKE != nil:
+		return PKE
+	case r.Properties.CreateClusterACK != nil, r.Properties.CreateClusterACSK != nil:
+		return ACK
+	default:
+		return ""
+	}
+}
+
+// GetCostNodePools returns the node pool sizes of a create request
+func (r *CreateClusterRequest) GetCostNodePools() map[string]CostNodePool {
+	nodePools := make(map[string]CostNodePool)
+
+	if r.Properties == nil {
+		return nodePools
+	}
+
+	properties := r.Properties
+
+	switch {
+	case properties.CreateClusterEKS != nil:
+		for name, np := range properties.CreateClusterEKS.NodePools {
+			count := np.Count
+			if count == 0 {
+				count = np.MinCount
+			}
+			nodePools[name] = CostNodePool{InstanceType: np.InstanceType, Count: count, Spot: IsSpotPrice(np.SpotPrice)}
+		}
+
+	case properties.CreateClusterAKS != nil:
+		for name, np := range properties.CreateClusterAKS.NodePools {
+			nodePools[name] = CostNodePool{InstanceType: np.NodeInstanceType, Count: np.Count}
+		}
+
+	case properties.CreateClusterGKE != nil:
+		for name, np := range properties.CreateClusterGKE.NodePools {
+			nodePools[name] = CostNodePool{InstanceType: np.NodeInstanceType, Count: np.Count, Spot: np.Preemptible}
+		}
+
+	case properties.CreateClusterACK != nil, properties.CreateClusterACSK != nil:
+		ackRequest := properties.CreateClusterACK
+		if ackRequest == nil {
+			ackRequest = properties.CreateClusterACSK
+		}
+
+		for name, np := range ackRequest.NodePools {
+			nodePools[name] = CostNodePool{InstanceType: np.InstanceType, Count: np.MinCount}
+		}
+
+	case properties.CreateClusterPKE != nil:
+		for _, np := range properties.CreateClusterPKE.NodePools {
+			var providerConfig struct {
+				AutoScalingGroup struct {
+					InstanceType string `json:"instanceType"`
+					SpotPrice    string `json:"spotPrice"`
+					Size         struct {
+						Desired int `json:"desired"`
+						Min     int `json:"min"`
+					} `json:"size"`
+				} `json:"autoScalingGroup"`
+			}
+
+			// the provider config is validated by the cluster creation itself
+			rawConfig, _ := json.Marshal(np.ProviderConfig)
+			_ = json.Unmarshal(rawConfig, &providerConfig)
+
+			asg := providerConfig.AutoScalingGroup
+			count := asg.Size.Desired
+			if count == 0 {
+				count = asg.Size.Min
+			}
+
+			nodePools[np.Name] = CostNodePool{InstanceType: asg.InstanceType, Count: count, Spot: IsSpotPrice(asg.SpotPrice)}
+		}
+	}
+
+	return nodePools
+}
+
+// GetCostNodePools returns the node pool sizes of an update request.
+// Node pools without an instance type in the request (eg. AKS) keep their current instance type.
+func (r *UpdateClusterRequest) GetCostNodePools() map[string]CostNodePool {
+	nodePools := make(map[string]CostNodePool)
+
+	switch {
+	case r.EKS != nil:
+		for name, np := range r.EKS.NodePools {
+			nodePools[name] = CostNodePool{InstanceType: np.InstanceType, Count: np.Count, Spot: IsSpotPrice(np.SpotPrice)}
+		}
+
+	case r.AKS != nil:
+		for name, np := range r.AKS.NodePools {
+			nodePools[name] = CostNodePool{Count: np.Count}
+		}
+
+	case r.GKE != nil:
+		for name, np := range r.GKE.NodePools {
+			nodePools[name] = CostNodePool{InstanceType: np.NodeInstanceType, Count: np.Count, Spot: np.Preemptible}
+		}
+
+	case r.ACK != nil, r.ACSK != nil:
+		ackRequest := r.ACK
+		if ackRequest == nil {
+			ackRequest = r.ACSK
+		}
+
+		for name, np := range ackRequest.NodePools {
+			nodePools[name] = CostNodePool{InstanceType: np.InstanceType, Count: np.MinCount}
+		}
+
+	case r.PKE != nil:
+		for name, np := range r.PKE.NodePools {
+			nodePools[name] = CostNodePool{InstanceType: np.InstanceType, Count: np.Count, Spot: IsSpotPrice(np.SpotPrice)}
+		}
+	}
+
+	return nodePools
+}
@@ -0,0 +1,53 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		labels map[string]string
+		valid  bool
+	}{
+		"nil":           {nil, true},
+		"simple":        {map[string]string{"team": "backend", "env": "dev"}, true},
+		"prefixed key":  {map[string]string{"example.com/cost-center": "42"}, true},
+		"empty value":   {map[string]string{"team": ""}, true},
+		"empty key":     {map[string]string{"": "backend"}, false},
+		"invalid key":   {map[string]string{"team name": "backend"}, false},
+		"invalid value": {map[string]string{"team": "back end"}, false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateLabels(test.labels)
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
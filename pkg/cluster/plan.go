@@ -0,0 +1,156 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Plan actions
+const (
+	PlanActionAdd    = "add"
+	PlanActionChange = "change"
+	PlanActionRemove = "remove"
+)
+
+// ClusterPlan describes the changes a cluster create or update request would make (dry run response).
+type ClusterPlan struct {
+	Name         string `json:"name"`
+	Cloud        string `json:"cloud"`
+	Distribution string `json:"distribution,omitempty"`
+	Location     string `json:"location,omitempty"`
+
+	Version      *PlanValueChange `json:"version,omitempty"`
+	TtlMinutes   *PlanValueChange `json:"ttlMinutes,omitempty"`
+	ScaleOptions *ScaleOptions    `json:"scaleOptions,omitempty"`
+
+	NodePools []NodePoolPlan `json:"nodePools,omitempty"`
+	PostHooks []PostHookPlan `json:"postHooks,omitempty"`
+}
+
+// PlanValueChange describes the change of a single value.
+type PlanValueChange struct {
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to"`
+}
+
+// NodePoolPlan describes the planned change of a node pool.
+type NodePoolPlan struct {
+	Name    string          `json:"name"`
+	Action  string          `json:"action"`
+	Changes []string        `json:"changes,omitempty"`
+	Current *NodePoolStatus `json:"current,omitempty"`
+	Desired *NodePoolStatus `json:"desired,omitempty"`
+}
+
+// PostHookPlan describes a post hook which would be executed.
+type PostHookPlan struct {
+	Name   string        `json:"name"`
+	Action string        `json:"action"`
+	Param  PostHookParam `json:"param,omitempty"`
+}
+
+// HasChanges returns true if the plan would change anything.
+func (p *ClusterPlan) HasChanges() bool {
+	return p.Version != nil || p.TtlMinutes != nil || p.ScaleOptions != nil || len(p.NodePools) > 0 || len(p.PostHooks) > 0
+}
+
+// PlanNodePools compares the current and desired node pools and returns the planned changes ordered by name.
+// Unchanged node pools are not part of the plan.
+func PlanNodePools(current, desired map[string]*NodePoolStatus) []NodePoolPlan {
+	plans := make([]NodePoolPlan, 0)
+
+	for name, desiredNodePool := range desired {
+		if desiredNodePool == nil {
+			continue
+		}
+
+		currentNodePool, ok := current[name]
+		if !ok || currentNodePool == nil {
+			plans = append(plans, NodePoolPlan{
+				Name:    name,
+				Action:  PlanActionAdd,
+				Desired: desiredNodePool,
+			})
+
+			continue
+		}
+
+		if changes := nodePoolChanges(currentNodePool, desiredNodePool); len(changes) > 0 {
+			plans = append(plans, NodePoolPlan{
+				Name:    name,
+				Action:  PlanActionChange,
+				Changes: changes,
+				Current: currentNodePool,
+				Desired: desiredNodePool,
+			})
+		}
+	}
+
+	for name, currentNodePool := range current {
+		if desiredNodePool, ok := desired[name]; ok && desiredNodePool != nil {
+			continue
+		}
+
+		plans = append(plans, NodePoolPlan{
+			Name:    name,
+			Action:  PlanActionRemove,
+			Current: currentNodePool,
+		})
+	}
+
+	sort.Slice(plans, func(i, j int) bool {
+		return plans[i].Name < plans[j].Name
+	})
+
+	return plans
+}
+
+// nodePoolChanges returns the names of the fields that differ.
+// Optional fields not present in the desired node pool are considered unchanged.
+func nodePoolChanges(current, desired *NodePoolStatus) []string {
+	var changes []string
+
+	if current.Count != desired.Count {
+		changes = append(changes, "count")
+	}
+	if current.MinCount != desired.MinCount {
+		changes = append(changes, "minCount")
+	}
+	if current.MaxCount != desired.MaxCount {
+		changes = append(changes, "maxCount")
+	}
+	if current.Preemptible != desired.Preemptible {
+		changes = append(changes, "preemptible")
+	}
+	if desired.InstanceType != "" && current.InstanceType != desired.InstanceType {
+		changes = append(changes, "instanceType")
+	}
+	if desired.SpotPrice != "" && current.SpotPrice != desired.SpotPrice {
+		changes = append(changes, "spotPrice")
+	}
+	if desired.Image != "" && current.Image != desired.Image {
+		changes = append(changes, "image")
+	}
+	if desired.Version != "" && current.Version != desired.Version {
+		changes = append(changes, "version")
+	}
+	if desired.Labels != nil && !(len(current.Labels) == 0 && len(desired.Labels) == 0) && !reflect.DeepEqual(current.Labels, desired.Labels) {
+		changes = append(changes, "labels")
+	}
+
+	return changes
+}
@@ -0,0 +1,99 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanNodePools(t *testing.T) {
+	t.Parallel()
+
+	current := map[string]*NodePoolStatus{
+		"pool1": {Count: 1, MinCount: 1, MaxCount: 2, InstanceType: "m5.large", Labels: map[string]string{"a": "b"}},
+		"pool2": {Count: 3, MinCount: 1, MaxCount: 5, InstanceType: "m5.large"},
+		"pool3": {Count: 1, MinCount: 1, MaxCount: 1, InstanceType: "m5.xlarge"},
+	}
+
+	tests := map[string]struct {
+		desired  map[string]*NodePoolStatus
+		expected map[string][]string
+		actions  map[string]string
+	}{
+		"unchanged": {
+			desired: map[string]*NodePoolStatus{
+				"pool1": {Count: 1, MinCount: 1, MaxCount: 2},
+				"pool2": {Count: 3, MinCount: 1, MaxCount: 5},
+				"pool3": {Count: 1, MinCount: 1, MaxCount: 1},
+			},
+			expected: map[string][]string{},
+			actions:  map[string]string{},
+		},
+		"scale and relabel": {
+			desired: map[string]*NodePoolStatus{
+				"pool1": {Count: 1, MinCount: 1, MaxCount: 2, Labels: map[string]string{"a": "c"}},
+				"pool2": {Count: 4, MinCount: 1, MaxCount: 5, InstanceType: "m5.large"},
+				"pool3": {Count: 1, MinCount: 1, MaxCount: 1},
+			},
+			expected: map[string][]string{
+				"pool1": {"labels"},
+				"pool2": {"count"},
+			},
+			actions: map[string]string{
+				"pool1": PlanActionChange,
+				"pool2": PlanActionChange,
+			},
+		},
+		"add and remove": {
+			desired: map[string]*NodePoolStatus{
+				"pool1": {Count: 1, MinCount: 1, MaxCount: 2},
+				"pool2": {Count: 3, MinCount: 1, MaxCount: 5},
+				"pool4": {Count: 2, MinCount: 1, MaxCount: 3, InstanceType: "m5.large"},
+			},
+			expected: map[string][]string{
+				"pool3": nil,
+				"pool4": nil,
+			},
+			actions: map[string]string{
+				"pool3": PlanActionRemove,
+				"pool4": PlanActionAdd,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			plans := PlanNodePools(current, test.desired)
+
+			changes := make(map[string][]string, len(plans))
+			actions := make(map[string]string, len(plans))
+			for i, plan := range plans {
+				if i > 0 {
+					assert.True(t, plans[i-1].Name < plan.Name, "node pool plans should be ordered by name")
+				}
+
+				changes[plan.Name] = plan.Changes
+				actions[plan.Name] = plan.Action
+			}
+
+			assert.Equal(t, test.expected, changes)
+			assert.Equal(t, test.actions, actions)
+		})
+	}
+}
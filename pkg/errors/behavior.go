@@ -0,0 +1,30 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errors
+
+import (
+	"github.com/pkg/errors"
+)
+
+// IsInvalid checks whether the root cause of an error is about an invalid request.
+func IsInvalid(err error) bool {
+	if e, ok := errors.Cause(err).(interface {
+		IsInvalid() bool
+	}); ok {
+		return e.IsInvalid()
+	}
+
+	return false
+}
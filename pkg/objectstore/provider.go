@@ -0,0 +1,131 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package objectstore
+
+import (
+	"github.com/pkg/errors"
+
+	pkgErrors "github.com/banzaicloud/pipeline/pkg/errors"
+	"github.com/banzaicloud/pipeline/pkg/providers"
+	alibabaObjectstore "github.com/banzaicloud/pipeline/pkg/providers/alibaba/objectstore"
+	amazonObjectstore "github.com/banzaicloud/pipeline/pkg/providers/amazon/objectstore"
+	"github.com/banzaicloud/pipeline/pkg/providers/azure"
+	azureObjectstore "github.com/banzaicloud/pipeline/pkg/providers/azure/objectstore"
+	googleObjectstore "github.com/banzaicloud/pipeline/pkg/providers/google/objectstore"
+	oracleObjectstore "github.com/banzaicloud/pipeline/pkg/providers/oracle/objectstore"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+)
+
+// Config describes the cloud provider agnostic parameters of an object store client.
+type Config struct {
+	Provider string
+
+	// Region (or location) of the buckets.
+	Region string
+
+	// Azure specific parameters
+	ResourceGroup  string
+	StorageAccount string
+}
+
+// New creates an object store client for the given cloud provider
+// authenticated with the values of a secret of the same provider.
+func New(config Config, secretValues map[string]string) (ObjectStore, error) {
+	var (
+		objectStore ObjectStore
+		err         error
+	)
+
+	switch config.Provider {
+	case providers.Alibaba:
+		objectStore, err = alibabaObjectstore.New(
+			alibabaObjectstore.Config{
+				Region: config.Region,
+			},
+			alibabaObjectstore.Credentials{
+				AccessKeyID:     secretValues[pkgSecret.AlibabaAccessKeyId],
+				SecretAccessKey: secretValues[pkgSecret.AlibabaSecretAccessKey],
+			},
+		)
+
+	case providers.Amazon:
+		objectStore, err = amazonObjectstore.New(
+			amazonObjectstore.Config{
+				Region: config.Region,
+			},
+			amazonObjectstore.Credentials{
+				AccessKeyID:     secretValues[pkgSecret.AwsAccessKeyId],
+				SecretAccessKey: secretValues[pkgSecret.AwsSecretAccessKey],
+			},
+		)
+
+	case providers.Azure:
+		objectStore = azureObjectstore.New(
+			azureObjectstore.Config{
+				ResourceGroup:  config.ResourceGroup,
+				StorageAccount: config.StorageAccount,
+				Location:       config.Region,
+			},
+			*azure.NewCredentials(secretValues),
+		)
+
+	case providers.Google:
+		objectStore, err = googleObjectstore.New(
+			googleObjectstore.Config{
+				Region: config.Region,
+			},
+			googleObjectstore.Credentials{
+				Type:                   secretValues[pkgSecret.Type],
+				ProjectID:              secretValues[pkgSecret.ProjectId],
+				PrivateKeyID:           secretValues[pkgSecret.PrivateKeyId],
+				PrivateKey:             secretValues[pkgSecret.PrivateKey],
+				ClientEmail:            secretValues[pkgSecret.ClientEmail],
+				ClientID:               secretValues[pkgSecret.ClientId],
+				AuthURI:                secretValues[pkgSecret.AuthUri],
+				TokenURI:               secretValues[pkgSecret.TokenUri],
+				AuthProviderX50CertURL: secretValues[pkgSecret.AuthX509Url],
+				ClientX509CertURL:      secretValues[pkgSecret.ClientX509Url],
+			},
+		)
+
+	case providers.Oracle:
+		region := secretValues[pkgSecret.OracleRegion]
+		if config.Region != "" {
+			region = config.Region
+		}
+
+		objectStore, err = oracleObjectstore.New(
+			oracleObjectstore.Config{
+				Region: region,
+			},
+			oracleObjectstore.Credentials{
+				UserOCID:          secretValues[pkgSecret.OracleUserOCID],
+				APIKey:            secretValues[pkgSecret.OracleAPIKey],
+				APIKeyFingerprint: secretValues[pkgSecret.OracleAPIKeyFingerprint],
+				CompartmentOCID:   secretValues[pkgSecret.OracleCompartmentOCID],
+				TenancyOCID:       secretValues[pkgSecret.OracleTenancyOCID],
+			},
+		)
+
+	default:
+		return nil, pkgErrors.ErrorNotSupportedCloudType
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s object store client", config.Provider)
+	}
+
+	return objectStore, nil
+}
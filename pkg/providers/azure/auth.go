@@ -48,11 +48,20 @@ func NewCredentials(values map[string]string) *Credentials {
 
 // GetAuthorizer returns autorest Authorizer with the specified service principal in the specified environment
 func GetAuthorizer(sp *ServicePrincipal, env *azure.Environment) (autorest.Authorizer, error) {
+	return getAuthorizerForResource(sp, env, env.ServiceManagementEndpoint)
+}
+
+// GetGraphAuthorizer returns autorest Authorizer for the Azure AD Graph API with the specified service principal in the specified environment
+func GetGraphAuthorizer(sp *ServicePrincipal, env *azure.Environment) (autorest.Authorizer, error) {
+	return getAuthorizerForResource(sp, env, env.GraphEndpoint)
+}
+
+func getAuthorizerForResource(sp *ServicePrincipal, env *azure.Environment, resource string) (autorest.Authorizer, error) {
 	oauthConfig, err := adal.NewOAuthConfig(env.ActiveDirectoryEndpoint, sp.TenantID)
 	if err != nil {
 		return nil, err
 	}
-	token, err := adal.NewServicePrincipalToken(*oauthConfig, sp.ClientID, sp.ClientSecret, env.ServiceManagementEndpoint)
+	token, err := adal.NewServicePrincipalToken(*oauthConfig, sp.ClientID, sp.ClientSecret, resource)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,116 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"sync"
+	"time"
+)
+
+// Backend persists the versioned content of secrets for the secret store.
+//
+// Every write creates a new version of a secret. Writes are check-and-set operations:
+// version 0 only allows creating a secret, any other version has to match the latest version of the secret.
+type Backend interface {
+	// Read returns a version of a secret, the latest one if version is 0.
+	Read(organizationID uint, secretID string, version int) (*BackendSecret, error)
+
+	// Write stores value as the new version of a secret.
+	Write(organizationID uint, secretID string, version int, value map[string]interface{}) error
+
+	// Delete deletes a secret with all of its versions.
+	Delete(organizationID uint, secretID string) error
+
+	// List returns the IDs of the secrets of an organization.
+	List(organizationID uint) ([]string, error)
+
+	// Versions returns the versions of a secret, latest first.
+	Versions(organizationID uint, secretID string) ([]SecretVersion, error)
+}
+
+// BackendSecret is a version of a secret as it is stored by a Backend.
+type BackendSecret struct {
+	Version   int
+	CreatedAt time.Time
+	Value     map[string]interface{}
+}
+
+// lazyBackend creates the underlying backend on first use.
+type lazyBackend struct {
+	create func() (Backend, error)
+
+	once    sync.Once
+	backend Backend
+	err     error
+}
+
+// newLazyBackend returns a backend that is only created when it is first used,
+// so that importing this package does not set up the backend before the configuration and migrations are done.
+func newLazyBackend(create func() (Backend, error)) *lazyBackend {
+	return &lazyBackend{create: create}
+}
+
+func (b *lazyBackend) get() (Backend, error) {
+	b.once.Do(func() {
+		b.backend, b.err = b.create()
+	})
+
+	return b.backend, b.err
+}
+
+func (b *lazyBackend) Read(organizationID uint, secretID string, version int) (*BackendSecret, error) {
+	backend, err := b.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.Read(organizationID, secretID, version)
+}
+
+func (b *lazyBackend) Write(organizationID uint, secretID string, version int, value map[string]interface{}) error {
+	backend, err := b.get()
+	if err != nil {
+		return err
+	}
+
+	return backend.Write(organizationID, secretID, version, value)
+}
+
+func (b *lazyBackend) Delete(organizationID uint, secretID string) error {
+	backend, err := b.get()
+	if err != nil {
+		return err
+	}
+
+	return backend.Delete(organizationID, secretID)
+}
+
+func (b *lazyBackend) List(organizationID uint) ([]string, error) {
+	backend, err := b.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.List(organizationID)
+}
+
+func (b *lazyBackend) Versions(organizationID uint, secretID string) ([]SecretVersion, error) {
+	backend, err := b.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.Versions(organizationID, secretID)
+}
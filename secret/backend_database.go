@@ -0,0 +1,243 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+const secretVersionsTableName = "secret_versions"
+
+// errCASMismatch mimics the check-and-set error of Vault, so that IsCASError detects it for every backend.
+// nolint: gochecknoglobals
+var errCASMismatch = errors.New("check-and-set parameter did not match the current version")
+
+// secretVersionModel is a version of a secret with its content encrypted.
+type secretVersionModel struct {
+	ID             uint   `gorm:"primary_key"`
+	OrganizationID uint   `gorm:"not null;unique_index:idx_secret_versions_version"`
+	SecretID       string `gorm:"size:64;not null;unique_index:idx_secret_versions_version"`
+	Version        int    `gorm:"not null;unique_index:idx_secret_versions_version"`
+	Value          string `gorm:"type:text;not null"`
+	CreatedAt      time.Time
+}
+
+// TableName changes the default table name.
+func (secretVersionModel) TableName() string {
+	return secretVersionsTableName
+}
+
+// databaseBackend keeps secrets AES-GCM encrypted in the database.
+type databaseBackend struct {
+	db   *gorm.DB
+	aead cipher.AEAD
+}
+
+// NewDatabaseBackend returns a secret store backend keeping secrets in the database
+// encrypted with AES-256-GCM using the given 32 bytes long key.
+func NewDatabaseBackend(db *gorm.DB, key []byte) (Backend, error) {
+	if len(key) != 32 {
+		return nil, errors.Errorf("secret encryption key must be 32 bytes long, got %d bytes", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create secret cipher")
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create secret cipher")
+	}
+
+	return &databaseBackend{db: db, aead: aead}, nil
+}
+
+func (b *databaseBackend) Read(organizationID uint, secretID string, version int) (*BackendSecret, error) {
+	query := b.db.Where("organization_id = ? AND secret_id = ?", organizationID, secretID)
+	if version != 0 {
+		query = query.Where("version = ?", version)
+	}
+
+	var model secretVersionModel
+
+	err := query.Order("version DESC").First(&model).Error
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, ErrSecretNotExists
+	} else if err != nil {
+		return nil, err
+	}
+
+	value, err := b.decrypt(model)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackendSecret{
+		Version:   model.Version,
+		CreatedAt: model.CreatedAt,
+		Value:     value,
+	}, nil
+}
+
+func (b *databaseBackend) Write(organizationID uint, secretID string, version int, value map[string]interface{}) error {
+	latest, err := b.latestVersion(organizationID, secretID)
+	if err != nil {
+		return err
+	}
+
+	if latest != version {
+		return errCASMismatch
+	}
+
+	model := secretVersionModel{
+		OrganizationID: organizationID,
+		SecretID:       secretID,
+		Version:        version + 1,
+	}
+
+	model.Value, err = b.encrypt(model, value)
+	if err != nil {
+		return err
+	}
+
+	if err := b.db.Create(&model).Error; err != nil {
+		// the unique index rejects concurrent writes of the same version
+		if latest, lerr := b.latestVersion(organizationID, secretID); lerr == nil && latest != version {
+			return errCASMismatch
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (b *databaseBackend) Delete(organizationID uint, secretID string) error {
+	return b.db.Where("organization_id = ? AND secret_id = ?", organizationID, secretID).Delete(&secretVersionModel{}).Error
+}
+
+func (b *databaseBackend) List(organizationID uint) ([]string, error) {
+	var secretIDs []string
+
+	err := b.db.Model(&secretVersionModel{}).
+		Where("organization_id = ?", organizationID).
+		Order("secret_id").
+		Pluck("DISTINCT secret_id", &secretIDs).Error
+
+	return secretIDs, err
+}
+
+func (b *databaseBackend) Versions(organizationID uint, secretID string) ([]SecretVersion, error) {
+	var models []secretVersionModel
+
+	err := b.db.Select("version, created_at").
+		Where("organization_id = ? AND secret_id = ?", organizationID, secretID).
+		Order("version DESC").
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(models) == 0 {
+		return nil, ErrSecretNotExists
+	}
+
+	versions := make([]SecretVersion, len(models))
+	for i, model := range models {
+		versions[i] = SecretVersion{
+			Version:   model.Version,
+			CreatedAt: model.CreatedAt,
+			Current:   i == 0,
+		}
+	}
+
+	return versions, nil
+}
+
+func (b *databaseBackend) latestVersion(organizationID uint, secretID string) (int, error) {
+	var versions []int
+
+	err := b.db.Model(&secretVersionModel{}).
+		Where("organization_id = ? AND secret_id = ?", organizationID, secretID).
+		Order("version DESC").
+		Limit(1).
+		Pluck("version", &versions).Error
+	if err != nil {
+		return 0, err
+	}
+
+	if len(versions) == 0 {
+		return 0, nil
+	}
+
+	return versions[0], nil
+}
+
+// encrypt seals the value of a secret version prefixed with a random nonce.
+// The version's identity is authenticated as well, so that encrypted values cannot be moved between secrets.
+func (b *databaseBackend) encrypt(model secretVersionModel, value map[string]interface{}) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to encode secret")
+	}
+
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", errors.Wrap(err, "failed to generate nonce")
+	}
+
+	ciphertext := b.aead.Seal(nonce, nonce, plaintext, additionalData(model))
+
+	return base64.StdEncoding.EncodeToString(ciphertext), nil
+}
+
+func (b *databaseBackend) decrypt(model secretVersionModel) (map[string]interface{}, error) {
+	ciphertext, err := base64.StdEncoding.DecodeString(model.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode secret")
+	}
+
+	nonceSize := b.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return nil, errors.New("encrypted secret is too short")
+	}
+
+	plaintext, err := b.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], additionalData(model))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt secret")
+	}
+
+	var value map[string]interface{}
+	if err := json.Unmarshal(plaintext, &value); err != nil {
+		return nil, errors.Wrap(err, "failed to decode secret")
+	}
+
+	return value, nil
+}
+
+func additionalData(model secretVersionModel) []byte {
+	return []byte(fmt.Sprintf("%d/%s/%d", model.OrganizationID, model.SecretID, model.Version))
+}
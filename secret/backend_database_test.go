@@ -0,0 +1,109 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestNewDatabaseBackend_InvalidKey(t *testing.T) {
+	if _, err := NewDatabaseBackend(nil, []byte("too short")); err == nil {
+		t.Error("Expected error for an encryption key of invalid length")
+	}
+}
+
+func TestDatabaseBackend_Encryption(t *testing.T) {
+	backend, err := NewDatabaseBackend(nil, bytes.Repeat([]byte{42}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := backend.(*databaseBackend)
+
+	model := secretVersionModel{OrganizationID: 1, SecretID: "secret", Version: 2}
+	value := map[string]interface{}{
+		"name":   "secret",
+		"values": map[string]interface{}{"password": "s3cr3t"},
+		"tags":   []interface{}{"test"},
+	}
+
+	encrypted, err := b.encrypt(model, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains([]byte(encrypted), []byte("s3cr3t")) {
+		t.Error("Expected the value to be encrypted")
+	}
+
+	if again, _ := b.encrypt(model, value); again == encrypted {
+		t.Error("Expected a new nonce for every encryption")
+	}
+
+	model.Value = encrypted
+
+	decrypted, err := b.decrypt(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(decrypted, value) {
+		t.Errorf("Expected decrypted value: %+v, but got: %+v", value, decrypted)
+	}
+
+	moved := model
+	moved.SecretID = "other"
+
+	if _, err := b.decrypt(moved); err == nil {
+		t.Error("Expected error decrypting the value of another secret")
+	}
+
+	other, err := NewDatabaseBackend(nil, bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := other.(*databaseBackend).decrypt(model); err == nil {
+		t.Error("Expected error decrypting with another key")
+	}
+}
+
+func TestLazyBackend(t *testing.T) {
+	var created int
+
+	backend := newLazyBackend(func() (Backend, error) {
+		created++
+
+		return NewDatabaseBackend(nil, []byte("too short"))
+	})
+
+	if created != 0 {
+		t.Fatal("Expected the backend to be created on first use")
+	}
+
+	if _, err := backend.List(1); err == nil {
+		t.Error("Expected the creation error to be returned")
+	}
+
+	if err := backend.Delete(1, "secret"); err == nil {
+		t.Error("Expected the creation error to be returned")
+	}
+
+	if created != 1 {
+		t.Errorf("Expected the backend to be created once, got %d", created)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/banzaicloud/bank-vaults/pkg/vault"
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+// vaultBackend keeps secrets in the KV version 2 secret engine of Vault.
+type vaultBackend struct {
+	logical *vaultapi.Logical
+}
+
+// NewVaultBackend returns a secret store backend keeping secrets in Vault.
+func NewVaultBackend(logical *vaultapi.Logical) Backend {
+	return &vaultBackend{logical: logical}
+}
+
+func (b *vaultBackend) Read(organizationID uint, secretID string, version int) (*BackendSecret, error) {
+	path := secretDataPath(organizationID, secretID)
+
+	var secret *vaultapi.Secret
+	var err error
+
+	if version == 0 {
+		secret, err = b.logical.Read(path)
+	} else {
+		secret, err = b.logical.ReadWithData(path, map[string][]string{"version": {strconv.Itoa(version)}})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// deleted and destroyed versions are returned without data
+	if secret == nil || secret.Data["data"] == nil {
+		return nil, ErrSecretNotExists
+	}
+
+	return parseVaultSecret(secret)
+}
+
+func (b *vaultBackend) Write(organizationID uint, secretID string, version int, value map[string]interface{}) error {
+	_, err := b.logical.Write(secretDataPath(organizationID, secretID), vault.NewData(version, map[string]interface{}{"value": value}))
+
+	return err
+}
+
+func (b *vaultBackend) Delete(organizationID uint, secretID string) error {
+	_, err := b.logical.Delete(secretMetadataPath(organizationID, secretID))
+
+	return err
+}
+
+func (b *vaultBackend) List(organizationID uint) ([]string, error) {
+	list, err := b.logical.List(fmt.Sprintf("secret/metadata/orgs/%d", organizationID))
+	if err != nil {
+		return nil, err
+	}
+
+	if list == nil {
+		return nil, nil
+	}
+
+	return cast.ToStringSlice(list.Data["keys"]), nil
+}
+
+func (b *vaultBackend) Versions(organizationID uint, secretID string) ([]SecretVersion, error) {
+	metadata, err := b.logical.Read(secretMetadataPath(organizationID, secretID))
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata == nil {
+		return nil, ErrSecretNotExists
+	}
+
+	return parseSecretVersions(metadata.Data)
+}
+
+func parseVaultSecret(secret *vaultapi.Secret) (*BackendSecret, error) {
+	data := cast.ToStringMap(secret.Data["data"])
+	metadata := cast.ToStringMap(secret.Data["metadata"])
+
+	version, _ := metadata["version"].(json.Number).Int64()
+
+	createdAt, err := time.Parse(time.RFC3339, cast.ToString(metadata["created_time"]))
+	if err != nil {
+		return nil, err
+	}
+
+	return &BackendSecret{
+		Version:   int(version),
+		CreatedAt: createdAt,
+		Value:     cast.ToStringMap(data["value"]),
+	}, nil
+}
+
+// parseSecretVersions parses the version list of a KV v2 metadata response.
+func parseSecretVersions(metadata map[string]interface{}) ([]SecretVersion, error) {
+	var currentVersion int64
+	if number, ok := metadata["current_version"].(json.Number); ok {
+		currentVersion, _ = number.Int64()
+	}
+
+	var versions []SecretVersion
+
+	for key, value := range cast.ToStringMap(metadata["versions"]) {
+		number, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid secret version: %s", key)
+		}
+
+		versionMetadata := cast.ToStringMap(value)
+
+		createdAt, err := time.Parse(time.RFC3339, cast.ToString(versionMetadata["created_time"]))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid creation time of secret version: %s", key)
+		}
+
+		destroyed, _ := versionMetadata["destroyed"].(bool)
+
+		versions = append(versions, SecretVersion{
+			Version:   number,
+			CreatedAt: createdAt,
+			Current:   int64(number) == currentVersion,
+			Deleted:   destroyed || cast.ToString(versionMetadata["deletion_time"]) != "",
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+
+	return versions, nil
+}
+
+func secretDataPath(organizationID uint, secretID string) string {
+	return fmt.Sprintf("secret/data/orgs/%d/%s", organizationID, secretID)
+}
+
+func secretMetadataPath(organizationID uint, secretID string) string {
+	return fmt.Sprintf("secret/metadata/orgs/%d/%s", organizationID, secretID)
+}
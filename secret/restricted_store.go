@@ -59,6 +59,30 @@ func (s *restrictedSecretStore) Delete(organizationID uint, secretID string) err
 	return s.secretStore.Delete(organizationID, secretID)
 }
 
+func (s *restrictedSecretStore) ListVersions(organizationID uint, secretID string) ([]SecretVersion, error) {
+	if err := s.checkForbiddenTags(organizationID, secretID); err != nil {
+		return nil, err
+	}
+
+	return s.secretStore.ListVersions(organizationID, secretID)
+}
+
+func (s *restrictedSecretStore) GetVersion(organizationID uint, secretID string, version int) (*SecretItemResponse, error) {
+	if err := s.checkForbiddenTags(organizationID, secretID); err != nil {
+		return nil, err
+	}
+
+	return s.secretStore.GetVersion(organizationID, secretID, version)
+}
+
+func (s *restrictedSecretStore) Rollback(organizationID uint, secretID string, version int, updatedBy string) error {
+	if err := s.checkBlockingTags(organizationID, secretID); err != nil {
+		return err
+	}
+
+	return s.secretStore.Rollback(organizationID, secretID, version, updatedBy)
+}
+
 func (s *restrictedSecretStore) checkBlockingTags(organizationID uint, secretID string) error {
 
 	secretItem, err := s.secretStore.Get(organizationID, secretID)
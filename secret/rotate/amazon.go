@@ -0,0 +1,102 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotate
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/goph/emperror"
+
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+	"github.com/banzaicloud/pipeline/secret/verify"
+)
+
+// awsRotator rotates the access key of the IAM user an Amazon secret belongs to
+type awsRotator struct{}
+
+func (r *awsRotator) CurrentKey(values map[string]string, tags []string) string {
+	return values[pkgSecret.AwsAccessKeyId]
+}
+
+func (r *awsRotator) CreateKey(ctx context.Context, values map[string]string, tags []string) (map[string]string, []string, string, error) {
+	client, err := newIAMClient(values)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	userName, err := getAccessKeyUser(ctx, client, values[pkgSecret.AwsAccessKeyId])
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	output, err := client.CreateAccessKeyWithContext(ctx, &iam.CreateAccessKeyInput{
+		UserName: userName,
+	})
+	if err != nil {
+		return nil, nil, "", emperror.WrapWith(err, "failed to create access key", "user", aws.StringValue(userName))
+	}
+
+	newValues := copyValues(values)
+	newValues[pkgSecret.AwsAccessKeyId] = aws.StringValue(output.AccessKey.AccessKeyId)
+	newValues[pkgSecret.AwsSecretAccessKey] = aws.StringValue(output.AccessKey.SecretAccessKey)
+
+	return newValues, tags, aws.StringValue(output.AccessKey.AccessKeyId), nil
+}
+
+func (r *awsRotator) RevokeKey(ctx context.Context, values map[string]string, keyID string) error {
+	client, err := newIAMClient(values)
+	if err != nil {
+		return err
+	}
+
+	userName, err := getAccessKeyUser(ctx, client, keyID)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.DeleteAccessKeyWithContext(ctx, &iam.DeleteAccessKeyInput{
+		AccessKeyId: aws.String(keyID),
+		UserName:    userName,
+	})
+
+	return emperror.WrapWith(err, "failed to delete access key", "accessKeyId", keyID)
+}
+
+func newIAMClient(values map[string]string) (*iam.IAM, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Credentials: verify.CreateAWSCredentials(values),
+		Region:      aws.String(verify.DefaultRegion),
+	})
+	if err != nil {
+		return nil, emperror.Wrap(err, "failed to create AWS session")
+	}
+
+	return iam.New(sess), nil
+}
+
+// getAccessKeyUser returns the name of the IAM user owning an access key
+func getAccessKeyUser(ctx context.Context, client *iam.IAM, accessKeyID string) (*string, error) {
+	output, err := client.GetAccessKeyLastUsedWithContext(ctx, &iam.GetAccessKeyLastUsedInput{
+		AccessKeyId: aws.String(accessKeyID),
+	})
+	if err != nil {
+		return nil, emperror.WrapWith(err, "failed to get access key owner", "accessKeyId", accessKeyID)
+	}
+
+	return output.UserName, nil
+}
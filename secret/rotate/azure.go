@@ -0,0 +1,187 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotate
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/services/graphrbac/1.6/graphrbac"
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/Azure/go-autorest/autorest/date"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/gofrs/uuid"
+	"github.com/goph/emperror"
+	"github.com/pkg/errors"
+
+	pkgAzure "github.com/banzaicloud/pipeline/pkg/providers/azure"
+	pkgSecret "github.com/banzaicloud/pipeline/pkg/secret"
+)
+
+const (
+	// TagAzureClientSecretKeyID is the prefix of the tag recording which client secret of an application is stored in a secret.
+	// Client secrets created outside of Pipeline cannot be told apart, so they are left in place after the first rotation.
+	TagAzureClientSecretKeyID = "azure:clientSecretKeyId:"
+
+	azureClientSecretLength   = 32
+	azureClientSecretValidity = 2 * 365 * 24 * time.Hour
+)
+
+// azureRotator rotates the client secret of the application an Azure service principal secret belongs to.
+// The service principal needs permission to manage its own application in Azure AD.
+type azureRotator struct {
+	env *azure.Environment
+}
+
+func newAzureRotator() *azureRotator {
+	return &azureRotator{
+		env: &azure.PublicCloud,
+	}
+}
+
+func (r *azureRotator) CurrentKey(values map[string]string, tags []string) string {
+	for _, tag := range tags {
+		if strings.HasPrefix(tag, TagAzureClientSecretKeyID) {
+			return strings.TrimPrefix(tag, TagAzureClientSecretKeyID)
+		}
+	}
+
+	return ""
+}
+
+func (r *azureRotator) CreateKey(ctx context.Context, values map[string]string, tags []string) (map[string]string, []string, string, error) {
+	client, objectID, err := r.getApplication(ctx, values)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	credentials, err := client.ListPasswordCredentials(ctx, objectID)
+	if err != nil {
+		return nil, nil, "", emperror.Wrap(err, "failed to list client secrets")
+	}
+
+	keyID, err := uuid.NewV4()
+	if err != nil {
+		return nil, nil, "", emperror.Wrap(err, "failed to generate key ID")
+	}
+
+	clientSecret, err := generateClientSecret()
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	now := time.Now()
+	passwordCredentials := []graphrbac.PasswordCredential{
+		{
+			KeyID:     to.StringPtr(keyID.String()),
+			Value:     to.StringPtr(clientSecret),
+			StartDate: &date.Time{Time: now},
+			EndDate:   &date.Time{Time: now.Add(azureClientSecretValidity)},
+		},
+	}
+	if credentials.Value != nil {
+		passwordCredentials = append(passwordCredentials, *credentials.Value...)
+	}
+
+	_, err = client.UpdatePasswordCredentials(ctx, objectID, graphrbac.PasswordCredentialsUpdateParameters{
+		Value: &passwordCredentials,
+	})
+	if err != nil {
+		return nil, nil, "", emperror.Wrap(err, "failed to create client secret")
+	}
+
+	newValues := copyValues(values)
+	newValues[pkgSecret.AzureClientSecret] = clientSecret
+
+	return newValues, setClientSecretKeyIDTag(tags, keyID.String()), keyID.String(), nil
+}
+
+func (r *azureRotator) RevokeKey(ctx context.Context, values map[string]string, keyID string) error {
+	client, objectID, err := r.getApplication(ctx, values)
+	if err != nil {
+		return err
+	}
+
+	credentials, err := client.ListPasswordCredentials(ctx, objectID)
+	if err != nil {
+		return emperror.Wrap(err, "failed to list client secrets")
+	}
+
+	passwordCredentials := []graphrbac.PasswordCredential{}
+	if credentials.Value != nil {
+		for _, credential := range *credentials.Value {
+			if credential.KeyID == nil || *credential.KeyID != keyID {
+				passwordCredentials = append(passwordCredentials, credential)
+			}
+		}
+	}
+
+	_, err = client.UpdatePasswordCredentials(ctx, objectID, graphrbac.PasswordCredentialsUpdateParameters{
+		Value: &passwordCredentials,
+	})
+
+	return emperror.WrapWith(err, "failed to delete client secret", "keyId", keyID)
+}
+
+// getApplication returns a client for the applications of the tenant and the object ID of the application the credentials belong to
+func (r *azureRotator) getApplication(ctx context.Context, values map[string]string) (graphrbac.ApplicationsClient, string, error) {
+	credentials := pkgAzure.NewCredentials(values)
+
+	client := graphrbac.NewApplicationsClientWithBaseURI(r.env.GraphEndpoint, credentials.TenantID)
+
+	authorizer, err := pkgAzure.GetGraphAuthorizer(&credentials.ServicePrincipal, r.env)
+	if err != nil {
+		return client, "", emperror.Wrap(err, "failed to authenticate to Azure AD Graph")
+	}
+	client.Authorizer = authorizer
+
+	applications, err := client.List(ctx, fmt.Sprintf("appId eq '%s'", credentials.ClientID))
+	if err != nil {
+		return client, "", emperror.WrapWith(err, "failed to get application", "clientId", credentials.ClientID)
+	}
+
+	for _, application := range applications.Values() {
+		if application.ObjectID != nil {
+			return client, *application.ObjectID, nil
+		}
+	}
+
+	return client, "", errors.Errorf("application %s not found", credentials.ClientID)
+}
+
+// setClientSecretKeyIDTag replaces the key ID tag of the stored client secret
+func setClientSecretKeyIDTag(tags []string, keyID string) []string {
+	newTags := make([]string, 0, len(tags)+1)
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, TagAzureClientSecretKeyID) {
+			newTags = append(newTags, tag)
+		}
+	}
+
+	return append(newTags, TagAzureClientSecretKeyID+keyID)
+}
+
+func generateClientSecret() (string, error) {
+	buf := make([]byte, azureClientSecretLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", emperror.Wrap(err, "failed to generate client secret")
+	}
+
+	return base64.StdEncoding.EncodeToString(buf), nil
+}
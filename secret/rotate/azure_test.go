@@ -0,0 +1,61 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAzureRotator_CurrentKey(t *testing.T) {
+	cases := map[string]struct {
+		tags     []string
+		expected string
+	}{
+		"tagged": {
+			tags:     []string{"banzai:readonly", TagAzureClientSecretKeyID + "0b1c0e2a-5d3f-4d5e-9a57-3c0e8d9a8f21"},
+			expected: "0b1c0e2a-5d3f-4d5e-9a57-3c0e8d9a8f21",
+		},
+		"untagged": {
+			tags:     []string{"banzai:readonly"},
+			expected: "",
+		},
+	}
+
+	for name, test := range cases {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if key := newAzureRotator().CurrentKey(nil, test.tags); key != test.expected {
+				t.Errorf("expected key %q, got %q", test.expected, key)
+			}
+		})
+	}
+}
+
+func TestSetClientSecretKeyIDTag(t *testing.T) {
+	tags := []string{"team:infra", TagAzureClientSecretKeyID + "old"}
+
+	expected := []string{"team:infra", TagAzureClientSecretKeyID + "new"}
+	if newTags := setClientSecretKeyIDTag(tags, "new"); !reflect.DeepEqual(newTags, expected) {
+		t.Errorf("expected tags %v, got %v", expected, newTags)
+	}
+
+	if tags[1] != TagAzureClientSecretKeyID+"old" {
+		t.Errorf("original tags must not be modified, got %v", tags)
+	}
+}
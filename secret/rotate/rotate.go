@@ -0,0 +1,60 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rotate
+
+import (
+	"context"
+
+	pkgCluster "github.com/banzaicloud/pipeline/pkg/cluster"
+)
+
+// Rotator replaces the credential stored in a secret with a newly created one
+type Rotator interface {
+	// CurrentKey returns the identifier of the credential stored in a secret or an empty string if it is unknown
+	CurrentKey(values map[string]string, tags []string) string
+
+	// CreateKey creates a new credential for the identity a secret belongs to
+	// and returns the secret values and tags referring to the new credential
+	CreateKey(ctx context.Context, values map[string]string, tags []string) (newValues map[string]string, newTags []string, keyID string, err error)
+
+	// RevokeKey revokes a credential of the identity a secret belongs to, authenticating with the given values
+	RevokeKey(ctx context.Context, values map[string]string, keyID string) error
+}
+
+// NewRotator returns a Rotator for the given secret type or nil if the credentials of the type cannot be rotated
+func NewRotator(secretType string) Rotator {
+	switch secretType {
+	case pkgCluster.Amazon:
+		return &awsRotator{}
+	case pkgCluster.Azure:
+		return newAzureRotator()
+	default:
+		return nil
+	}
+}
+
+// IsSupported returns true if the credentials of the given secret type can be rotated
+func IsSupported(secretType string) bool {
+	return NewRotator(secretType) != nil
+}
+
+func copyValues(values map[string]string) map[string]string {
+	newValues := make(map[string]string, len(values))
+	for key, value := range values {
+		newValues[key] = value
+	}
+
+	return newValues
+}
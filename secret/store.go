@@ -30,13 +30,13 @@ import (
 
 	"github.com/banzaicloud/bank-vaults/pkg/tls"
 	"github.com/banzaicloud/bank-vaults/pkg/vault"
+	"github.com/banzaicloud/pipeline/config"
 	secretTypes "github.com/banzaicloud/pipeline/pkg/secret"
 	"github.com/banzaicloud/pipeline/secret/verify"
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"github.com/spf13/cast"
 	"github.com/spf13/viper"
 	"golang.org/x/crypto/bcrypt"
 	"k8s.io/apimachinery/pkg/util/validation"
@@ -48,7 +48,7 @@ const (
 	PublicKeyBlockType     = "PUBLIC KEY"
 )
 
-// Store object that wraps up vault logical store
+// Store object that wraps up the configured secret store backend
 // nolint: gochecknoglobals
 var Store *secretStore
 
@@ -61,11 +61,14 @@ var RestrictedStore *restrictedSecretStore
 var ErrSecretNotExists = fmt.Errorf("There's no secret with this ID")
 
 func init() {
-	Store = newVaultSecretStore()
+	Store = newSecretStore()
 	RestrictedStore = &restrictedSecretStore{Store}
 }
 
 type secretStore struct {
+	backend Backend
+
+	// Client and Logical are only available when secrets are kept in Vault
 	Client  *vault.Client
 	Logical *vaultapi.Logical
 }
@@ -82,7 +85,7 @@ type CreateSecretResponse struct {
 }
 
 // CreateSecretRequest param for Store.Store
-// Only fields with `mapstructure` tag are getting written to Vault
+// Only fields with `mapstructure` tag are getting written to the secret store
 type CreateSecretRequest struct {
 	Name      string            `json:"name" binding:"required" mapstructure:"name"`
 	Type      string            `json:"type" binding:"required" mapstructure:"type"`
@@ -147,6 +150,24 @@ type AllowedFilteredSecretTypesResponse struct {
 // AllowedSecretTypesResponse for API response for AllowedSecretTypes
 type AllowedSecretTypesResponse map[string]secretTypes.Meta
 
+// NewStore returns a secret store keeping secrets in the given backend.
+func NewStore(backend Backend) *secretStore {
+	return &secretStore{backend: backend}
+}
+
+func newSecretStore() *secretStore {
+	switch backend := viper.GetString(config.SecretStoreBackend); backend {
+	case "vault":
+		return newVaultSecretStore()
+
+	case "database":
+		return newDatabaseSecretStore()
+
+	default:
+		panic(fmt.Sprintf("unknown secret store backend: %s", backend))
+	}
+}
+
 func newVaultSecretStore() *secretStore {
 	role := "pipeline"
 	client, err := vault.NewClient(role)
@@ -154,7 +175,21 @@ func newVaultSecretStore() *secretStore {
 		panic(err)
 	}
 	logical := client.Vault().Logical()
-	return &secretStore{Client: client, Logical: logical}
+	return &secretStore{backend: NewVaultBackend(logical), Client: client, Logical: logical}
+}
+
+// newDatabaseSecretStore returns a secret store keeping secrets in the database.
+// The backend is created on first use: the store is set up at import time,
+// when the configuration is not final yet and the secret tables may not exist.
+func newDatabaseSecretStore() *secretStore {
+	return NewStore(newLazyBackend(func() (Backend, error) {
+		key, err := base64.StdEncoding.DecodeString(viper.GetString(config.SecretStoreEncryptionKey))
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid secret encryption key")
+		}
+
+		return NewDatabaseBackend(config.DB(), key)
+	}))
 }
 
 // GenerateSecretIDFromName generates a "unique by name per organization" id for Secrets
@@ -269,25 +304,23 @@ func (ss *secretStore) DeleteByClusterUID(orgID uint, clusterUID string) error {
 // Delete secret secret/orgs/:orgid:/:id: scope
 func (ss *secretStore) Delete(organizationID uint, secretID string) error {
 
-	path := secretMetadataPath(organizationID, secretID)
-
-	log.Debugln("Delete secret:", path)
+	log.Debugln("Delete secret:", secretID)
 
 	secret, err := ss.Get(organizationID, secretID)
 	if err != nil {
 		return errors.Wrap(err, "Error during querying secret before deletion")
 	}
 
-	if _, err := ss.Logical.Delete(path); err != nil {
+	if err := ss.backend.Delete(organizationID, secretID); err != nil {
 		return errors.Wrap(err, "Error during deleting secret")
 	}
 
 	// if type is distribution, unmount all pki engines
-	if secret.Type == secretTypes.PKESecretType {
+	if secret.Type == secretTypes.PKESecretType && ss.Client != nil {
 		clusterID := getClusterIDFromTags(secret.Tags)
 		basePath := clusterPKIPath(organizationID, clusterID)
 
-		path = fmt.Sprintf("%s/ca", basePath)
+		path := fmt.Sprintf("%s/ca", basePath)
 		err = ss.Client.Vault().Sys().Unmount(path)
 		if err != nil {
 			log.Warnf("failed to unmount %s: %s", path, err)
@@ -324,7 +357,6 @@ func (ss *secretStore) Store(organizationID uint, request *CreateSecretRequest)
 	}
 
 	secretID := GenerateSecretID(request)
-	path := secretDataPath(organizationID, secretID)
 
 	if err := ss.generateValuesIfNeeded(organizationID, request); err != nil {
 		return "", err
@@ -332,12 +364,12 @@ func (ss *secretStore) Store(organizationID uint, request *CreateSecretRequest)
 
 	sort.Strings(request.Tags)
 
-	data, err := secretData(0, request)
+	data, err := secretData(request)
 	if err != nil {
 		return "", err
 	}
 
-	if _, err := ss.Logical.Write(path, data); err != nil {
+	if err := ss.backend.Write(organizationID, secretID, 0, data); err != nil {
 		return "", errors.Wrap(err, "Error during storing secret")
 	}
 
@@ -351,9 +383,7 @@ func (ss *secretStore) Update(organizationID uint, secretID string, request *Cre
 		return errors.New("Secret name cannot be changed")
 	}
 
-	path := secretDataPath(organizationID, secretID)
-
-	log.Debugln("Update secret:", path)
+	log.Debugln("Update secret:", secretID)
 
 	sort.Strings(request.Tags)
 
@@ -363,12 +393,12 @@ func (ss *secretStore) Update(organizationID uint, secretID string, request *Cre
 		version = *request.Version
 	}
 
-	data, err := secretData(version, request)
+	data, err := secretData(request)
 	if err != nil {
 		return err
 	}
 
-	if _, err := ss.Logical.Write(path, data); err != nil {
+	if err := ss.backend.Write(organizationID, secretID, version, data); err != nil {
 		return errors.Wrap(err, "Error during updating secret")
 	}
 
@@ -421,26 +451,16 @@ func (ss *secretStore) CreateOrUpdate(organizationID uint, value *CreateSecretRe
 	return secretID, nil
 }
 
-func parseSecret(secretID string, secret *vaultapi.Secret, values bool) (*SecretItemResponse, error) {
-
-	data := cast.ToStringMap(secret.Data["data"])
-	metadata := cast.ToStringMap(secret.Data["metadata"])
-
-	version, _ := metadata["version"].(json.Number).Int64()
-
-	updatedAt, err := time.Parse(time.RFC3339, metadata["created_time"].(string))
-	if err != nil {
-		return nil, err
-	}
+func parseSecret(secretID string, secret *BackendSecret, values bool) (*SecretItemResponse, error) {
 
 	response := SecretItemResponse{
 		ID:        secretID,
-		Version:   int(version),
-		UpdatedAt: updatedAt,
+		Version:   secret.Version,
+		UpdatedAt: secret.CreatedAt,
 		Tags:      []string{},
 	}
 
-	if err := mapstructure.Decode(data["value"], &response); err != nil {
+	if err := mapstructure.Decode(secret.Value, &response); err != nil {
 		return nil, err
 	}
 
@@ -457,18 +477,13 @@ func parseSecret(secretID string, secret *vaultapi.Secret, values bool) (*Secret
 // Retrieve secret secret/orgs/:orgid:/:id: scope
 func (ss *secretStore) Get(organizationID uint, secretID string) (*SecretItemResponse, error) {
 
-	path := secretDataPath(organizationID, secretID)
-
-	secret, err := ss.Logical.Read(path)
-
-	if err != nil {
+	secret, err := ss.backend.Read(organizationID, secretID, 0)
+	if err == ErrSecretNotExists {
+		return nil, err
+	} else if err != nil {
 		return nil, errors.Wrap(err, "Error during reading secret")
 	}
 
-	if secret == nil {
-		return nil, ErrSecretNotExists
-	}
-
 	return parseSecret(secretID, secret, true)
 }
 
@@ -495,23 +510,7 @@ func (ss *secretStore) getSecretIDs(orgid uint, query *secretTypes.ListSecretsQu
 		return query.IDs, nil
 	}
 
-	listPath := fmt.Sprintf("secret/metadata/orgs/%d", orgid)
-
-	list, err := ss.Logical.List(listPath)
-	if err != nil {
-		return nil, err
-	}
-
-	if list != nil {
-		keys := cast.ToStringSlice(list.Data["keys"])
-		res := make([]string, len(keys))
-		for i, key := range keys {
-			res[i] = string(key)
-		}
-		return res, nil
-	}
-
-	return nil, nil
+	return ss.backend.List(orgid)
 }
 
 // List secret secret/orgs/:orgid:/ scope
@@ -529,7 +528,7 @@ func (ss *secretStore) List(orgid uint, query *secretTypes.ListSecretsQuery) ([]
 
 	for _, secretID := range secretIDs {
 
-		if secret, err := ss.Logical.Read(secretDataPath(orgid, secretID)); err != nil {
+		if secret, err := ss.backend.Read(orgid, secretID, 0); err != nil && err != ErrSecretNotExists {
 
 			log.Errorf("Error listing secrets: %s", err.Error())
 			return nil, err
@@ -550,22 +549,14 @@ func (ss *secretStore) List(orgid uint, query *secretTypes.ListSecretsQuery) ([]
 	return responseItems, nil
 }
 
-func secretData(version int, request *CreateSecretRequest) (map[string]interface{}, error) {
+func secretData(request *CreateSecretRequest) (map[string]interface{}, error) {
 	valueData := map[string]interface{}{}
 
 	if err := mapstructure.Decode(request, &valueData); err != nil {
 		return nil, errors.Wrap(err, "Error during encoding secret")
 	}
 
-	return vault.NewData(version, map[string]interface{}{"value": valueData}), nil
-}
-
-func secretDataPath(organizationID uint, secretID string) string {
-	return fmt.Sprintf("secret/data/orgs/%d/%s", organizationID, secretID)
-}
-
-func secretMetadataPath(organizationID uint, secretID string) string {
-	return fmt.Sprintf("secret/metadata/orgs/%d/%s", organizationID, secretID)
+	return valueData, nil
 }
 
 func hasTags(tags []string, searchingTag []string) bool {
@@ -595,7 +586,7 @@ func (m MismatchError) Error() string {
 	return m.Err.Error()
 }
 
-// IsCASError detects if the underlying Vault error is caused by a CAS failure
+// IsCASError detects if the underlying backend error is caused by a CAS failure
 func IsCASError(err error) bool {
 	return strings.Contains(err.Error(), "check-and-set parameter did not match the current version")
 }
@@ -662,6 +653,10 @@ func (ss *secretStore) generateValuesIfNeeded(organizationID uint, value *Create
 		}
 
 	} else if value.Type == secretTypes.PKESecretType {
+		if ss.Client == nil {
+			return errors.New("PKE secrets can only be generated when secrets are kept in Vault")
+		}
+
 		clusterID := getClusterIDFromTags(value.Tags)
 		if clusterID == "" {
 			return errors.New("clusterID is missing from the tags")
@@ -0,0 +1,161 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// SecretVersion describes a version of a secret kept by the secret store.
+type SecretVersion struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+	Current   bool      `json:"current,omitempty"`
+
+	// Deleted versions cannot be read or rolled back to
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// SecretDiff describes the changes between two versions of a secret.
+// Only the names of the changed values are listed, their content is available through the versions themselves.
+type SecretDiff struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+
+	AddedKeys   []string `json:"addedKeys"`
+	RemovedKeys []string `json:"removedKeys"`
+	ChangedKeys []string `json:"changedKeys"`
+	AddedTags   []string `json:"addedTags"`
+	RemovedTags []string `json:"removedTags"`
+}
+
+// ListVersions returns the versions of a secret, latest first.
+func (ss *secretStore) ListVersions(organizationID uint, secretID string) ([]SecretVersion, error) {
+	versions, err := ss.backend.Versions(organizationID, secretID)
+	if err == ErrSecretNotExists {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.Wrap(err, "Error during reading secret metadata")
+	}
+
+	// The backends do not know who created a version, it is stored along with the secret
+	for i, version := range versions {
+		if version.Deleted {
+			continue
+		}
+
+		secret, err := ss.GetVersion(organizationID, secretID, version.Version)
+		if err == ErrSecretNotExists {
+			versions[i].Deleted = true
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		versions[i].CreatedBy = secret.UpdatedBy
+	}
+
+	return versions, nil
+}
+
+// GetVersion returns a specific version of a secret.
+func (ss *secretStore) GetVersion(organizationID uint, secretID string, version int) (*SecretItemResponse, error) {
+	secret, err := ss.backend.Read(organizationID, secretID, version)
+	if err == ErrSecretNotExists {
+		return nil, err
+	} else if err != nil {
+		return nil, errors.Wrap(err, "Error during reading secret version")
+	}
+
+	return parseSecret(secretID, secret, true)
+}
+
+// Rollback stores the content of a previous version of a secret as its latest version.
+func (ss *secretStore) Rollback(organizationID uint, secretID string, version int, updatedBy string) error {
+	current, err := ss.Get(organizationID, secretID)
+	if err != nil {
+		return err
+	}
+
+	previous, err := ss.GetVersion(organizationID, secretID, version)
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Rollback secret %s to version %d", secretID, version)
+
+	return ss.Update(organizationID, secretID, &CreateSecretRequest{
+		Name:      previous.Name,
+		Type:      previous.Type,
+		Values:    previous.Values,
+		Tags:      previous.Tags,
+		Version:   &current.Version,
+		UpdatedBy: updatedBy,
+	})
+}
+
+// DiffSecrets compares two versions of a secret.
+func DiffSecrets(from *SecretItemResponse, to *SecretItemResponse) SecretDiff {
+	diff := SecretDiff{
+		From:        from.Version,
+		To:          to.Version,
+		AddedKeys:   []string{},
+		RemovedKeys: []string{},
+		ChangedKeys: []string{},
+	}
+
+	for key, value := range to.Values {
+		if previous, ok := from.Values[key]; !ok {
+			diff.AddedKeys = append(diff.AddedKeys, key)
+		} else if previous != value {
+			diff.ChangedKeys = append(diff.ChangedKeys, key)
+		}
+	}
+
+	for key := range from.Values {
+		if _, ok := to.Values[key]; !ok {
+			diff.RemovedKeys = append(diff.RemovedKeys, key)
+		}
+	}
+
+	sort.Strings(diff.AddedKeys)
+	sort.Strings(diff.RemovedKeys)
+	sort.Strings(diff.ChangedKeys)
+
+	diff.AddedTags = subtractTags(to.Tags, from.Tags)
+	diff.RemovedTags = subtractTags(from.Tags, to.Tags)
+
+	return diff
+}
+
+func subtractTags(tags []string, other []string) []string {
+	otherTags := make(map[string]bool, len(other))
+	for _, tag := range other {
+		otherTags[tag] = true
+	}
+
+	result := []string{}
+	for _, tag := range tags {
+		if !otherTags[tag] {
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
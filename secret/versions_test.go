@@ -0,0 +1,51 @@
+// Copyright © 2019 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/banzaicloud/pipeline/secret"
+)
+
+func TestDiffSecrets(t *testing.T) {
+
+	from := &secret.SecretItemResponse{
+		Version: 1,
+		Values:  map[string]string{"username": "admin", "password": "old", "email": "admin@example.com"},
+		Tags:    []string{"registry", "shared"},
+	}
+
+	to := &secret.SecretItemResponse{
+		Version: 3,
+		Values:  map[string]string{"username": "admin", "password": "new", "server": "registry.example.com"},
+		Tags:    []string{"registry", "team-a"},
+	}
+
+	expected := secret.SecretDiff{
+		From:        1,
+		To:          3,
+		AddedKeys:   []string{"server"},
+		RemovedKeys: []string{"email"},
+		ChangedKeys: []string{"password"},
+		AddedTags:   []string{"team-a"},
+		RemovedTags: []string{"shared"},
+	}
+
+	if diff := secret.DiffSecrets(from, to); !reflect.DeepEqual(diff, expected) {
+		t.Errorf("Expected diff: %+v, but got: %+v", expected, diff)
+	}
+}
@@ -52,6 +52,9 @@ const IconPath = ".banzaicloud/icon.svg"
 const CreateClusterStep = "create_cluster"
 const SpotguideRepoTableName = "spotguide_repos"
 
+// SecretRepoTagPrefix prefixes the tag of the secrets created for a launched spotguide repository
+const SecretRepoTagPrefix = "repo:"
+
 var IgnoredPaths = []string{".circleci", ".github"} // nolint: gochecknoglobals
 
 type SpotguideYAML struct {
@@ -479,7 +482,7 @@ func getSpotguideContent(sourceSCM scm.SCM, request *LaunchRequest, sourceRepo *
 
 func createSecrets(request *LaunchRequest, orgID uint, userID uint) error {
 
-	repoTag := "repo:" + request.RepoFullname()
+	repoTag := SecretRepoTagPrefix + request.RepoFullname()
 
 	for _, secretRequest := range request.Secrets {
 